@@ -3,77 +3,425 @@
 package config
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/melbahja/goph"
+	"golang.org/x/crypto/ssh"
 )
 
 type Settings struct {
 	SshUsername string
 	SshPassword string
 	SshHostname string
+	SshPort     uint
 	DnsServer   string
 	Version     string
+
+	// SshFailoverHostnames are tried in order when no SSH connection can be established to SshHostname.
+	SshFailoverHostnames []string
+
+	// SshProxyJump lists the hosts to tunnel through, in order, to reach SshHostname.
+	SshProxyJump []SSHHop
+	// SshHostKey is the expected host key of SshHostname, in authorized_keys format.
+	SshHostKey string
+	// SshKnownHostsFile is used to verify host keys not given by SshHostKey.
+	// The user's default known_hosts file is used when empty.
+	SshKnownHostsFile string
+	// SshInsecure disables host key verification.
+	SshInsecure bool
+	// SshUseAgent offers the keys of the SSH agent listening on SSH_AUTH_SOCK when authenticating, before SshPassword.
+	SshUseAgent bool
+
+	// SshConnectTimeout is the time to wait for the connection to each SSH host to be established.
+	SshConnectTimeout time.Duration
+	// SshKeepaliveInterval is the time between keepalive requests on idle SSH connections. Zero disables keepalives.
+	SshKeepaliveInterval time.Duration
+	// SshKeepaliveMaxMissed is the number of keepalive requests in a row going unanswered before the connection is
+	// closed and replaced.
+	SshKeepaliveMaxMissed int
+
+	SkipCreatePrecheck bool
+
+	// PowerShellPath is the PowerShell executable running the commands on the SSH host.
+	PowerShellPath string
+
+	// BusyRetries is the number of times a command failing because the DNS server is busy is run again.
+	BusyRetries int
+	// BusyRetryDelay is the time to wait before running such a command again.
+	BusyRetryDelay time.Duration
+
+	// PostWriteDelay is the time to wait after records are written, giving AD replication and resolver caches time
+	// to pick up the change before checks run after the apply.
+	PostWriteDelay time.Duration
+
+	// MaxConcurrentOperations caps the number of remote commands running at once. Zero means no limit.
+	MaxConcurrentOperations int
+
+	// NamePrefix and NameSuffix are added to the names of records on the server, and stripped from the names read.
+	NamePrefix string
+	NameSuffix string
+
+	// DefaultZone is the zone of records not given a zone_name.
+	DefaultZone string
+
+	// VerifySerial is how a zone serial number that did not advance after changing records is reported, either
+	// "warn" or "error". The serial number is not verified when empty.
+	VerifySerial string
+
+	// DestroyProtectionThreshold is the maximum number of windns_record resources destroyed in one run, unless
+	// DestroyProtectionOverride is set. Zero means no limit.
+	DestroyProtectionThreshold int
+	DestroyProtectionOverride  bool
+}
+
+// requiredSettings lists the provider attributes that must be set, either in the provider block or with their
+// environment variable.
+var requiredSettings = []struct {
+	attribute string
+	envVar    string
+	// unless is a boolean attribute that makes the attribute optional when set.
+	unless string
+}{
+	{"ssh_username", "WINDNS_SSH_USERNAME", ""},
+	{"ssh_password", "WINDNS_SSH_PASSWORD", "ssh_use_agent"},
+	{"ssh_hostname", "WINDNS_SSH_HOSTNAME", ""},
 }
 
 func NewConfig(d *schema.ResourceData) (*Settings, error) {
+	var missing []string
+	for _, s := range requiredSettings {
+		if s.unless != "" && d.Get(s.unless).(bool) {
+			continue
+		}
+		if d.Get(s.attribute).(string) == "" {
+			missing = append(missing, fmt.Sprintf("%s (environment variable %s)", s.attribute, s.envVar))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing provider configuration: %s must be set in the provider block or with the environment variable", strings.Join(missing, ", "))
+	}
+
 	sshUsername := d.Get("ssh_username").(string)
 	sshPassword := d.Get("ssh_password").(string)
 	sshHost := d.Get("ssh_hostname").(string)
 	dnsServer := d.Get("dns_server").(string)
+	skipCreatePrecheck := d.Get("skip_create_precheck").(bool)
+
+	proxyJump, err := ParseProxyJump(d.Get("ssh_proxy_jump").(string), sshUsername)
+	if err != nil {
+		return nil, err
+	}
 
 	cfg := &Settings{
-		SshHostname: sshHost,
-		SshUsername: sshUsername,
-		SshPassword: sshPassword,
-		DnsServer:   dnsServer,
+		SshHostKey:                 d.Get("ssh_host_key").(string),
+		SshKnownHostsFile:          d.Get("ssh_known_hosts_file").(string),
+		SshInsecure:                d.Get("ssh_insecure").(bool),
+		SshUseAgent:                d.Get("ssh_use_agent").(bool),
+		SshConnectTimeout:          time.Duration(d.Get("ssh_connect_timeout").(int)) * time.Second,
+		SshKeepaliveInterval:       time.Duration(d.Get("ssh_keepalive_interval").(int)) * time.Second,
+		SshKeepaliveMaxMissed:      d.Get("ssh_keepalive_max_missed").(int),
+		SshHostname:                sshHost,
+		SshFailoverHostnames:       parseHostnames(d.Get("ssh_failover_hostnames").(string)),
+		SshPort:                    uint(d.Get("ssh_port").(int)),
+		SshUsername:                sshUsername,
+		SshPassword:                sshPassword,
+		DnsServer:                  dnsServer,
+		SshProxyJump:               proxyJump,
+		SkipCreatePrecheck:         skipCreatePrecheck,
+		PowerShellPath:             d.Get("powershell_path").(string),
+		BusyRetries:                d.Get("busy_retries").(int),
+		BusyRetryDelay:             time.Duration(d.Get("busy_retry_delay").(int)) * time.Second,
+		MaxConcurrentOperations:    d.Get("max_concurrent_operations").(int),
+		PostWriteDelay:             time.Duration(d.Get("post_write_delay").(int)) * time.Second,
+		NamePrefix:                 d.Get("name_prefix").(string),
+		NameSuffix:                 d.Get("name_suffix").(string),
+		DefaultZone:                d.Get("default_zone").(string),
+		VerifySerial:               d.Get("verify_serial").(string),
+		DestroyProtectionThreshold: d.Get("destroy_protection_threshold").(int),
+		DestroyProtectionOverride:  d.Get("destroy_protection_override").(bool),
 	}
 
 	return cfg, nil
 }
 
-func GetSSHConnection(settings *Settings) (*goph.Client, error) {
-	auth := goph.Password(settings.SshPassword)
-	client, err := goph.NewUnknown(settings.SshUsername, settings.SshHostname, auth)
+// parseHostnames parses a comma separated list of hostnames.
+func parseHostnames(hostnames string) []string {
+	var parsed []string
+	for _, v := range strings.Split(hostnames, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			parsed = append(parsed, v)
+		}
+	}
+	return parsed
+}
+
+// GetSSHConnection connects to SshHostname, failing over to each of SshFailoverHostnames in order when the
+// connection cannot be established. The error lists why each of the hosts failed.
+func GetSSHConnection(ctx context.Context, settings *Settings) (*SSHClient, error) {
+	hostnames := append([]string{settings.SshHostname}, settings.SshFailoverHostnames...)
+	var errs []error
+	for _, hostname := range hostnames {
+		client, err := getSSHConnectionTo(ctx, settings, hostname)
+		if err == nil {
+			if hostname != settings.SshHostname {
+				tflog.Warn(ctx, "Failed over to another SSH host", map[string]any{"ssh_hostname": hostname, "failed_hosts": len(errs)})
+			}
+			return client, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 1 {
+		return nil, errs[0]
+	}
+	return nil, errors.Join(errs...)
+}
+
+// getSSHConnectionTo connects to hostname, through the jump hosts of settings.
+func getSSHConnectionTo(ctx context.Context, settings *Settings, hostname string) (*SSHClient, error) {
+	fields := map[string]any{
+		"ssh_hostname": hostname,
+		"ssh_port":     settings.SshPort,
+		"ssh_username": settings.SshUsername,
+	}
+
+	if len(settings.SshProxyJump) > 0 {
+		fields["ssh_proxy_jump"] = fmt.Sprintf("%s", settings.SshProxyJump)
+	}
+	tflog.Debug(ctx, "Establishing SSH connection", fields)
+
+	hops := append([]SSHHop{}, settings.SshProxyJump...)
+	hops = append(hops, SSHHop{User: settings.SshUsername, Host: hostname, Port: settings.SshPort})
+
+	auth, agentConn, err := settings.sshAuth()
 	if err != nil {
 		return nil, err
 	}
+	if agentConn != nil {
+		// The agent signs during the handshakes only, so it is not needed once connected.
+		defer agentConn.Close()
+	}
+	timeout := settings.SshConnectTimeout
+	if timeout == 0 {
+		timeout = goph.DefaultTimeout
+	}
+	client, err := dialHops(hops, auth, timeout, settings.hostKeyCallback)
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Debug(ctx, "Failed to establish SSH connection", fields)
+		return nil, err
+	}
 
+	tflog.Info(ctx, "Established SSH connection", fields)
 	return client, err
 }
 
+// hostKeyCallback returns the callback used to verify the host key of hop.
+func (s *Settings) hostKeyCallback(hop SSHHop) (ssh.HostKeyCallback, error) {
+	if s.SshInsecure {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	if s.SshHostKey != "" && hop.Host == s.SshHostname {
+		return fixedHostKeyCallback(s.SshHostKey)
+	}
+	return knownHostsCallback(s.SshKnownHostsFile)
+}
+
 type ProviderConf struct {
-	Settings   *Settings
-	sshClients []*goph.Client
-	mx         *sync.Mutex
+	Settings *Settings
+	// Executor runs the powershell commands, over SSH unless replaced, e.g. in tests.
+	Executor CommandExecutor
+
+	sshClients []*SSHClient
+	// sshClosed holds a channel for every SSH connection, closed once the connection is, e.g. after keepalives went
+	// unanswered.
+	sshClosed map[*SSHClient]chan struct{}
+	mx        *sync.Mutex
+
+	// zoneNames caches the names of the zones on the DNS server for the lifetime of the provider.
+	zoneNames map[string]bool
+	zonesMx   *sync.Mutex
+
+	// operations holds a token for every remote command running, when their number is capped.
+	operations chan struct{}
+
+	// destroys holds the windns_record resources planned to be destroyed in the run, see CountDestroy.
+	destroys *destroyCounter
+
+	// dnsServers caches the configurations returned by ForDnsServer, by DNS server.
+	dnsServers   map[string]*ProviderConf
+	dnsServersMx *sync.Mutex
 }
 
 func NewProviderConf(settings *Settings) *ProviderConf {
 	pcfg := &ProviderConf{
-		Settings:   settings,
-		sshClients: make([]*goph.Client, 0),
-		mx:         &sync.Mutex{},
+		Settings:     settings,
+		sshClients:   make([]*SSHClient, 0),
+		sshClosed:    make(map[*SSHClient]chan struct{}),
+		mx:           &sync.Mutex{},
+		zonesMx:      &sync.Mutex{},
+		dnsServersMx: &sync.Mutex{},
+		destroys:     &destroyCounter{planned: make(map[string]bool)},
+	}
+	if settings.MaxConcurrentOperations > 0 {
+		pcfg.operations = make(chan struct{}, settings.MaxConcurrentOperations)
 	}
+	pcfg.Executor = &sshExecutor{conf: pcfg}
 	return pcfg
 }
 
-func (c *ProviderConf) AcquireSshClient() (client *goph.Client, err error) {
+// ForDnsServer returns the configuration running commands against dnsServer rather than the provider's DnsServer, for
+// resources overriding the DNS server. The configuration runs commands with the executor of c, and so over its SSH
+// connections, and shares its cap on concurrent commands, but caches the zone names of dnsServer separately. An empty
+// dnsServer returns c.
+func (c *ProviderConf) ForDnsServer(dnsServer string) *ProviderConf {
+	if dnsServer == "" || dnsServer == c.Settings.DnsServer {
+		return c
+	}
+	c.dnsServersMx.Lock()
+	defer c.dnsServersMx.Unlock()
+	if conf, ok := c.dnsServers[dnsServer]; ok {
+		return conf
+	}
+
+	settings := *c.Settings
+	settings.DnsServer = dnsServer
+	conf := &ProviderConf{
+		Settings:     &settings,
+		Executor:     c.Executor,
+		zonesMx:      &sync.Mutex{},
+		operations:   c.operations,
+		destroys:     c.destroys,
+		dnsServersMx: &sync.Mutex{},
+	}
+	if c.dnsServers == nil {
+		c.dnsServers = make(map[string]*ProviderConf)
+	}
+	c.dnsServers[dnsServer] = conf
+	return conf
+}
+
+// destroyCounter holds the resources planned to be destroyed, by key.
+type destroyCounter struct {
+	mx      sync.Mutex
+	planned map[string]bool
+}
+
+// CountDestroy counts a windns_record resource, identified by key, planned to be destroyed or replaced, failing once
+// more resources than DestroyProtectionThreshold are planned to be destroyed in the run of the provider, unless
+// DestroyProtectionOverride is set. A resource planned more than once, as Terraform does for replacements, is counted
+// once.
+func (c *ProviderConf) CountDestroy(key string) error {
+	threshold := c.Settings.DestroyProtectionThreshold
+	if threshold == 0 || c.Settings.DestroyProtectionOverride {
+		return nil
+	}
+	c.destroys.mx.Lock()
+	defer c.destroys.mx.Unlock()
+	c.destroys.planned[key] = true
+	if len(c.destroys.planned) > threshold {
+		return fmt.Errorf("destroying more than %d windns_record resources in one run is blocked by destroy_protection_threshold. Review the plan, and set destroy_protection_override to destroy them", threshold)
+	}
+	return nil
+}
+
+// AcquireOperation waits until another remote command may run, and must be paired with ReleaseOperation.
+// Commands beyond MaxConcurrentOperations queue until a running command finishes or ctx is done.
+func (c *ProviderConf) AcquireOperation(ctx context.Context) error {
+	if c.operations == nil {
+		return nil
+	}
+	select {
+	case c.operations <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("while waiting for other commands to finish: %s", ctx.Err())
+	}
+}
+
+// ReleaseOperation marks a remote command started with AcquireOperation as finished.
+func (c *ProviderConf) ReleaseOperation() {
+	if c.operations == nil {
+		return
+	}
+	<-c.operations
+}
+
+// ZoneNames returns the lower cased names of the zones on the DNS server. The names are loaded
+// with load on first use, and cached so that planning many records only lists the zones once.
+func (c *ProviderConf) ZoneNames(load func() ([]string, error)) (map[string]bool, error) {
+	c.zonesMx.Lock()
+	defer c.zonesMx.Unlock()
+	if c.zoneNames != nil {
+		return c.zoneNames, nil
+	}
+
+	names, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.zoneNames = make(map[string]bool, len(names))
+	for _, name := range names {
+		c.zoneNames[strings.ToLower(strings.TrimSuffix(name, "."))] = true
+	}
+	return c.zoneNames, nil
+}
+
+// ForgetZoneNames drops the zone names cached by ZoneNames, so that they are listed again after a zone was added or
+// removed.
+func (c *ProviderConf) ForgetZoneNames() {
+	c.zonesMx.Lock()
+	defer c.zonesMx.Unlock()
+	c.zoneNames = nil
+}
+
+// AcquireSshClient returns a pooled SSH connection, or a new one when none is pooled. Pooled connections that were
+// closed, e.g. after keepalives went unanswered, are dropped.
+func (c *ProviderConf) AcquireSshClient(ctx context.Context) (client *SSHClient, err error) {
 	c.mx.Lock()
 	defer c.mx.Unlock()
-	if len(c.sshClients) == 0 {
-		client, err = GetSSHConnection(c.Settings)
-		if err != nil {
-			return nil, err
-		}
-	} else {
+	for len(c.sshClients) > 0 {
 		client = c.sshClients[0]
 		c.sshClients = c.sshClients[1:]
+		select {
+		case <-c.sshClosed[client]:
+			delete(c.sshClosed, client)
+			tflog.Debug(ctx, "Dropping closed SSH connection")
+		default:
+			return client, nil
+		}
 	}
+
+	client, err = GetSSHConnection(ctx, c.Settings)
+	if err != nil {
+		return nil, err
+	}
+	c.watchSshClient(client)
 	return client, nil
 }
 
-func (c *ProviderConf) ReleaseSshClient(client *goph.Client) {
+// watchSshClient tracks when the connection of client is closed, sending keepalives over it when enabled. The
+// connections to the jump hosts of a closed connection are closed along with it.
+func (c *ProviderConf) watchSshClient(client *SSHClient) {
+	closed := make(chan struct{})
+	c.sshClosed[client] = closed
+	go func() {
+		_ = client.Wait()
+		_ = client.Close()
+		close(closed)
+	}()
+	if c.Settings.SshKeepaliveInterval > 0 && c.Settings.SshKeepaliveMaxMissed > 0 {
+		go keepAlive(client.Client.Client, c.Settings.SshKeepaliveInterval, c.Settings.SshKeepaliveMaxMissed, closed)
+	}
+}
+
+func (c *ProviderConf) ReleaseSshClient(client *SSHClient) {
 	c.mx.Lock()
 	defer c.mx.Unlock()
 	c.sshClients = append(c.sshClients, client)
@@ -0,0 +1,313 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func TestProviderConf_ZoneNames(t *testing.T) {
+	conf := NewProviderConf(&Settings{})
+
+	_, err := conf.ZoneNames(func() ([]string, error) {
+		return nil, errors.New("connection refused")
+	})
+	if err == nil {
+		t.Fatalf("ZoneNames() did not return the error of load")
+	}
+
+	loads := 0
+	load := func() ([]string, error) {
+		loads++
+		return []string{"Example.com", "10.10.in-addr.arpa."}, nil
+	}
+	for i := 0; i < 2; i++ {
+		names, err := conf.ZoneNames(load)
+		if err != nil {
+			t.Fatalf("ZoneNames() error = %s", err)
+		}
+		if !names["example.com"] || !names["10.10.in-addr.arpa"] {
+			t.Errorf("ZoneNames() = %v, want example.com and 10.10.in-addr.arpa", names)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("load called %d times, want 1", loads)
+	}
+
+	conf.ForgetZoneNames()
+	if _, err := conf.ZoneNames(load); err != nil {
+		t.Fatalf("ZoneNames() error = %s", err)
+	}
+	if loads != 2 {
+		t.Errorf("load called %d times after ForgetZoneNames(), want 2", loads)
+	}
+}
+
+func TestProviderConf_AcquireOperation(t *testing.T) {
+	conf := NewProviderConf(&Settings{MaxConcurrentOperations: 1})
+
+	if err := conf.AcquireOperation(context.Background()); err != nil {
+		t.Fatalf("AcquireOperation() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := conf.AcquireOperation(ctx); err == nil {
+		t.Fatalf("AcquireOperation() beyond the limit did not wait")
+	}
+
+	conf.ReleaseOperation()
+	if err := conf.AcquireOperation(context.Background()); err != nil {
+		t.Fatalf("AcquireOperation() after ReleaseOperation() error = %v", err)
+	}
+	conf.ReleaseOperation()
+
+	unlimited := NewProviderConf(&Settings{})
+	for i := 0; i < 3; i++ {
+		if err := unlimited.AcquireOperation(ctx); err != nil {
+			t.Fatalf("AcquireOperation() without a limit error = %v", err)
+		}
+	}
+}
+
+func TestProviderConf_ForDnsServer(t *testing.T) {
+	conf := NewProviderConf(&Settings{DnsServer: "dc01.example.com", MaxConcurrentOperations: 1})
+
+	if got := conf.ForDnsServer(""); got != conf {
+		t.Errorf("ForDnsServer(\"\") did not return the provider configuration")
+	}
+	if got := conf.ForDnsServer("dc01.example.com"); got != conf {
+		t.Errorf("ForDnsServer() of the provider's DNS server did not return the provider configuration")
+	}
+
+	dc02 := conf.ForDnsServer("dc02.example.com")
+	if dc02.Settings.DnsServer != "dc02.example.com" || conf.Settings.DnsServer != "dc01.example.com" {
+		t.Errorf("ForDnsServer() DnsServer = %q, provider DnsServer = %q", dc02.Settings.DnsServer, conf.Settings.DnsServer)
+	}
+	if dc02.Executor != conf.Executor {
+		t.Errorf("ForDnsServer() does not share the executor of the provider configuration")
+	}
+	if got := conf.ForDnsServer("dc02.example.com"); got != dc02 {
+		t.Errorf("ForDnsServer() did not cache the configuration")
+	}
+
+	// The cap on concurrent commands is shared.
+	if err := conf.AcquireOperation(context.Background()); err != nil {
+		t.Fatalf("AcquireOperation() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := dc02.AcquireOperation(ctx); err == nil {
+		t.Errorf("AcquireOperation() beyond the limit of the provider did not wait")
+	}
+	conf.ReleaseOperation()
+
+	// The zones of each server are cached separately.
+	_, _ = conf.ZoneNames(func() ([]string, error) { return []string{"example.com"}, nil })
+	names, _ := dc02.ZoneNames(func() ([]string, error) { return []string{"branch.example.com"}, nil })
+	if names["example.com"] || !names["branch.example.com"] {
+		t.Errorf("ZoneNames() = %v, want only branch.example.com", names)
+	}
+}
+
+func TestProviderConf_CountDestroy(t *testing.T) {
+	tests := []struct {
+		name      string
+		settings  Settings
+		destroys  int
+		resources int
+		wantFails int
+	}{
+		{"test-no-limit", Settings{}, 5, 5, 0},
+		{"test-within-threshold", Settings{DestroyProtectionThreshold: 3}, 3, 3, 0},
+		{"test-above-threshold", Settings{DestroyProtectionThreshold: 3}, 5, 5, 2},
+		{"test-planned-twice", Settings{DestroyProtectionThreshold: 3}, 6, 3, 0},
+		{"test-override", Settings{DestroyProtectionThreshold: 3, DestroyProtectionOverride: true}, 5, 5, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := tt.settings
+			conf := NewProviderConf(&settings)
+			fails := 0
+			for i := 0; i < tt.destroys; i++ {
+				// Resources on other DNS servers count towards the same threshold.
+				c := conf
+				if i%2 == 1 {
+					c = conf.ForDnsServer("dc02.example.com")
+				}
+				if err := c.CountDestroy(fmt.Sprintf("record-%d", i%tt.resources)); err != nil {
+					fails++
+				}
+			}
+			if fails != tt.wantFails {
+				t.Errorf("CountDestroy() failed %d times, want %d", fails, tt.wantFails)
+			}
+		})
+	}
+}
+
+func TestParseHostnames(t *testing.T) {
+	tests := []struct {
+		name      string
+		hostnames string
+		want      []string
+	}{
+		{"test-empty", "", nil},
+		{"test-single", "dc02.example.com", []string{"dc02.example.com"}},
+		{"test-multiple", " dc02.example.com, dc03.example.com ,", []string{"dc02.example.com", "dc03.example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseHostnames(tt.hostnames); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseHostnames() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSSHConnection_Failover(t *testing.T) {
+	// Nothing listens on the port once the listener is closed, so every connection is refused.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %s", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	settings := &Settings{
+		SshUsername:          "user",
+		SshHostname:          "127.0.0.1",
+		SshFailoverHostnames: []string{"localhost"},
+		SshPort:              uint(port),
+		SshInsecure:          true,
+	}
+	_, err = GetSSHConnection(context.Background(), settings)
+	if err == nil {
+		t.Fatalf("GetSSHConnection() returned no error")
+	}
+	for _, hostname := range []string{"127.0.0.1", "localhost"} {
+		if !strings.Contains(err.Error(), "user@"+net.JoinHostPort(hostname, strconv.Itoa(port))) {
+			t.Errorf("GetSSHConnection() error = %q, want the failure of %s", err, hostname)
+		}
+	}
+}
+
+// newLocalAgent serves an SSH agent holding key on a unix socket, and points SSH_AUTH_SOCK at it.
+func newLocalAgent(t *testing.T, key ed25519.PrivateKey) {
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+		t.Fatal(err)
+	}
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("net.Listen() error = %s", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = agent.ServeAgent(keyring, conn)
+			}()
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", socket)
+}
+
+func TestGetSSHConnection_Agent(t *testing.T) {
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userPub, userKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorized, err := ssh.NewPublicKey(userPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The server accepts the key held by the agent only, not passwords.
+	serverConfig := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(authorized.Marshal()) {
+				return nil, errors.New("unknown key")
+			}
+			return nil, nil
+		},
+	}
+	serverConfig.AddHostKey(hostSigner)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %s", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			serverConn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				conn, chans, reqs, err := ssh.NewServerConn(serverConn, serverConfig)
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				go ssh.DiscardRequests(reqs)
+				for ch := range chans {
+					_ = ch.Reject(ssh.Prohibited, "no channels")
+				}
+			}()
+		}
+	}()
+
+	settings := &Settings{
+		SshUsername: "user",
+		SshHostname: "127.0.0.1",
+		SshPort:     uint(listener.Addr().(*net.TCPAddr).Port),
+		SshInsecure: true,
+	}
+	if _, err := GetSSHConnection(context.Background(), settings); err == nil {
+		t.Fatalf("GetSSHConnection() without ssh_use_agent returned no error")
+	}
+
+	settings.SshUseAgent = true
+	t.Setenv("SSH_AUTH_SOCK", "")
+	if _, err := GetSSHConnection(context.Background(), settings); err == nil || !strings.Contains(err.Error(), "SSH_AUTH_SOCK") {
+		t.Fatalf("GetSSHConnection() without an agent error = %v, want an error naming SSH_AUTH_SOCK", err)
+	}
+
+	newLocalAgent(t, userKey)
+	client, err := GetSSHConnection(context.Background(), settings)
+	if err != nil {
+		t.Fatalf("GetSSHConnection() with ssh_use_agent error = %s", err)
+	}
+	_ = client.Close()
+}
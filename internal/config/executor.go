@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// CommandOutput holds the output and exit code of a command run by a CommandExecutor.
+type CommandOutput struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// CommandExecutor runs command lines on the host running the powershell commands. The provider runs them over SSH,
+// while tests may replace the executor of a ProviderConf to run commands without a server.
+type CommandExecutor interface {
+	// Execute runs command with stdin as its input, interrupting it when ctx is done. A command that runs but exits
+	// with a non zero exit code is not an error.
+	Execute(ctx context.Context, command, stdin string) (*CommandOutput, error)
+}
+
+// sshExecutor runs commands on ssh_hostname, with the pooled SSH connections of conf.
+type sshExecutor struct {
+	conf *ProviderConf
+}
+
+func (e *sshExecutor) Execute(ctx context.Context, command, stdin string) (*CommandOutput, error) {
+	conn, err := e.conf.AcquireSshClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("while acquiring ssh client: %s", err)
+	}
+	defer e.conf.ReleaseSshClient(conn)
+
+	// The command is interrupted when ctx is done, e.g. when the resource timeout expires.
+	cmd, err := conn.CommandContext(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Session.Stderr = &stderr
+	cmd.Session.Stdout = &stdout
+	if stdin != "" {
+		cmd.Session.Stdin = strings.NewReader(stdin)
+	}
+
+	output := &CommandOutput{}
+	err = cmd.Run()
+	if err != nil {
+		v, ok := err.(*ssh.ExitError)
+		if !ok {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("command did not finish in time: %s", ctx.Err())
+			}
+			return nil, fmt.Errorf("run error: %s", err)
+		}
+		output.ExitCode = v.ExitStatus()
+	}
+	output.Stdout = stdout.String()
+	output.Stderr = stderr.String()
+	return output, nil
+}
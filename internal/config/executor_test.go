@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// newLocalHangingHost starts an SSH server on the loopback interface that accepts commands but never finishes them,
+// and returns its port.
+func newLocalHangingHost(t *testing.T) uint {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConfig := &ssh.ServerConfig{NoClientAuth: true}
+	serverConfig.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %s", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			serverConn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				conn, chans, reqs, err := ssh.NewServerConn(serverConn, serverConfig)
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				go ssh.DiscardRequests(reqs)
+				for ch := range chans {
+					if ch.ChannelType() != "session" {
+						_ = ch.Reject(ssh.UnknownChannelType, "unsupported channel")
+						continue
+					}
+					channel, requests, err := ch.Accept()
+					if err != nil {
+						continue
+					}
+					// The command is started, but no exit status is ever sent.
+					go func() {
+						defer channel.Close()
+						for req := range requests {
+							_ = req.Reply(req.Type == "exec", nil)
+						}
+					}()
+				}
+			}()
+		}
+	}()
+	return uint(listener.Addr().(*net.TCPAddr).Port)
+}
+
+func TestSSHExecutor_Timeout(t *testing.T) {
+	conf := NewProviderConf(&Settings{
+		SshUsername: "user",
+		SshHostname: "127.0.0.1",
+		SshPort:     newLocalHangingHost(t),
+		SshInsecure: true,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := conf.Executor.Execute(ctx, "Get-DnsServerZone", "")
+	if err == nil || !strings.Contains(err.Error(), "did not finish in time") {
+		t.Fatalf("Execute() error = %v, want a command that did not finish in time", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Execute() returned after %s, want it interrupted when the context expired", elapsed)
+	}
+}
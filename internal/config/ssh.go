@@ -0,0 +1,291 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/melbahja/goph"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const defaultSSHPort = 22
+
+// keepAliveRequest is the global request OpenSSH sends as keepalive. Servers not knowing it reply with a failure, which
+// still shows the connection is alive.
+const keepAliveRequest = "keepalive@openssh.com"
+
+// SSHHop is a host the SSH connection passes through on its way to the server running the powershell commands.
+type SSHHop struct {
+	User string
+	Host string
+	Port uint
+}
+
+func (h SSHHop) String() string {
+	return fmt.Sprintf("%s@%s", h.User, net.JoinHostPort(h.Host, strconv.FormatUint(uint64(h.Port), 10)))
+}
+
+// ParseProxyJump parses a comma separated list of [user@]host[:port] hops, like OpenSSH's ProxyJump option.
+// Hops without a user use defaultUser.
+func ParseProxyJump(proxyJump, defaultUser string) ([]SSHHop, error) {
+	var hops []SSHHop
+	for _, v := range strings.Split(proxyJump, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		hop := SSHHop{User: defaultUser, Port: defaultSSHPort}
+		if idx := strings.LastIndex(v, "@"); idx != -1 {
+			hop.User = v[:idx]
+			v = v[idx+1:]
+		}
+
+		if strings.HasPrefix(v, "[") || strings.Count(v, ":") == 1 {
+			host, port, err := net.SplitHostPort(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid jump host %q: %s", v, err)
+			}
+			p, err := strconv.ParseUint(port, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("invalid port in jump host %q: %s", v, err)
+			}
+			hop.Host = host
+			hop.Port = uint(p)
+		} else {
+			hop.Host = v
+		}
+
+		if hop.Host == "" || hop.User == "" {
+			return nil, fmt.Errorf("invalid jump host %q", v)
+		}
+		hops = append(hops, hop)
+	}
+	return hops, nil
+}
+
+// SSHClient is an SSH connection to the host running the powershell commands, along with the connections to the jump
+// hosts it is tunneled through.
+type SSHClient struct {
+	*goph.Client
+	// jumps are the connections to the jump hosts, in the order they were established.
+	jumps []*goph.Client
+}
+
+// Close closes the connection, and then the connections to the jump hosts in reverse order.
+func (c *SSHClient) Close() error {
+	err := c.Client.Close()
+	for i := len(c.jumps) - 1; i >= 0; i-- {
+		_ = c.jumps[i].Close()
+	}
+	return err
+}
+
+// dialHops connects to the last of hops, tunneling through each of the preceding hops in order.
+// The host key of each hop is verified with the callback returned by hostKeyCallback.
+// Connecting to each hop times out after timeout. When a hop cannot be reached, the connections to the hops before it
+// are closed.
+func dialHops(hops []SSHHop, auth goph.Auth, timeout time.Duration, hostKeyCallback func(SSHHop) (ssh.HostKeyCallback, error)) (*SSHClient, error) {
+	var clients []*goph.Client
+	fail := func(err error) (*SSHClient, error) {
+		for i := len(clients) - 1; i >= 0; i-- {
+			_ = clients[i].Close()
+		}
+		return nil, err
+	}
+	for _, hop := range hops {
+		callback, err := hostKeyCallback(hop)
+		if err != nil {
+			return fail(err)
+		}
+
+		cfg := &goph.Config{
+			User:     hop.User,
+			Addr:     hop.Host,
+			Port:     hop.Port,
+			Auth:     auth,
+			Timeout:  timeout,
+			Callback: callback,
+		}
+
+		var c *goph.Client
+		if len(clients) == 0 {
+			c, err = goph.NewConn(cfg)
+		} else {
+			c, err = dialThrough(clients[len(clients)-1], cfg)
+		}
+		if err != nil {
+			return fail(fmt.Errorf("while connecting to %s: %s", hop, err))
+		}
+		clients = append(clients, c)
+	}
+	if len(clients) == 0 {
+		return nil, errors.New("no SSH host to connect to")
+	}
+	return &SSHClient{Client: clients[len(clients)-1], jumps: clients[:len(clients)-1]}, nil
+}
+
+// dialThrough establishes an SSH connection described by cfg, tunneled through client.
+func dialThrough(client *goph.Client, cfg *goph.Config) (*goph.Client, error) {
+	addr := net.JoinHostPort(cfg.Addr, strconv.FormatUint(uint64(cfg.Port), 10))
+	conn, err := client.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	clientConn, chans, reqs, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		User:            cfg.User,
+		Auth:            cfg.Auth,
+		Timeout:         cfg.Timeout,
+		HostKeyCallback: cfg.Callback,
+		BannerCallback:  cfg.BannerCallback,
+	})
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return &goph.Client{
+		Client: ssh.NewClient(clientConn, chans, reqs),
+		Config: cfg,
+	}, nil
+}
+
+// sshAuth returns the methods used to authenticate to every hop: the keys of the SSH agent when SshUseAgent is set,
+// then SshPassword when given. The connection to the agent is returned too, to be closed once connected.
+func (s *Settings) sshAuth() (goph.Auth, io.Closer, error) {
+	var auth goph.Auth
+	var agentConn io.Closer
+	if s.SshUseAgent {
+		socket := os.Getenv("SSH_AUTH_SOCK")
+		if socket == "" {
+			return nil, nil, errors.New("ssh_use_agent is set, but no SSH agent is running: SSH_AUTH_SOCK is not set")
+		}
+		conn, err := net.Dial("unix", socket)
+		if err != nil {
+			return nil, nil, fmt.Errorf("while connecting to the SSH agent at %s: %s", socket, err)
+		}
+		auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		agentConn = conn
+	}
+	if s.SshPassword != "" || !s.SshUseAgent {
+		auth = append(auth, ssh.Password(s.SshPassword))
+	}
+	return auth, agentConn, nil
+}
+
+// fixedHostKeyCallback accepts only the given host key, in authorized_keys format.
+func fixedHostKeyCallback(hostKey string) (ssh.HostKeyCallback, error) {
+	expected, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ssh_host_key: %s", err)
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		if !bytes.Equal(key.Marshal(), expected.Marshal()) {
+			return fmt.Errorf("host key mismatch for %s: expected %s key %s, but the server presented %s key %s",
+				hostname, expected.Type(), ssh.FingerprintSHA256(expected), key.Type(), ssh.FingerprintSHA256(key))
+		}
+		return nil
+	}, nil
+}
+
+// knownHostsCallback verifies host keys against the known_hosts file at path,
+// or the user's default known_hosts file if path is empty.
+func knownHostsCallback(path string) (ssh.HostKeyCallback, error) {
+	if path == "" {
+		defaultPath, err := goph.DefaultKnownHostsPath()
+		if err != nil {
+			return nil, fmt.Errorf("while locating known_hosts file: %s", err)
+		}
+		path = defaultPath
+	}
+
+	callback, err := knownhosts.New(path)
+	if err != nil {
+		return nil, fmt.Errorf("while reading known_hosts file: %s", err)
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := callback(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) {
+			if len(keyErr.Want) == 0 {
+				return fmt.Errorf("host key verification failed for %s: no %s key found in %s (server presented %s)",
+					hostname, key.Type(), path, ssh.FingerprintSHA256(key))
+			}
+			return fmt.Errorf("host key mismatch for %s: the server presented %s key %s, which does not match the key in %s",
+				hostname, key.Type(), ssh.FingerprintSHA256(key), path)
+		}
+		return err
+	}, nil
+}
+
+// SSHErrorReason returns a short description of why establishing an SSH connection failed with err.
+func SSHErrorReason(err error) string {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "unable to authenticate"):
+		return "authentication failed"
+	case strings.Contains(msg, "host key"):
+		return "host key verification failed"
+	default:
+		return "connection failed"
+	}
+}
+
+// keepAlive sends a keepalive request over client every interval until closed is closed, closing client once maxMissed
+// requests in a row were not answered within interval. Idle connections are then not dropped by firewalls, and a
+// connection that was dropped anyway is closed, to be replaced, rather than failing the next command.
+func keepAlive(client *ssh.Client, interval time.Duration, maxMissed int, closed <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	missed := 0
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+		}
+
+		if sendKeepAlive(client, interval) {
+			missed = 0
+			continue
+		}
+		missed++
+		if missed >= maxMissed {
+			_ = client.Close()
+			return
+		}
+	}
+}
+
+// sendKeepAlive reports whether client got a reply to a keepalive request within timeout.
+func sendKeepAlive(client *ssh.Client, timeout time.Duration) bool {
+	reply := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest(keepAliveRequest, true, nil)
+		reply <- err
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-reply:
+		return err == nil
+	case <-timer.C:
+		return false
+	}
+}
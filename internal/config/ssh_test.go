@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/melbahja/goph"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestParseProxyJump(t *testing.T) {
+	tests := []struct {
+		name      string
+		proxyJump string
+		want      []SSHHop
+		wantErr   bool
+	}{
+		{"test-empty", "", nil, false},
+		{"test-host", "bastion", []SSHHop{{User: "user", Host: "bastion", Port: 22}}, false},
+		{"test-user-host-port", "admin@bastion:2222", []SSHHop{{User: "admin", Host: "bastion", Port: 2222}}, false},
+		{
+			"test-multiple", "bastion, jump@jump.example.com:2222",
+			[]SSHHop{{User: "user", Host: "bastion", Port: 22}, {User: "jump", Host: "jump.example.com", Port: 2222}}, false,
+		},
+		{"test-ipv6", "[2001:db8::1]:2222", []SSHHop{{User: "user", Host: "2001:db8::1", Port: 2222}}, false},
+		{"test-ipv6-no-port", "2001:db8::1", []SSHHop{{User: "user", Host: "2001:db8::1", Port: 22}}, false},
+		{"test-invalid-port", "bastion:ssh", nil, true},
+		{"test-missing-host", "admin@", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProxyJump(tt.proxyJump, "user")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseProxyJump() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseProxyJump() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFixedHostKeyCallback(t *testing.T) {
+	newKey := func() ssh.PublicKey {
+		pub, _, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := ssh.NewPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	expected := newKey()
+	other := newKey()
+
+	callback, err := fixedHostKeyCallback(string(ssh.MarshalAuthorizedKey(expected)))
+	if err != nil {
+		t.Fatalf("fixedHostKeyCallback() error = %s", err)
+	}
+
+	if err := callback("dns01:22", nil, expected); err != nil {
+		t.Errorf("callback() with expected key error = %s", err)
+	}
+
+	err = callback("dns01:22", nil, other)
+	if err == nil || !strings.Contains(err.Error(), "host key mismatch") {
+		t.Errorf("callback() with other key error = %v, want host key mismatch", err)
+	}
+
+	if _, err := fixedHostKeyCallback("not a key"); err == nil {
+		t.Errorf("fixedHostKeyCallback() with invalid key did not fail")
+	}
+}
+
+func TestSSHErrorReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"test-auth", errors.New("while connecting to user@dc01:22: ssh: handshake failed: ssh: unable to authenticate, attempted methods [none password], no supported methods remain"), "authentication failed"},
+		{"test-host-key", errors.New("while connecting to user@dc01:22: ssh: handshake failed: host key mismatch for dc01:22"), "host key verification failed"},
+		{"test-known-hosts", errors.New("while connecting to user@dc01:22: ssh: handshake failed: host key verification failed for dc01:22: no ssh-ed25519 key found"), "host key verification failed"},
+		{"test-dial", errors.New("while connecting to user@dc01:22: dial tcp 203.0.113.10:22: i/o timeout"), "connection failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SSHErrorReason(tt.err); got != tt.want {
+				t.Errorf("SSHErrorReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// newLocalSSHClient returns an SSH client connected to a server on the loopback interface, which answers global requests when reply is
+// set and ignores them otherwise.
+func newLocalSSHClient(t *testing.T, reply bool) *ssh.Client {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConfig := &ssh.ServerConfig{NoClientAuth: true}
+	serverConfig.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %s", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		serverConn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		conn, chans, reqs, err := ssh.NewServerConn(serverConn, serverConfig)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		go func() {
+			for ch := range chans {
+				_ = ch.Reject(ssh.Prohibited, "no channels")
+			}
+		}()
+		for req := range reqs {
+			if reply {
+				_ = req.Reply(false, nil)
+			}
+		}
+	}()
+
+	client, err := ssh.Dial("tcp", listener.Addr().String(), &ssh.ClientConfig{
+		User:            "user",
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		t.Fatalf("ssh.Dial() error = %s", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestKeepAlive(t *testing.T) {
+	tests := []struct {
+		name       string
+		reply      bool
+		wantClosed bool
+	}{
+		{"test-answered", true, false},
+		{"test-unanswered", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newLocalSSHClient(t, tt.reply)
+			closed := make(chan struct{})
+			go func() {
+				_ = client.Wait()
+				close(closed)
+			}()
+
+			go keepAlive(client, 10*time.Millisecond, 2, closed)
+
+			select {
+			case <-closed:
+				if !tt.wantClosed {
+					t.Errorf("keepAlive() closed a connection answering keepalives")
+				}
+			case <-time.After(200 * time.Millisecond):
+				if tt.wantClosed {
+					t.Errorf("keepAlive() did not close a connection ignoring keepalives")
+				}
+			}
+		})
+	}
+}
+
+// newLocalJumpHost starts an SSH server on the loopback interface forwarding direct-tcpip channels, like a jump host.
+// It returns its port, and a channel receiving a value whenever a client connection to it ends.
+func newLocalJumpHost(t *testing.T) (uint, <-chan struct{}) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConfig := &ssh.ServerConfig{NoClientAuth: true}
+	serverConfig.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %s", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	ended := make(chan struct{}, 10)
+	go func() {
+		for {
+			serverConn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				conn, chans, reqs, err := ssh.NewServerConn(serverConn, serverConfig)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				go forwardChannels(chans)
+				_ = conn.Wait()
+				ended <- struct{}{}
+			}()
+		}
+	}()
+	return uint(listener.Addr().(*net.TCPAddr).Port), ended
+}
+
+// forwardChannels connects direct-tcpip channels to their destination, rejecting other channels and destinations that
+// cannot be reached.
+func forwardChannels(chans <-chan ssh.NewChannel) {
+	for ch := range chans {
+		var dest struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+		if ch.ChannelType() != "direct-tcpip" || ssh.Unmarshal(ch.ExtraData(), &dest) != nil {
+			_ = ch.Reject(ssh.UnknownChannelType, "unsupported channel")
+			continue
+		}
+		target, err := net.Dial("tcp", net.JoinHostPort(dest.Host, strconv.FormatUint(uint64(dest.Port), 10)))
+		if err != nil {
+			_ = ch.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, reqs, err := ch.Accept()
+		if err != nil {
+			_ = target.Close()
+			continue
+		}
+		go ssh.DiscardRequests(reqs)
+		go func() {
+			_, _ = io.Copy(channel, target)
+			_ = channel.CloseWrite()
+		}()
+		go func() {
+			_, _ = io.Copy(target, channel)
+			_ = target.Close()
+		}()
+	}
+}
+
+// waitEnded waits for n client connections to the jump host to end.
+func waitEnded(t *testing.T, ended <-chan struct{}, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-ended:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("%d of %d connections to the jump host are still open", n-i, n)
+		}
+	}
+}
+
+func TestDialHops(t *testing.T) {
+	port, ended := newLocalJumpHost(t)
+	insecure := func(SSHHop) (ssh.HostKeyCallback, error) { return ssh.InsecureIgnoreHostKey(), nil }
+	auth := goph.Password("")
+
+	// Nothing listens on the port once the listener is closed, so the second hop cannot be reached.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %s", err)
+	}
+	closedPort := uint(listener.Addr().(*net.TCPAddr).Port)
+	listener.Close()
+
+	hops := []SSHHop{{User: "user", Host: "127.0.0.1", Port: port}, {User: "user", Host: "127.0.0.1", Port: closedPort}}
+	if _, err := dialHops(hops, auth, time.Second, insecure); err == nil {
+		t.Fatalf("dialHops() through an unreachable hop returned no error")
+	}
+	waitEnded(t, ended, 1)
+
+	// The jump host tunnels to itself, so closing the client ends both connections to it.
+	hops = []SSHHop{{User: "user", Host: "127.0.0.1", Port: port}, {User: "user", Host: "127.0.0.1", Port: port}}
+	client, err := dialHops(hops, auth, time.Second, insecure)
+	if err != nil {
+		t.Fatalf("dialHops() error = %s", err)
+	}
+	if len(client.jumps) != 1 {
+		t.Errorf("dialHops() returned %d jump host connections, want 1", len(client.jumps))
+	}
+	_ = client.Close()
+	waitEnded(t, ended, 2)
+}
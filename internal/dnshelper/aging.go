@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// ZoneAging holds the aging and scavenging configuration of a zone. The intervals are in hours.
+type ZoneAging struct {
+	ZoneName          string
+	AgingEnabled      bool
+	RefreshInterval   int64
+	NoRefreshInterval int64
+	// ScavengeServers are the IP addresses of the servers allowed to scavenge the zone. Empty when any server may.
+	ScavengeServers []string
+}
+
+// zoneAging holds the fields we use from the object returned by Get-DnsServerZoneAging.
+type zoneAging struct {
+	AgingEnabled      bool `json:"AgingEnabled"`
+	RefreshInterval   TTL  `json:"RefreshInterval"`
+	NoRefreshInterval TTL  `json:"NoRefreshInterval"`
+
+	ScavengeServers []IPAddress `json:"ScavengeServers"`
+}
+
+// setZoneAgingScript runs Set-DnsServerZoneAging with the parameters in $params, converting the intervals from hours.
+const setZoneAgingScript = `$ErrorActionPreference = 'Stop'; ` +
+	`foreach ($name in 'RefreshInterval', 'NoRefreshInterval') { ` +
+	`if ($params.ContainsKey($name)) { $params[$name] = [TimeSpan]::FromHours($params[$name]) } }; ` +
+	`Set-DnsServerZoneAging @params`
+
+// GetZoneAging returns the aging configuration of zoneName.
+func GetZoneAging(ctx context.Context, conf *config.ProviderConf, zoneName string) (*ZoneAging, error) {
+	psOpts := CreatePSCommandOpts{
+		JSONOutput: true,
+		JSONDepth:  2,
+		Username:   conf.Settings.SshUsername,
+		Password:   conf.Settings.SshPassword,
+		Server:     conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSCommand("Get-DnsServerZoneAging", map[string]any{"Name": zoneName}, psOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return nil, fmt.Errorf("ssh execution failure in GetZoneAging: %s", err)
+	}
+
+	if result.ExitCode != 0 {
+		return nil, newPSCommandError("Get-DnsServerZoneAging", result)
+	}
+
+	aging, err := unmarshallZoneAging(ctx, []byte(result.Stdout))
+	if err != nil {
+		return nil, fmt.Errorf("GetZoneAging: %s", err)
+	}
+	aging.ZoneName = zoneName
+	return aging, nil
+}
+
+// Update changes the aging configuration of the zone. The keys of changes are the Set-DnsServerZoneAging parameters
+// to change: Aging, RefreshInterval, NoRefreshInterval and ScavengeServers.
+func (a *ZoneAging) Update(ctx context.Context, conf *config.ProviderConf, changes map[string]any) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	params := map[string]any{"Name": a.ZoneName}
+	for k, v := range changes {
+		params[k] = v
+	}
+
+	psOpts := CreatePSCommandOpts{
+		Username: conf.Settings.SshUsername,
+		Password: conf.Settings.SshPassword,
+		Server:   conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSScript("Set-DnsServerZoneAging", setZoneAgingScript, params, psOpts)
+	if err != nil {
+		return err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("ssh execution failure while updating zone aging: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return newPSCommandError("Set-DnsServerZoneAging", result)
+	}
+	return nil
+}
+
+func unmarshallZoneAging(ctx context.Context, input []byte) (*ZoneAging, error) {
+	var aging zoneAging
+
+	doc, err := jsonDocument(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(doc, &aging)
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Failed to unmarshall a zone aging json document with error %q, document was %s", err, string(input)))
+		return nil, fmt.Errorf("failed while unmarshalling zone aging json document: %s", err)
+	}
+
+	return &ZoneAging{
+		AgingEnabled:      aging.AgingEnabled,
+		RefreshInterval:   aging.RefreshInterval.TotalSeconds / 3600,
+		NoRefreshInterval: aging.NoRefreshInterval.TotalSeconds / 3600,
+		ScavengeServers:   ipAddressStrings(aging.ScavengeServers),
+	}, nil
+}
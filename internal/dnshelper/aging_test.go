@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshallZoneAging(t *testing.T) {
+	input := `{
+  "AgingEnabled": true,
+  "AvailForScavengeTime": null,
+  "NoRefreshInterval": { "Ticks": 6048000000000, "TotalHours": 168, "TotalSeconds": 604800 },
+  "RefreshInterval": { "Ticks": 3024000000000, "TotalHours": 84, "TotalSeconds": 302400 },
+  "ScavengeServers": null,
+  "ZoneName": "example.com"
+}`
+
+	got, err := unmarshallZoneAging(context.Background(), []byte(input))
+	if err != nil {
+		t.Fatalf("unmarshallZoneAging() error = %v", err)
+	}
+
+	want := &ZoneAging{
+		AgingEnabled:      true,
+		RefreshInterval:   84,
+		NoRefreshInterval: 168,
+		ScavengeServers:   []string{},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unmarshallZoneAging() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUnmarshallZoneAging_ScavengeServers(t *testing.T) {
+	input := `{
+  "AgingEnabled": true,
+  "NoRefreshInterval": { "Ticks": 6048000000000, "TotalHours": 168, "TotalSeconds": 604800 },
+  "RefreshInterval": { "Ticks": 6048000000000, "TotalHours": 168, "TotalSeconds": 604800 },
+  "ScavengeServers": [ { "Address": 16843018, "AddressFamily": 2, "IPAddressToString": "10.1.1.1" } ],
+  "ZoneName": "example.com"
+}`
+
+	got, err := unmarshallZoneAging(context.Background(), []byte(input))
+	if err != nil {
+		t.Fatalf("unmarshallZoneAging() error = %v", err)
+	}
+	if want := []string{"10.1.1.1"}; !reflect.DeepEqual(got.ScavengeServers, want) {
+		t.Errorf("unmarshallZoneAging() ScavengeServers = %q, want %q", got.ScavengeServers, want)
+	}
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// certRRType is the type number of CERT records. The DnsServer module has no parameters for CERT records, so they are
+// added, read and removed by their type number, with the record data given as the hex encoded RDATA.
+const certRRType = 37
+
+// certTypes maps the mnemonics of the certificate types to their numbers, see RFC 4398.
+var certTypes = map[string]uint16{
+	"PKIX":    1,
+	"SPKI":    2,
+	"PGP":     3,
+	"IPKIX":   4,
+	"ISPKI":   5,
+	"IPGP":    6,
+	"ACPKIX":  7,
+	"IACPKIX": 8,
+	"URI":     253,
+	"OID":     254,
+}
+
+// certRecord holds the fields of the data of a CERT record.
+type certRecord struct {
+	CertType    uint16
+	KeyTag      uint16
+	Algorithm   uint8
+	Certificate []byte
+}
+
+// parseCERTRecordData parses CERT record data written as in a zone file: `<type> <key-tag> <algorithm> <certificate>`,
+// where the type may be given as a number or by mnemonic and the certificate is base64 encoded. The certificate may be
+// split by whitespace, as in zone files.
+func parseCERTRecordData(recordData string) (*certRecord, error) {
+	values := strings.Fields(recordData)
+	if len(values) < 4 {
+		return nil, fmt.Errorf("CERT record data %q must have the form %q", recordData, "<type> <key-tag> <algorithm> <certificate>")
+	}
+
+	certType, ok := certTypes[strings.ToUpper(values[0])]
+	if !ok {
+		v, err := strconv.ParseUint(values[0], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CERT record type %q, expected a number or a mnemonic, e.g. PKIX", values[0])
+		}
+		certType = uint16(v)
+	}
+	keyTag, err := strconv.ParseUint(values[1], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CERT record key tag %q, expected a number from 0 to 65535", values[1])
+	}
+	algorithm, err := strconv.ParseUint(values[2], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CERT record algorithm %q, expected a number from 0 to 255", values[2])
+	}
+	certificate, err := base64.StdEncoding.DecodeString(strings.Join(values[3:], ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CERT record certificate, expected base64: %s", err)
+	}
+
+	return &certRecord{
+		CertType:    certType,
+		KeyTag:      uint16(keyTag),
+		Algorithm:   uint8(algorithm),
+		Certificate: certificate,
+	}, nil
+}
+
+// String writes the record data as in the records attribute, with numeric type and algorithm.
+func (c *certRecord) String() string {
+	return fmt.Sprintf("%d %d %d %s", c.CertType, c.KeyTag, c.Algorithm, base64.StdEncoding.EncodeToString(c.Certificate))
+}
+
+// rdata returns the record data in wire format, hex encoded as the DnsServer module takes it.
+func (c *certRecord) rdata() string {
+	data := make([]byte, 5, 5+len(c.Certificate))
+	binary.BigEndian.PutUint16(data[0:], c.CertType)
+	binary.BigEndian.PutUint16(data[2:], c.KeyTag)
+	data[4] = c.Algorithm
+	return hex.EncodeToString(append(data, c.Certificate...))
+}
+
+// certRecordFromRData decodes the hex encoded RDATA of a CERT record returned by the server.
+func certRecordFromRData(rdata string) (*certRecord, error) {
+	data, err := hex.DecodeString(strings.Join(strings.Fields(rdata), ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CERT record data %q: %s", rdata, err)
+	}
+	if len(data) < 5 {
+		return nil, fmt.Errorf("CERT record data %q is too short", rdata)
+	}
+	return &certRecord{
+		CertType:    binary.BigEndian.Uint16(data[0:]),
+		KeyTag:      binary.BigEndian.Uint16(data[2:]),
+		Algorithm:   data[4],
+		Certificate: data[5:],
+	}, nil
+}
+
+// SanitizeCERTRecordData validates the data of a CERT record, written as in a zone file.
+func SanitizeCERTRecordData(input string) (string, error) {
+	if _, err := parseCERTRecordData(input); err != nil {
+		return "", err
+	}
+	return input, nil
+}
+
+// certRecordData returns CERT record data in the form the DnsServer module takes it in, the hex encoded RDATA.
+func certRecordData(recordData string) (string, error) {
+	c, err := parseCERTRecordData(recordData)
+	if err != nil {
+		return "", err
+	}
+	return c.rdata(), nil
+}
+
+// formatCERTRecordData writes the RDATA of a CERT record returned by the server as in the records attribute. RDATA
+// that cannot be decoded is returned unchanged.
+func formatCERTRecordData(rdata string) string {
+	c, err := certRecordFromRData(rdata)
+	if err != nil {
+		return rdata
+	}
+	return c.String()
+}
+
+// normalizeCERTRecordData writes CERT record data in the form returned by the server, with numeric type and the
+// certificate in a single field. Data that cannot be parsed is returned unchanged.
+func normalizeCERTRecordData(recordData string) string {
+	c, err := parseCERTRecordData(recordData)
+	if err != nil {
+		return recordData
+	}
+	return c.String()
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// testCertificate is a self-signed X.509 certificate for an S/MIME mailbox, of the size published in CERT records.
+const testCertificate = "MIIDYTCCAkmgAwIBAgIUJfE3LBgytCCo/FWZwwCewW4tbvYwDQYJKoZIhvcNAQELBQAwQDEZMBcGA1UEAwwQbWFpbC5leGFtcGxlLmNvbTEjMCEGCSqGSIb3DQEJARYUc2VjdXJpdHlAZXhhbXBsZS5jb20wHhcNMjYxMDE2MTA1NTM0WhcNMzYxMDEzMTA1NTM0WjBAMRkwFwYDVQQDDBBtYWlsLmV4YW1wbGUuY29tMSMwIQYJKoZIhvcNAQkBFhRzZWN1cml0eUBleGFtcGxlLmNvbTCCASIwDQYJKoZIhvcNAQEBBQADggEPADCCAQoCggEBAK5XWeGDsaKY/pGYfCDDPr3ROCkBZL/REsX+7X8k9JlqRq3GxIb2SWJb6B+z4B0p4pQojyQPj+an1TXmQswFzmhnR14VZsjm5u7SzKGAbFf8sQLPPlXnxt+0j9lLJNQjIfv2MPmuBXJqtNAd/Y56NJKPUCPQXhg2kfXNRrsA1QZo8NOW+M5JKGdc1bhJbsbAXdxHLgdoxMaJhR3dpqwDoJfgpN94LAArjv1CzJWgNJCZbzynCfuUFSOAcx8MST5mc2v358uktKeNX+1pYjstII266EGsqKpZmXsbu5ahHR+7RHp5xMASHHW+f8hJhqld4CRcKgMD5l0RDKIcGq3GOsUCAwEAAaNTMFEwHQYDVR0OBBYEFBxU2qFyrbjjEpZAssdGWBmIgGjAMB8GA1UdIwQYMBaAFBxU2qFyrbjjEpZAssdGWBmIgGjAMA8GA1UdEwEB/wQFMAMBAf8wDQYJKoZIhvcNAQELBQADggEBAJiDfBoX0zjeapjvH8tLC3bgwPmwBmTVE5pipFXRN4injT49JkqAAgdtOP+ZRwl1SohVFN4LVuV401J+Akhfpl7vGUOBuYJ17SpCxKKMKxhOJBHNItTFN7MYuFXvaijezp0EM3XsYiPr2dM5EQqT7elxIoxYTUALr9O33wMjA08ucYUwdjIQomEhDWHhU6IdaAzvG0lWwxjccwVVVR3D2GWOLQe/0XS+z4IdwW8pVF3wHOD/EmZuJQ934P2PBKw16GcioOZ+AMoUowXuxfJNT82+TAPIxpGFLMSRcbmxt8tc3ekIdYKlJDXtMTtZpJ4bN+4aFNYVHuOQw1nFB+KgbxY="
+
+func TestParseCERTRecordData(t *testing.T) {
+	split := testCertificate[:400] + " " + testCertificate[400:800] + "\t" + testCertificate[800:]
+	tests := []struct {
+		name       string
+		recordData string
+		want       string
+		wantErr    bool
+	}{
+		{"test-numeric", "1 12345 8 " + testCertificate, "1 12345 8 " + testCertificate, false},
+		{"test-mnemonic", "pkix 0 8 " + testCertificate, "1 0 8 " + testCertificate, false},
+		{"test-split-certificate", "PKIX 0 8 " + split, "1 0 8 " + testCertificate, false},
+		{"test-uri", "URI 0 0 aHR0cHM6Ly9leGFtcGxlLmNvbS9jZXJ0", "253 0 0 aHR0cHM6Ly9leGFtcGxlLmNvbS9jZXJ0", false},
+		{"test-missing-certificate", "PKIX 0 8", "", true},
+		{"test-unknown-mnemonic", "X509 0 8 " + testCertificate, "", true},
+		{"test-key-tag-too-large", "PKIX 65536 8 " + testCertificate, "", true},
+		{"test-algorithm-too-large", "PKIX 0 256 " + testCertificate, "", true},
+		{"test-invalid-base64", "PKIX 0 8 " + testCertificate[:len(testCertificate)-1] + "%", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCERTRecordData(tt.recordData)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCERTRecordData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("parseCERTRecordData() = %q, want %q", got.String(), tt.want)
+			}
+
+			// The record data round-trips through the RDATA passed to and returned by the server.
+			if formatted := formatCERTRecordData(strings.ToUpper(got.rdata())); formatted != tt.want {
+				t.Errorf("formatCERTRecordData() = %q, want %q", formatted, tt.want)
+			}
+		})
+	}
+}
+
+func TestCERTRecordRData(t *testing.T) {
+	c, err := parseCERTRecordData("PGP 258 5 AQID")
+	if err != nil {
+		t.Fatalf("parseCERTRecordData() error = %s", err)
+	}
+	if got, want := c.rdata(), "00030102"+"05"+"010203"; got != want {
+		t.Errorf("rdata() = %q, want %q", got, want)
+	}
+	if _, err := certRecordFromRData("000301"); err == nil {
+		t.Errorf("certRecordFromRData() of truncated RDATA did not fail")
+	}
+}
+
+func TestRecordCERT_ExecutorRoundTrip(t *testing.T) {
+	conf, executor := newFakeConf()
+	recordData := "PKIX 0 8 " + testCertificate
+	r := &Record{ZoneName: "example.com", HostName: "mail", RecordType: RecordTypeCERT, Records: []string{recordData}}
+
+	if _, err := r.Create(context.Background(), conf); err != nil {
+		t.Fatalf("Create() error = %s", err)
+	}
+	params := executor.params(t, 0)
+	if params["Type"] != float64(certRRType) || params["CERT"] != nil {
+		t.Errorf("params = %v, want the record added by type number", params)
+	}
+	rdata, _ := params["RecordData"].(string)
+
+	conf, _ = newFakeConf(&config.CommandOutput{Stdout: `{
+  "HostName": "mail",
+  "RecordType": "UNKNOWN",
+  "Type": 37,
+  "RecordData": { "CimInstanceProperties": [ { "Name": "Data", "value": "` + rdata + `" } ] },
+  "TimeToLive": { "TotalSeconds": 3600 }
+}`})
+	got, err := GetDNSRecordFromId(context.Background(), conf, r.Id())
+	if err != nil {
+		t.Fatalf("GetDNSRecordFromId() error = %s", err)
+	}
+	if got.RecordType != RecordTypeCERT || len(got.Records) != 1 {
+		t.Fatalf("GetDNSRecordFromId() = %+v, want a single CERT record", got)
+	}
+	if want := "1 0 8 " + testCertificate; got.Records[0] != want {
+		t.Errorf("GetDNSRecordFromId() records = %q, want %q", got.Records[0], want)
+	}
+	if !recordDataInList(RecordTypeCERT, recordData, got.Records) {
+		t.Errorf("record data read back does not match %q", recordData)
+	}
+}
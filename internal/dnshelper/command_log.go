@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+type commandLogKey struct{}
+
+// CommandLog collects the commands changing the DNS server that are run with a context, for auditing. Queries, which
+// return JSON, are left out.
+type CommandLog struct {
+	mu       sync.Mutex
+	commands []string
+}
+
+// WithCommandLog returns a context logging the commands run with it to the returned CommandLog.
+func WithCommandLog(ctx context.Context) (context.Context, *CommandLog) {
+	log := &CommandLog{}
+	return context.WithValue(ctx, commandLogKey{}, log), log
+}
+
+// Commands returns the commands logged, in the order they were run, as rendered by PSCommand.String.
+func (l *CommandLog) Commands() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string{}, l.commands...)
+}
+
+// logCommand adds p to the CommandLog of ctx, if any. Credentials are never part of the parameters of a command, but
+// the SSH password is masked should it appear in a value.
+func logCommand(ctx context.Context, conf *config.ProviderConf, p *PSCommand) {
+	log, ok := ctx.Value(commandLogKey{}).(*CommandLog)
+	if !ok || p.JSONOutput {
+		return
+	}
+
+	command := p.String()
+	if conf.Settings.SshPassword != "" {
+		command = strings.ReplaceAll(command, conf.Settings.SshPassword, "***")
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.commands = append(log.commands, command)
+}
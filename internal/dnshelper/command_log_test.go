@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+func TestCommandLog(t *testing.T) {
+	conf, _ := newFakeConf(&config.CommandOutput{Stdout: `{
+  "HostName": "www",
+  "RecordType": "A",
+  "RecordData": { "CimInstanceProperties": [ { "Name": "IPv4Address", "value": "203.0.113.11" } ] }
+}`})
+	conf.Settings.SshUsername = "dnsadmin"
+	conf.Settings.SshPassword = "s3cret"
+	r := &Record{ZoneName: "example.com", HostName: "www", RecordType: RecordTypeA, Records: []string{"203.0.113.12"}}
+
+	ctx, log := WithCommandLog(context.Background())
+	changes := map[string]interface{}{"records": []interface{}{"203.0.113.12"}}
+	if err := r.Update(ctx, conf, changes); err != nil {
+		t.Fatalf("Update() error = %s", err)
+	}
+
+	// The query for the existing records is left out.
+	want := []string{
+		"Add-DnsServerResourceRecord -A -ComputerName 'dc01.example.com' -IPv4Address '203.0.113.12' -Name 'www' -ZoneName 'example.com'",
+		"Remove-DnsServerResourceRecord -ComputerName 'dc01.example.com' -Force -Name 'www' -RRType 'A' -RecordData '203.0.113.11' -ZoneName 'example.com'",
+	}
+	got := log.Commands()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Commands() = %q, want %q", got, want)
+	}
+	for _, command := range got {
+		if strings.Contains(command, "s3cret") || strings.Contains(command, "dnsadmin") {
+			t.Errorf("command %q contains credentials", command)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// dhcidRRType is the type number of DHCID records. Like CERT records, they are added, read and removed by their type
+// number, with the record data given as the hex encoded RDATA.
+const dhcidRRType = 49
+
+// minDHCIDLength is the length of the shortest DHCID RDATA: the identifier type code, the digest type code and at least
+// one byte of digest, see RFC 4701.
+const minDHCIDLength = 4
+
+// parseDHCIDRecordData decodes DHCID record data written as in a zone file, the base64 encoded RDATA, which may be split
+// by whitespace.
+func parseDHCIDRecordData(recordData string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(recordData), ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DHCID record data %q, expected base64: %s", recordData, err)
+	}
+	if len(data) < minDHCIDLength {
+		return nil, fmt.Errorf("DHCID record data %q is too short, expected an identifier type, a digest type and a digest", recordData)
+	}
+	return data, nil
+}
+
+// SanitizeDHCIDRecordData validates the data of a DHCID record, written as in a zone file.
+func SanitizeDHCIDRecordData(input string) (string, error) {
+	if _, err := parseDHCIDRecordData(input); err != nil {
+		return "", err
+	}
+	return input, nil
+}
+
+// dhcidRecordData returns DHCID record data in the form the DnsServer module takes it in, the hex encoded RDATA.
+func dhcidRecordData(recordData string) (string, error) {
+	data, err := parseDHCIDRecordData(recordData)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// unknownRecordDataProperty is the record data property of records the server reads as of an unknown type, holding
+// the hex encoded RDATA.
+const unknownRecordDataProperty = "Data"
+
+// formatDHCIDRecordData writes the record data property of a DHCID record returned by the server as in the records
+// attribute. The server returns the record data base64 encoded for records of the DHCID type, and hex encoded in the
+// Data property for records it reads as of an unknown type. Data that cannot be decoded is returned unchanged.
+func formatDHCIDRecordData(property CimInstanceProperties) string {
+	value := formatCimValue(property.Value)
+	var data []byte
+	var err error
+	if strings.EqualFold(property.Name, unknownRecordDataProperty) {
+		data, err = hex.DecodeString(strings.Join(strings.Fields(value), ""))
+	} else {
+		data, err = parseDHCIDRecordData(value)
+	}
+	if err != nil {
+		return value
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// normalizeDHCIDRecordData writes DHCID record data in the form returned by the server, in a single field. Data that
+// cannot be parsed is returned unchanged.
+func normalizeDHCIDRecordData(recordData string) string {
+	data, err := parseDHCIDRecordData(recordData)
+	if err != nil {
+		return recordData
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
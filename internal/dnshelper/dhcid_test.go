@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// testDHCID is the DHCID record data of the first example of RFC 4701, for a client identified by its DUID.
+const testDHCID = "AAIBY2/AuCccgoJbsaxcQc9TUapptP69lOjxfNuVAA2kjEA="
+
+func TestParseDHCIDRecordData(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordData string
+		want       string
+		wantErr    bool
+	}{
+		{"test-dhcid", testDHCID, testDHCID, false},
+		{"test-split", testDHCID[:20] + " " + testDHCID[20:], testDHCID, false},
+		{"test-too-short", "AAIB", "", true},
+		{"test-invalid-base64", testDHCID[:len(testDHCID)-1] + "%", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SanitizeDHCIDRecordData(tt.recordData)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SanitizeDHCIDRecordData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := normalizeDHCIDRecordData(tt.recordData); got != tt.want {
+				t.Errorf("normalizeDHCIDRecordData() = %q, want %q", got, tt.want)
+			}
+
+			// The record data round-trips through the RDATA passed to and returned by the server.
+			rdata, err := dhcidRecordData(tt.recordData)
+			if err != nil {
+				t.Fatalf("dhcidRecordData() error = %s", err)
+			}
+			property := CimInstanceProperties{Name: "Data", Value: strings.ToUpper(rdata)}
+			if formatted := formatDHCIDRecordData(property); formatted != tt.want {
+				t.Errorf("formatDHCIDRecordData() = %q, want %q", formatted, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordDHCID_ExecutorRoundTrip(t *testing.T) {
+	conf, executor := newFakeConf()
+	r := &Record{ZoneName: "example.com", HostName: "client", RecordType: RecordTypeDHCID, Records: []string{testDHCID}}
+
+	if _, err := r.Create(context.Background(), conf); err != nil {
+		t.Fatalf("Create() error = %s", err)
+	}
+	params := executor.params(t, 0)
+	if params["Type"] != float64(dhcidRRType) || params["DHCID"] != nil {
+		t.Errorf("params = %v, want the record added by type number", params)
+	}
+	if want := "000201636fc0b8271c82825bb1ac5c41cf5351aa69b4febd94e8f17cdb95000da48c40"; params["RecordData"] != want {
+		t.Errorf("RecordData = %v, want %s", params["RecordData"], want)
+	}
+
+	// The server reads records of the types it knows by name, with their record data base64 encoded.
+	conf, _ = newFakeConf(&config.CommandOutput{Stdout: `{
+  "HostName": "client",
+  "RecordType": "DHCID",
+  "Type": 49,
+  "RecordData": { "CimInstanceProperties": [ { "Name": "DHCID", "value": "` + testDHCID + `" } ] },
+  "TimeToLive": { "TotalSeconds": 3600 }
+}`})
+	got, err := GetDNSRecordFromId(context.Background(), conf, r.Id())
+	if err != nil {
+		t.Fatalf("GetDNSRecordFromId() error = %s", err)
+	}
+	if got.RecordType != RecordTypeDHCID || len(got.Records) != 1 || got.Records[0] != testDHCID {
+		t.Fatalf("GetDNSRecordFromId() = %+v, want the DHCID record %s", got, testDHCID)
+	}
+	// Base64 is case-sensitive, so record data differing only in case is another record.
+	if recordDataInList(RecordTypeDHCID, strings.ToLower(testDHCID), got.Records) {
+		t.Errorf("record data in lower case matched %q", testDHCID)
+	}
+}
@@ -7,22 +7,47 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"golang.org/x/exp/slices"
 )
 
 const (
 	IDSeparator = "_"
 
+	// ApexName is the record name the DnsServer module uses for the zone apex.
+	ApexName = "@"
+	// WildcardLabel is the leading label of wildcard record names, which match names without records of their own.
+	WildcardLabel = "*"
+
 	RecordTypeAAAA  = "AAAA"
 	RecordTypeA     = "A"
 	RecordTypeTXT   = "TXT"
 	RecordTypePTR   = "PTR"
 	RecordTypeCNAME = "CNAME"
+	RecordTypeAFSDB = "AFSDB"
+	RecordTypeRP    = "RP"
+	RecordTypeX25   = "X25"
+	RecordTypeISDN  = "ISDN"
+	RecordTypeWKS   = "WKS"
+	RecordTypeDS    = "DS"
+	RecordTypeMB    = "MB"
+	RecordTypeMG    = "MG"
+	RecordTypeMR    = "MR"
+	RecordTypeMINFO = "MINFO"
+	RecordTypeATMA  = "ATMA"
+	RecordTypeCERT  = "CERT"
+	RecordTypeDHCID = "DHCID"
+	RecordTypeNS    = "NS"
+	RecordTypeRT    = "RT"
+	// RecordTypeSOA records are never managed as records, as a zone always has exactly one, at its apex.
+	RecordTypeSOA = "SOA"
 )
 
 type Record struct {
@@ -31,14 +56,67 @@ type Record struct {
 	RecordType string   `json:"RecordType"`
 	Records    []string `json:"Records"`
 	CreatePtr  bool     `json:"CreatePtr"`
+	// PtrZoneName is the reverse zone PTR records are created in when CreatePtr is set.
+	// When empty, the DNS server picks the reverse zone.
+	PtrZoneName string `json:"PtrZoneName"`
+	// VirtualizationInstance is the DNS server virtualization instance holding the zone.
+	// When empty, the default instance is used.
+	VirtualizationInstance string `json:"VirtualizationInstance"`
+	// ZoneScope is the zone scope holding the records, e.g. for answers chosen by DNS policies per client subnet.
+	// When empty, the default scope of the zone is used.
+	ZoneScope string `json:"ZoneScope"`
+	// OrderedRecords makes the order of Records significant. The records are
+	// then stored on the server in the order of Records.
+	OrderedRecords bool `json:"OrderedRecords"`
+	// ManagePtrLifecycle makes removing A and AAAA records created with CreatePtr remove their PTR records too.
+	ManagePtrLifecycle bool `json:"ManagePtrLifecycle"`
+	// SkipMissingPtrZone makes CreatePtr skip the PTR records of addresses no reverse zone on the server covers. By
+	// default adding such addresses fails before any record is added.
+	SkipMissingPtrZone bool `json:"SkipMissingPtrZone"`
+	// SkippedPtrAddresses lists the addresses added without a PTR record, as no reverse zone covers them. It is only
+	// set by Create and Update with SkipMissingPtrZone.
+	SkippedPtrAddresses []string `json:"SkippedPtrAddresses"`
+	// AppendOnly limits the records managed to those in Records, leaving other records of the name and type on the
+	// server in place, e.g. when several teams add records to the same name.
+	AppendOnly bool `json:"AppendOnly"`
+	// TrimWhitespace removes leading and trailing whitespace from Records before they are validated and added.
+	TrimWhitespace bool `json:"TrimWhitespace"`
+	// ExplicitTXTSegments makes the data of TXT records list their character-strings in double quotes, rather than
+	// being a single logical string split into character-strings when added.
+	ExplicitTXTSegments bool `json:"ExplicitTXTSegments"`
+	// TTL is the time to live of the records in seconds. When zero, records are created with the default TTL of the
+	// zone. On read it is zero when the records have different TTLs, which Set-DnsServerResourceRecord allows.
+	TTL int64 `json:"TTL"`
+	// DN is the distinguished name of the record's node, which includes the
+	// directory partition for AD-integrated zones. It is only set on read.
+	DN string `json:"DistinguishedName"`
+	// Timestamp is the latest time the server recorded for the records, which dynamically updated records refresh.
+	// It is zero for static records, and only set on read.
+	Timestamp time.Time `json:"Timestamp"`
 }
 
+// DNSRecord holds the fields we use from the objects returned by Get-DnsServerResourceRecord. The fields are read by
+// the names of the properties of the objects, which are not localized, and never from their formatted display values,
+// e.g. TTLs are read from TotalSeconds rather than the TimeSpan rendered in the culture of the server.
 type DNSRecord struct {
 	HostName   string     `json:"HostName"`
 	RecordType string     `json:"RecordType"`
+	Type       uint16     `json:"Type"`
 	DN         string     `json:"DistinguishedName"`
 	RecordData RecordData `json:"RecordData"`
 	TimeToLive TTL        `json:"TimeToLive"`
+	Timestamp  Timestamp  `json:"Timestamp"`
+}
+
+// recordType returns the type of the record. Records of types the DnsServer module has no name for, e.g. CERT, are
+// told apart by their type number.
+func (v DNSRecord) recordType() string {
+	for recordType, number := range rrTypeNumbers {
+		if v.Type == number {
+			return recordType
+		}
+	}
+	return v.RecordType
 }
 
 // The structure we get from powershell contains more fields, but we're only interested in CimInstanceProperties.
@@ -46,9 +124,10 @@ type RecordData struct {
 	CimInstanceProperties []CimInstanceProperties `json:"CimInstanceProperties"`
 }
 
-// The structure we get from powershell contains more fields, but we're only interested in the Value.
+// The structure we get from powershell contains more fields, but we're only interested in the Name and Value.
 type CimInstanceProperties struct {
-	Value string `json:"value"`
+	Name  string `json:"Name"`
+	Value any    `json:"value"`
 }
 
 // The structure we get from powershell contains more fields, but we're only interested in TotalSeconds.
@@ -56,71 +135,288 @@ type TTL struct {
 	TotalSeconds int64 `json:"TotalSeconds"`
 }
 
-// windns has no concept of primary key so we need to create one based on inputs
+// Timestamp is a DateTime serialized by ConvertTo-Json. Windows PowerShell writes it as "/Date(<milliseconds>)/",
+// while PowerShell 7 writes it in ISO 8601. Static records have no timestamp, which is written as null.
+type Timestamp struct {
+	time.Time
+}
+
+var msDateFormat = regexp.MustCompile(`^/Date\((-?\d+)([+-]\d{4})?\)/$`)
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == nil || *s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	if m := msDateFormat.FindStringSubmatch(*s); m != nil {
+		ms, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid timestamp %q: %s", *s, err)
+		}
+		t.Time = time.UnixMilli(ms).UTC()
+		return nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.9999999"} {
+		parsed, err := time.Parse(layout, *s)
+		if err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid timestamp %q", *s)
+}
+
+// latestTimestamp returns the latest timestamp of records, or the zero time if none of them have one.
+func latestTimestamp(records []DNSRecord) time.Time {
+	var latest time.Time
+	for _, v := range records {
+		if v.Timestamp.After(latest) {
+			latest = v.Timestamp.Time
+		}
+	}
+	return latest
+}
+
+// Dynamic reports whether the records were registered by dynamic update, e.g. by a DHCP server, rather than added
+// statically. The server only keeps a timestamp for dynamically updated records, so this is only known on read.
+func (r *Record) Dynamic() bool {
+	return !r.Timestamp.IsZero()
+}
+
+// commonTTL returns the TTL shared by records in seconds, or zero if their TTLs differ.
+func commonTTL(records []DNSRecord) int64 {
+	if len(records) == 0 {
+		return 0
+	}
+	ttl := records[0].TimeToLive.TotalSeconds
+	for _, v := range records[1:] {
+		if v.TimeToLive.TotalSeconds != ttl {
+			return 0
+		}
+	}
+	return ttl
+}
+
+// windns has no concept of primary key so we need to create one based on inputs.
+// The virtualization instance is only part of the id when set, keeping the ids of records in the default instance unchanged.
+// The zone scope follows it when set, after an empty virtualization instance for records in the default instance.
 func (r *Record) Id() string {
-	return strings.Join([]string{r.HostName, r.ZoneName, r.RecordType, strconv.FormatBool(r.CreatePtr)}, IDSeparator)
+	components := []string{r.HostName, r.ZoneName, r.RecordType, strconv.FormatBool(r.CreatePtr)}
+	if r.VirtualizationInstance != "" || r.ZoneScope != "" {
+		components = append(components, r.VirtualizationInstance)
+	}
+	if r.ZoneScope != "" {
+		components = append(components, r.ZoneScope)
+	}
+	return strings.Join(components, IDSeparator)
 }
 
-// NewDNSRecordFromResource returns a new Record struct populated from resource data
-func NewDNSRecordFromResource(d *schema.ResourceData) (*Record, error) {
-	var records []string
-	recordsSet := d.Get("records").(*schema.Set)
-	recordType := d.Get("type").(string)
+// ParseRecordID returns the records the ID made by Id refers to, without their data. Names may contain underscores,
+// e.g. _acme-challenge.www or _sip._tcp, so the ID is parsed from the right: the create_ptr flag is the last true or
+// false, followed by at most the virtualization instance and the zone scope, and preceded by the record type. IDs of
+// earlier versions without the flag end with the record type. The name is split from the zone by SplitNameID.
+func ParseRecordID(id string) (*Record, error) {
+	components := strings.Split(id, IDSeparator)
+	r := &Record{}
+	typeIndex := len(components) - 1
+	for i := len(components) - 1; i >= 2 && i >= len(components)-3; i-- {
+		if strings.EqualFold(components[i], "true") || strings.EqualFold(components[i], "false") {
+			typeIndex = i - 1
+			r.CreatePtr = strings.EqualFold(components[i], "true")
+			if rest := components[i+1:]; len(rest) > 0 {
+				r.VirtualizationInstance = rest[0]
+				if len(rest) > 1 {
+					r.ZoneScope = rest[1]
+				}
+			}
+			break
+		}
+	}
+
+	r.RecordType = components[typeIndex]
+	if typeIndex < 1 || r.RecordType == "" || strings.Contains(r.RecordType, ".") {
+		return nil, fmt.Errorf("invalid record ID %q, expected <name>_<zone_name>_<type>_<create_ptr>", id)
+	}
+	var err error
+	r.HostName, r.ZoneName, err = SplitNameID(strings.Join(components[:typeIndex], IDSeparator))
+	if err != nil {
+		return nil, fmt.Errorf("invalid record ID %q: %s", id, err)
+	}
+	return r, nil
+}
+
+// SplitNameID splits <name>_<zone_name>, the leading components of a record ID, into the name and the zone. They are
+// split at the last underscore that does not start a label, so labels starting with an underscore, e.g. in
+// _acme-challenge.www_example.com or www__msdcs.example.com, stay part of their name. Zone names are assumed to have
+// no other underscores, while names may, e.g. my_host_example.com.
+func SplitNameID(id string) (string, string, error) {
+	for i := len(id) - 2; i > 0; i-- {
+		if id[i] == '_' && id[i-1] != '.' && id[i-1] != '_' {
+			return id[:i], id[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("expected <name>_<zone_name>, got %q", id)
+}
 
-	for _, v := range recordsSet.List() {
-		sanitizedInput, err := SanitizeInputString(recordType, v.(string))
+// scopeParams adds the parameters selecting the virtualization instance and the zone scope of the record to params.
+func (r *Record) scopeParams(params map[string]any) {
+	if r.VirtualizationInstance != "" {
+		params["VirtualizationInstance"] = r.VirtualizationInstance
+	}
+	if r.ZoneScope != "" {
+		params["ZoneScope"] = r.ZoneScope
+	}
+}
+
+// typeParams adds the parameter selecting the type of the record to params. CERT and DHCID records are selected by their
+// type number, see rrTypeNumbers.
+func (r *Record) typeParams(params map[string]any) {
+	if number, ok := rrTypeNumbers[r.RecordType]; ok {
+		params["Type"] = number
+		return
+	}
+	params["RRType"] = r.RecordType
+}
+
+// sanitizeRecordList validates the record data in records. The server keeps one copy of each record, so duplicates are
+// dropped, unless ordered, where the position of a record listed twice is ambiguous and duplicates are an error. With
+// trimWhitespace, leading and trailing whitespace is removed from the record data first.
+func sanitizeRecordList(recordType string, records []interface{}, explicitTXTSegments, trimWhitespace, ordered bool) ([]string, error) {
+	var sanitized []string
+	for _, v := range records {
+		recordData := v.(string)
+		if trimWhitespace {
+			recordData = strings.TrimSpace(recordData)
+		}
+
+		var sanitizedInput string
+		var err error
+		if strings.EqualFold(recordType, RecordTypeTXT) {
+			sanitizedInput, err = SanitizeTXTRecordData(recordData, explicitTXTSegments)
+		} else {
+			sanitizedInput, err = SanitizeRecordData(recordType, recordData)
+		}
 		if err != nil {
 			return nil, err
 		}
-		records = append(records, sanitizedInput)
+		if recordDataInList(recordType, sanitizedInput, sanitized) {
+			if ordered {
+				return nil, fmt.Errorf("duplicate record %q, which cannot be placed twice with ordered_records", sanitizedInput)
+			}
+			continue
+		}
+		sanitized = append(sanitized, sanitizedInput)
 	}
+	return sanitized, nil
+}
 
-	sanitizedZoneName, err := SanitiseTFInput(d, "zone_name")
+// NewDNSRecordFromResource returns a new Record struct populated from resource data. The name is given the provider's
+// name prefix and suffix.
+func NewDNSRecordFromResource(conf *config.ProviderConf, d *schema.ResourceData) (*Record, error) {
+	recordType := d.Get("type").(string)
+	records, err := sanitizeRecordList(recordType, d.Get("records").([]interface{}), d.Get("explicit_txt_segments").(bool), d.Get("trim_whitespace").(bool), d.Get("ordered_records").(bool))
 	if err != nil {
 		return nil, err
 	}
-	sanitizedHostName, err := SanitiseTFInput(d, "name")
+
+	sanitizedZoneName, err := SanitizeZoneName(d.Get("zone_name").(string))
 	if err != nil {
 		return nil, err
 	}
+	sanitizedHostName := ApexName
+	if !IsApexName(d.Get("name").(string)) {
+		sanitizedHostName, err = SanitizeHostName(d.Get("name").(string))
+		if err != nil {
+			return nil, err
+		}
+		sanitizedHostName = OwnerName(conf, sanitizedHostName)
+	}
 	sanitizedRecordType, err := SanitiseTFInput(d, "type")
 	if err != nil {
 		return nil, err
 	}
+	// ttl is empty until the records are read, when it is left unset.
+	var ttl int64
+	if v := d.Get("ttl").(string); v != "" {
+		ttl, err = ParseTTL(v)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return &Record{
-		ZoneName:   sanitizedZoneName,
-		HostName:   sanitizedHostName,
-		RecordType: sanitizedRecordType,
-		CreatePtr:  d.Get("create_ptr").(bool),
-		//		TTL:        d.Get("ttl").(int64),
-		Records: records,
+		ZoneName:               sanitizedZoneName,
+		HostName:               sanitizedHostName,
+		RecordType:             sanitizedRecordType,
+		CreatePtr:              d.Get("create_ptr").(bool),
+		PtrZoneName:            d.Get("ptr_zone_name").(string),
+		VirtualizationInstance: d.Get("virtualization_instance").(string),
+		ZoneScope:              d.Get("zone_scope").(string),
+		OrderedRecords:         d.Get("ordered_records").(bool),
+		ManagePtrLifecycle:     d.Get("manage_ptr_lifecycle").(bool),
+		SkipMissingPtrZone:     d.Get("missing_ptr_zone").(string) == MissingPtrZoneWarn,
+		ExplicitTXTSegments:    d.Get("explicit_txt_segments").(bool),
+		TrimWhitespace:         d.Get("trim_whitespace").(bool),
+		AppendOnly:             d.Get("append_only").(bool),
+		TTL:                    ttl,
+		Records:                records,
 	}, nil
 }
 
-func GetDNSRecordFromId(ctx context.Context, conf *config.ProviderConf, id string) (*Record, error) {
-	idComponents := strings.Split(id, IDSeparator)
-	hostName := idComponents[0]
-	zoneName := idComponents[1]
-	recordType := idComponents[2]
-	createPtr, err := strconv.ParseBool("false")
-
-	if len(idComponents) > 3 {
-		createPtr, err = strconv.ParseBool(idComponents[3])
+// NewDNSRecordFromMap returns a new Record struct in zoneName populated from a map with the name, type and records
+// attributes, as used by the record blocks of windns_records. The name is given the provider's name prefix and suffix.
+func NewDNSRecordFromMap(conf *config.ProviderConf, zoneName string, m map[string]interface{}) (*Record, error) {
+	recordType, err := SanitizeInputString("", m["type"].(string))
+	if err != nil {
+		return nil, err
 	}
+	recordType = strings.ToUpper(recordType)
+
+	records, err := sanitizeRecordList(recordType, m["records"].(*schema.Set).List(), false, false, false)
 	if err != nil {
-		return nil, fmt.Errorf("unknown state for createPtr: %s", err)
+		return nil, err
 	}
 
-	// TODO better error handling here. Test import.
+	sanitizedZoneName, err := SanitizeZoneName(zoneName)
+	if err != nil {
+		return nil, err
+	}
+	sanitizedHostName := ApexName
+	if !IsApexName(m["name"].(string)) {
+		sanitizedHostName, err = SanitizeHostName(m["name"].(string))
+		if err != nil {
+			return nil, err
+		}
+		sanitizedHostName = OwnerName(conf, sanitizedHostName)
+	}
 
-	cmd := fmt.Sprintf("Get-DnsServerResourceRecord -ZoneName %s -Name %s -RRType %s", zoneName, hostName, recordType)
+	return &Record{
+		ZoneName:   sanitizedZoneName,
+		HostName:   sanitizedHostName,
+		RecordType: recordType,
+		Records:    records,
+	}, nil
+}
 
-	conn, err := conf.AcquireSshClient()
+func GetDNSRecordFromId(ctx context.Context, conf *config.ProviderConf, id string) (*Record, error) {
+	scope, err := ParseRecordID(id)
 	if err != nil {
-		return nil, fmt.Errorf("while acquiring ssh client: %s", err)
+		return nil, err
 	}
-	defer conf.ReleaseSshClient(conn)
+	hostName, zoneName, recordType, createPtr := scope.HostName, scope.ZoneName, scope.RecordType, scope.CreatePtr
+
+	params := map[string]any{
+		"ZoneName": zoneName,
+		"Name":     hostName,
+	}
+	scope.typeParams(params)
+	scope.scopeParams(params)
 
 	psOpts := CreatePSCommandOpts{
 		JSONOutput: true,
@@ -130,29 +426,48 @@ func GetDNSRecordFromId(ctx context.Context, conf *config.ProviderConf, id strin
 		Password:   conf.Settings.SshPassword,
 		Server:     conf.Settings.DnsServer,
 	}
-	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	psCmd, err := NewPSCommand("Get-DnsServerResourceRecord", params, psOpts)
+	if err != nil {
+		return nil, err
+	}
 
-	result, err := psCmd.Run(conf)
+	result, err := psCmd.Run(ctx, conf)
 	if err != nil {
 		return nil, fmt.Errorf("ssh execution failure in GetDNSRecordFromId: %s", err)
 	}
 
 	if result.ExitCode != 0 {
-		return nil, fmt.Errorf("Get-DnsServerResourceRecord exited with a non zero exit code (%d), stderr: %s", result.ExitCode, result.StdErr)
+		return nil, newPSCommandError("Get-DnsServerResourceRecord", result)
+	}
+
+	if strings.TrimSpace(result.Stdout) == "" {
+		return nil, fmt.Errorf("ObjectNotFound: no %s records found for %q in zone %q", recordType, hostName, zoneName)
 	}
 
-	record, err := unmarshallRecord(ctx, []byte(result.Stdout))
+	record, err := unmarshallRecord(ctx, []byte(result.Stdout), recordType)
 	if err != nil {
 		return nil, fmt.Errorf("GetDNSRecordFromId: %s", err)
 	}
 
+	// DNS names are case-insensitive, so the name is kept as written in the ID when the server returns it in another
+	// casing.
+	if strings.EqualFold(record.HostName, hostName) {
+		record.HostName = hostName
+	}
 	record.ZoneName = zoneName
 	record.CreatePtr = createPtr
+	record.VirtualizationInstance = scope.VirtualizationInstance
+	record.ZoneScope = scope.ZoneScope
 	return record, nil
 }
 
+// IsNotFound reports whether err tells that the requested records or their zone do not exist.
+func IsNotFound(err error) bool {
+	return err != nil && notFoundPSError.MatchString(err.Error())
+}
+
 // Create creates a new DNSRecord object in DNS server
-func (r *Record) Create(conf *config.ProviderConf) (string, error) {
+func (r *Record) Create(ctx context.Context, conf *config.ProviderConf) (string, error) {
 	if r.ZoneName == "" {
 		return "", fmt.Errorf("DNSRecord.Create: missing zone_name variable")
 	}
@@ -169,8 +484,11 @@ func (r *Record) Create(conf *config.ProviderConf) (string, error) {
 		return "", fmt.Errorf("DNSRecord.Create: missing record variable")
 	}
 
+	if err := r.checkPtrZones(ctx, conf, r.Records); err != nil {
+		return "", err
+	}
 	for _, recordData := range r.Records {
-		err := r.addRecordData(conf, recordData)
+		err := r.addRecordData(ctx, conf, recordData)
 		if err != nil {
 			return "", err
 		}
@@ -183,33 +501,81 @@ func (r *Record) Create(conf *config.ProviderConf) (string, error) {
 // Update updates an existing DNSRecord object in DNS server
 func (r *Record) Update(ctx context.Context, conf *config.ProviderConf, changes map[string]interface{}) error {
 	existing, err := GetDNSRecordFromId(ctx, conf, r.Id())
+	if IsNotFound(err) {
+		// No records of the name and type exist, e.g. after records was emptied with allow_empty.
+		existing, err = &Record{RecordType: r.RecordType}, nil
+	}
 	if err != nil {
 		return err
 	}
-	if changes["records"] == nil {
-		return nil
+	if changes["records"] != nil {
+		records, err := sanitizeRecordList(existing.RecordType, changes["records"].([]interface{}), r.ExplicitTXTSegments, r.TrimWhitespace, r.OrderedRecords)
+		if err != nil {
+			return err
+		}
+		existingRecords := RecordValues(existing.RecordType, existing.Records, r.ExplicitTXTSegments)
+		if r.AppendOnly {
+			// Only the records listed now or previously, given in previous_records, are removed when missing from records.
+			managed := records
+			if previous, ok := changes["previous_records"].([]interface{}); ok {
+				for _, v := range previous {
+					managed = append(managed, v.(string))
+				}
+			}
+			existingRecords = FilterRecordData(existing.RecordType, existingRecords, managed)
+		}
+		err = r.updateRecordData(ctx, conf, records, existingRecords)
+		if err != nil {
+			return err
+		}
 	}
-	var records []string
-	expectedRecords := changes["records"].(*schema.Set)
-
-	for _, v := range expectedRecords.List() {
-		sanitizedInput, err := SanitizeInputString(existing.RecordType, v.(string))
+	// Without records there is no TTL to set, the TTL is given to the records when they are added.
+	if changes["ttl"] != nil && len(r.Records) > 0 {
+		ttl, err := ParseTTL(changes["ttl"].(string))
 		if err != nil {
 			return err
 		}
-		records = append(records, sanitizedInput)
+		return r.setTTL(ctx, conf, ttl)
 	}
+	return nil
+}
 
-	toAdd, toRemove := diffRecordLists(records, existing.Records)
+// updateRecordData adds and removes record data so the records on the server, existing, become records.
+func (r *Record) updateRecordData(ctx context.Context, conf *config.ProviderConf, records, existing []string) error {
+	if r.OrderedRecords {
+		// Records out of place are removed before being added again, as adding a record that exists fails.
+		toAdd, toRemove := diffOrderedRecordLists(r.RecordType, records, existing)
+		if err := r.checkPtrZones(ctx, conf, toAdd); err != nil {
+			return err
+		}
+		for _, recordData := range toRemove {
+			err := r.removeRecordData(ctx, conf, recordData)
+			if err != nil {
+				return err
+			}
+		}
+		for _, recordData := range toAdd {
+			err := r.addRecordData(ctx, conf, recordData)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	toAdd, toRemove := diffRecordLists(r.RecordType, records, existing)
+	if err := r.checkPtrZones(ctx, conf, toAdd); err != nil {
+		return err
+	}
 	for _, recordData := range toAdd {
-		err = r.addRecordData(conf, recordData)
+		err := r.addRecordData(ctx, conf, recordData)
 		if err != nil {
 			return err
 		}
 	}
 
 	for _, recordData := range toRemove {
-		err = r.removeRecordData(conf, recordData)
+		err := r.removeRecordData(ctx, conf, recordData)
 		if err != nil {
 			return err
 		}
@@ -217,10 +583,62 @@ func (r *Record) Update(ctx context.Context, conf *config.ProviderConf, changes
 	return nil
 }
 
-// Delete deletes an existing DNSRecord object in DNS server
-func (r *Record) Delete(conf *config.ProviderConf) error {
+// setTTLScript sets the TTL of every record of a name and type, given in seconds, as Set-DnsServerResourceRecord
+// changes one record at a time.
+const setTTLScript = `$ErrorActionPreference = 'Stop'; ` +
+	`$scope = @{ ZoneName = $params.ZoneName }; ` +
+	`foreach ($name in 'ComputerName', 'VirtualizationInstance', 'ZoneScope') { if ($params.ContainsKey($name)) { $scope[$name] = $params[$name] } }; ` +
+	`$type = if ($params.ContainsKey('Type')) { @{ Type = $params.Type } } else { @{ RRType = $params.RRType } }; ` +
+	`foreach ($old in @(Get-DnsServerResourceRecord @scope @type -Name $params.Name)) { ` +
+	`$new = $old.Clone(); $new.TimeToLive = [TimeSpan]::FromSeconds($params.TimeToLive); ` +
+	`Set-DnsServerResourceRecord @scope -OldInputObject $old -NewInputObject $new }`
+
+// setTTL sets the TTL of all records of r on the server to ttl seconds.
+func (r *Record) setTTL(ctx context.Context, conf *config.ProviderConf, ttl int64) error {
+	params := map[string]any{
+		"ZoneName":   r.ZoneName,
+		"Name":       r.HostName,
+		"TimeToLive": ttl,
+	}
+	r.typeParams(params)
+	r.scopeParams(params)
+
+	psOpts := CreatePSCommandOpts{
+		Username: conf.Settings.SshUsername,
+		Password: conf.Settings.SshPassword,
+		Server:   conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSScript("Set-DnsServerResourceRecord", setTTLScript, params, psOpts)
+	if err != nil {
+		return err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("ssh execution failure while setting the TTL of a DNS object: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return newPSCommandError("Set-DnsServerResourceRecord", result)
+	}
+	return nil
+}
+
+// Delete deletes an existing DNSRecord object in DNS server.
+// Record data that is already gone is skipped.
+func (r *Record) Delete(ctx context.Context, conf *config.ProviderConf) error {
+	if IsProtectedRecord(r.HostName, r.RecordType) {
+		return fmt.Errorf("refusing to remove the %s records at the apex of zone %q, as the zone does not work without them", r.RecordType, r.ZoneName)
+	}
 	for _, recordData := range r.Records {
-		err := r.removeRecordData(conf, recordData)
+		err := r.removeRecordData(ctx, conf, recordData)
+		if IsNotFound(err) {
+			tflog.Debug(ctx, "Record data to delete was not found, assuming it was already deleted", map[string]any{
+				"id":          r.Id(),
+				"record_data": recordData,
+				"error":       err.Error(),
+			})
+			continue
+		}
 		if err != nil {
 			return err
 		}
@@ -228,25 +646,46 @@ func (r *Record) Delete(conf *config.ProviderConf) error {
 	return nil
 }
 
-func (r *Record) addRecordData(conf *config.ProviderConf, recordData string) error {
-	cmd := fmt.Sprintf("Add-DNSServerResourceRecord -ZoneName %s -name %s -%s", r.ZoneName, r.HostName, r.RecordType)
+func (r *Record) addRecordData(ctx context.Context, conf *config.ProviderConf, recordData string) error {
+	if dnscmdRecordTypes[r.RecordType] {
+		return r.addRecordDataWithDnscmd(ctx, conf, recordData)
+	}
 
-	if r.RecordType == RecordTypeA {
-		cmd = fmt.Sprintf("%s -IPv4Address %s", cmd, recordData)
-	} else if r.RecordType == RecordTypeAAAA {
-		cmd = fmt.Sprintf("%s -IPv6Address %s", cmd, strings.ToLower(recordData))
-	} else if r.RecordType == RecordTypeTXT {
-		cmd = fmt.Sprintf("%s -DescriptiveText \"%s\"", cmd, recordData)
-	} else if r.RecordType == RecordTypePTR {
-		cmd = fmt.Sprintf("%s -PtrDomainName %s", cmd, recordData)
-	} else if r.RecordType == RecordTypeCNAME {
-		cmd = fmt.Sprintf("%s -HostNameAlias %s", cmd, recordData)
+	params := map[string]any{
+		"ZoneName": r.ZoneName,
+		"Name":     r.HostName,
+	}
+	if number, ok := rrTypeNumbers[r.RecordType]; ok {
+		params["Type"] = number
 	} else {
-		return fmt.Errorf("record type %s is not supported", r.RecordType)
+		params[r.RecordType] = true
 	}
+	r.scopeParams(params)
 
-	if (r.RecordType == RecordTypeA || r.RecordType == RecordTypeAAAA) && r.CreatePtr {
-		cmd = fmt.Sprintf("%s -CreatePtr", cmd)
+	if r.RecordType == RecordTypeAAAA {
+		recordData = strings.ToLower(recordData)
+	}
+	recordData, err := r.serverRecordData(recordData)
+	if err != nil {
+		return err
+	}
+
+	dataParams, err := recordDataParams(r.RecordType, recordData)
+	if err != nil {
+		return err
+	}
+	for k, v := range dataParams {
+		params[k] = v
+	}
+
+	if r.TTL > 0 {
+		params["TimeToLive"] = formatTimeSpan(r.TTL)
+	}
+
+	createPtr := (r.RecordType == RecordTypeA || r.RecordType == RecordTypeAAAA) && r.CreatePtr &&
+		!slices.ContainsFunc(r.SkippedPtrAddresses, func(address string) bool { return strings.EqualFold(address, recordData) })
+	if createPtr && r.PtrZoneName == "" {
+		params["CreatePtr"] = true
 	}
 
 	psOpts := CreatePSCommandOpts{
@@ -256,28 +695,248 @@ func (r *Record) addRecordData(conf *config.ProviderConf, recordData string) err
 		Password:   conf.Settings.SshPassword,
 		Server:     conf.Settings.DnsServer,
 	}
-	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	psCmd, err := NewPSCommand("Add-DnsServerResourceRecord", params, psOpts)
+	if err != nil {
+		return err
+	}
 
-	result, err := psCmd.Run(conf)
+	result, err := psCmd.Run(ctx, conf)
 	if err != nil {
 		return fmt.Errorf("ssh execution failure while creating a DNS object: %s", err)
 	}
 
 	if result.ExitCode != 0 {
-		fmt.Printf("CMD: %s", psCmd.String())
-		return fmt.Errorf("Add-DnsServerResourceRecord exited with a non zero exit code (%d), stderr: %s", result.ExitCode, result.StdErr)
+		return newPSCommandError("Add-DnsServerResourceRecord", result)
+	}
+
+	if createPtr && r.PtrZoneName != "" {
+		return r.addPtrRecord(ctx, conf, recordData)
 	}
 	return nil
 }
 
-func (r *Record) removeRecordData(conf *config.ProviderConf, recordData string) error {
-	cmd := fmt.Sprintf("Remove-DnsServerResourceRecord -Force -ZoneName %s -RRType %s -Name %s -RecordData \"%s\"", r.ZoneName, r.RecordType, r.HostName, recordData)
+// The missing_ptr_zone setting of windns_record, choosing how addresses added with CreatePtr are handled when their
+// reverse zone does not exist.
+const (
+	MissingPtrZoneWarn  = "warn"
+	MissingPtrZoneError = "error"
+)
+
+// checkPtrZones verifies that a reverse zone on the DNS server covers each of addresses, before they are added with
+// CreatePtr set, see MissingPtrZones. Without a reverse zone the server would add the address and fail to add its PTR
+// record, so uncovered addresses fail before any record is added, or with SkipMissingPtrZone are added without a PTR
+// record and listed in SkippedPtrAddresses. Zones of virtualization instances are not listed, so their records are not
+// checked.
+func (r *Record) checkPtrZones(ctx context.Context, conf *config.ProviderConf, addresses []string) error {
+	r.SkippedPtrAddresses = nil
+	if !r.CreatePtr || (r.RecordType != RecordTypeA && r.RecordType != RecordTypeAAAA) || r.VirtualizationInstance != "" {
+		return nil
+	}
+
+	missing, err := MissingPtrZones(ctx, conf, r.PtrZoneName, addresses)
+	if err != nil || len(missing) == 0 {
+		return err
+	}
+	if r.SkipMissingPtrZone {
+		r.SkippedPtrAddresses = missing
+		return nil
+	}
+	if r.PtrZoneName != "" {
+		return fmt.Errorf("the reverse zone %q for the PTR records of %s does not exist on the DNS server", r.PtrZoneName, strings.Join(missing, ", "))
+	}
+	return fmt.Errorf("no reverse zone for %s on the DNS server, so its PTR record cannot be created", strings.Join(missing, ", "))
+}
+
+// MissingPtrZones returns the addresses whose PTR records cannot be created, as their reverse zone does not exist on
+// the DNS server. The reverse zone is ptrZoneName when set, and otherwise the zone the DNS server would add the PTR
+// record to, see ReverseZoneFor.
+func MissingPtrZones(ctx context.Context, conf *config.ProviderConf, ptrZoneName string, addresses []string) ([]string, error) {
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+	names, err := zoneNames(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, address := range addresses {
+		covered := names[strings.ToLower(strings.TrimSuffix(ptrZoneName, "."))]
+		if ptrZoneName == "" {
+			_, covered = ReverseZoneFor(address, names)
+		}
+		if !covered {
+			missing = append(missing, address)
+		}
+	}
+	return missing, nil
+}
+
+// addPtrRecord adds the PTR record for address to the reverse zone PtrZoneName.
+func (r *Record) addPtrRecord(ctx context.Context, conf *config.ProviderConf, address string) error {
+	ptrName, err := ReverseNameInZone(address, r.PtrZoneName)
+	if err != nil {
+		return err
+	}
+
+	ptr := Record{
+		ZoneName:               r.PtrZoneName,
+		HostName:               ptrName,
+		RecordType:             RecordTypePTR,
+		VirtualizationInstance: r.VirtualizationInstance,
+		TTL:                    r.TTL,
+	}
+	return ptr.addRecordData(ctx, conf, r.ptrDomainName())
+}
+
+// ptrDomainName returns the fully qualified name of r, which its PTR records point to.
+func (r *Record) ptrDomainName() string {
+	domainName := fmt.Sprintf("%s.", strings.TrimSuffix(r.ZoneName, "."))
+	if r.HostName != ApexName {
+		domainName = fmt.Sprintf("%s.%s", r.HostName, domainName)
+	}
+	return domainName
+}
+
+// removePtrRecord removes the PTR record for address, from PtrZoneName or otherwise the reverse zone the DNS server
+// added it to. PTR records that are already gone, or whose reverse zone does not exist, are skipped.
+func (r *Record) removePtrRecord(ctx context.Context, conf *config.ProviderConf, address string) error {
+	ptrZoneName := r.PtrZoneName
+	if ptrZoneName == "" {
+		names, err := zoneNames(ctx, conf)
+		if err != nil {
+			return err
+		}
+		var ok bool
+		ptrZoneName, ok = ReverseZoneFor(address, names)
+		if !ok {
+			tflog.Debug(ctx, "No reverse zone covers the address, skipping removal of its PTR record", map[string]any{
+				"id":      r.Id(),
+				"address": address,
+			})
+			return nil
+		}
+	}
+
+	ptrName, err := ReverseNameInZone(address, ptrZoneName)
+	if err != nil {
+		return err
+	}
+	ptr := Record{
+		ZoneName:               ptrZoneName,
+		HostName:               ptrName,
+		RecordType:             RecordTypePTR,
+		VirtualizationInstance: r.VirtualizationInstance,
+	}
+	err = ptr.removeRecordData(ctx, conf, r.ptrDomainName())
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// GetPtrRecord returns the PTR records for address in zoneName, or otherwise in the reverse zone the DNS server adds
+// them to. The records are empty when the address has no PTR records, and the zone name is empty as well when no
+// reverse zone covers the address.
+func GetPtrRecord(ctx context.Context, conf *config.ProviderConf, address, zoneName string) (*Record, error) {
+	if zoneName == "" {
+		names, err := zoneNames(ctx, conf)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		zoneName, ok = ReverseZoneFor(address, names)
+		if !ok {
+			return &Record{RecordType: RecordTypePTR, Records: []string{}}, nil
+		}
+	}
+
+	ptrName, err := ReverseNameInZone(address, zoneName)
+	if err != nil {
+		return nil, err
+	}
+	ptr := Record{ZoneName: zoneName, HostName: ptrName, RecordType: RecordTypePTR, Records: []string{}}
+	record, err := GetDNSRecordFromId(ctx, conf, ptr.Id())
+	if err != nil {
+		if IsNotFound(err) {
+			return &ptr, nil
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+// addDnscmdRecordScript adds the record data given in $params with dnscmd.exe, failing with its output if it fails.
+const addDnscmdRecordScript = `$server = if ($params.ContainsKey('ComputerName')) { $params.ComputerName } else { '.' }; ` +
+	`$ttl = if ($params.ContainsKey('TTL')) { @($params.TTL) } else { @() }; ` +
+	`$output = & dnscmd.exe $server /RecordAdd $params.ZoneName $params.Name @ttl $params.RRType @($params.RecordData) 2>&1; ` +
+	`if ($LASTEXITCODE -ne 0) { [Console]::Error.WriteLine(($output | Out-String)); exit $LASTEXITCODE }`
+
+// addRecordDataWithDnscmd adds record data of the types Add-DnsServerResourceRecord cannot create.
+// Domain names are passed fully qualified, as dnscmd.exe treats names without the trailing dot as relative to the zone.
+func (r *Record) addRecordDataWithDnscmd(ctx context.Context, conf *config.ProviderConf, recordData string) error {
+	if r.VirtualizationInstance != "" {
+		return fmt.Errorf("%s records cannot be created in a virtualization instance", r.RecordType)
+	}
+	if r.ZoneScope != "" {
+		return fmt.Errorf("%s records cannot be created in a zone scope", r.RecordType)
+	}
+
+	values, err := recordDataValues(r.RecordType, NormalizeRecordData(r.RecordType, recordData))
+	if err != nil {
+		return err
+	}
+	params := map[string]any{
+		"ZoneName":   r.ZoneName,
+		"Name":       r.HostName,
+		"RRType":     r.RecordType,
+		"RecordData": values,
+	}
+	if r.TTL > 0 {
+		params["TTL"] = r.TTL
+	}
+
+	psOpts := CreatePSCommandOpts{
+		Username: conf.Settings.SshUsername,
+		Password: conf.Settings.SshPassword,
+		Server:   conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSScript("dnscmd.exe", addDnscmdRecordScript, params, psOpts)
+	if err != nil {
+		return err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("ssh execution failure while creating a DNS object: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return newPSCommandError("dnscmd.exe", result)
+	}
+	return nil
+}
 
-	conn, err := conf.AcquireSshClient()
+func (r *Record) removeRecordData(ctx context.Context, conf *config.ProviderConf, recordData string) error {
+	recordData, err := r.serverRecordData(recordData)
 	if err != nil {
-		return fmt.Errorf("while acquiring ssh client: %s", err)
+		return err
+	}
+	params := map[string]any{
+		"Force":      true,
+		"ZoneName":   r.ZoneName,
+		"Name":       r.HostName,
+		"RecordData": recordData,
 	}
-	defer conf.ReleaseSshClient(conn)
+	r.typeParams(params)
+	r.scopeParams(params)
+	if IsMultiFieldRecordType(r.RecordType) {
+		values, err := recordDataValues(r.RecordType, recordData)
+		if err != nil {
+			return err
+		}
+		params["RecordData"] = values
+	}
+
 	psOpts := CreatePSCommandOpts{
 		JSONOutput: false,
 		ForceArray: false,
@@ -286,23 +945,155 @@ func (r *Record) removeRecordData(conf *config.ProviderConf, recordData string)
 		Server:     conf.Settings.DnsServer,
 	}
 
-	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	psCmd, err := NewPSCommand("Remove-DnsServerResourceRecord", params, psOpts)
+	if err != nil {
+		return err
+	}
 
-	result, err := psCmd.Run(conf)
+	result, err := psCmd.Run(ctx, conf)
 	if err != nil {
 		return fmt.Errorf("ssh execution failure while removing record object: %s", err)
 	}
 	if result.ExitCode != 0 {
-		return fmt.Errorf("Remove-DnsServerResourceRecord exited with a non zero exit code (%d), stderr: %s", result.ExitCode, result.StdErr)
+		return newPSCommandError("Remove-DnsServerResourceRecord", result)
+	}
+
+	// Remove-DnsServerResourceRecord leaves the PTR records added along with A and AAAA records in place.
+	if (r.RecordType == RecordTypeA || r.RecordType == RecordTypeAAAA) && r.CreatePtr && r.ManagePtrLifecycle {
+		return r.removePtrRecord(ctx, conf, recordData)
 	}
 	return nil
 }
 
-// handle if powershell returns single object or list of objects.
-func unmarshallRecord(ctx context.Context, input []byte) (*Record, error) {
-	var err error
+// ParseRecordDN returns the record name and zone of the distinguished name of a record's node in an AD-integrated zone,
+// e.g. DC=www,DC=example.com,CN=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com.
+func ParseRecordDN(dn string) (string, string, error) {
+	components := strings.Split(dn, ",")
+	if len(components) < 3 {
+		return "", "", fmt.Errorf("%q is not the distinguished name of a record", dn)
+	}
+
+	var values []string
+	for _, c := range components[:2] {
+		key, value, ok := strings.Cut(strings.TrimSpace(c), "=")
+		if !ok || !strings.EqualFold(key, "DC") || value == "" {
+			return "", "", fmt.Errorf("%q is not the distinguished name of a record, expected it to start with DC=<name>,DC=<zone_name>", dn)
+		}
+		values = append(values, value)
+	}
+	if !strings.EqualFold(strings.TrimSpace(components[2]), "CN=MicrosoftDNS") {
+		return "", "", fmt.Errorf("%q is not the distinguished name of a record, expected the zone to be followed by CN=MicrosoftDNS", dn)
+	}
+	return values[0], values[1], nil
+}
+
+// IsRecordDN reports whether id looks like the distinguished name of a record, rather than a resource ID.
+func IsRecordDN(id string) bool {
+	return len(id) > 3 && strings.EqualFold(id[:3], "DC=")
+}
+
+// GetDNSRecords returns all records in zoneName, grouped by name and type, with a single command.
+func GetDNSRecords(ctx context.Context, conf *config.ProviderConf, zoneName string) ([]*Record, error) {
+	zoneRecords, err := getZoneRecords(ctx, conf, zoneName, "GetDNSRecords")
+	if err != nil {
+		return nil, err
+	}
+
+	records := groupRecords(zoneRecords)
+	for _, r := range records {
+		r.ZoneName = zoneName
+	}
+	return records, nil
+}
+
+// getZoneRecords returns all records in zoneName as returned by the server, one per record, with a single command.
+// caller names the function reading the records in errors.
+func getZoneRecords(ctx context.Context, conf *config.ProviderConf, zoneName, caller string) ([]DNSRecord, error) {
+	psOpts := CreatePSCommandOpts{
+		JSONOutput: true,
+		JSONDepth:  4,
+		ForceArray: true,
+		Username:   conf.Settings.SshUsername,
+		Password:   conf.Settings.SshPassword,
+		Server:     conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSCommand("Get-DnsServerResourceRecord", map[string]any{"ZoneName": zoneName}, psOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return nil, fmt.Errorf("ssh execution failure in %s: %s", caller, err)
+	}
+
+	if result.ExitCode != 0 {
+		return nil, newPSCommandError("Get-DnsServerResourceRecord", result)
+	}
+
+	if strings.TrimSpace(result.Stdout) == "" {
+		return nil, nil
+	}
+
+	records, err := unmarshallDNSRecords(ctx, []byte(result.Stdout))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", caller, err)
+	}
+	return records, nil
+}
+
+// GetDNSRecordTypes returns the distinct record types present at hostName in zoneName.
+func GetDNSRecordTypes(ctx context.Context, conf *config.ProviderConf, zoneName, hostName string) ([]string, error) {
+	params := map[string]any{
+		"ZoneName": zoneName,
+		"Name":     hostName,
+	}
+
+	psOpts := CreatePSCommandOpts{
+		JSONOutput: true,
+		JSONDepth:  1,
+		ForceArray: true,
+		Username:   conf.Settings.SshUsername,
+		Password:   conf.Settings.SshPassword,
+		Server:     conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSCommand("Get-DnsServerResourceRecord", params, psOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return nil, fmt.Errorf("ssh execution failure in GetDNSRecordTypes: %s", err)
+	}
+
+	if result.ExitCode != 0 {
+		return nil, newPSCommandError("Get-DnsServerResourceRecord", result)
+	}
+
+	doc, err := jsonDocument([]byte(result.Stdout))
+	if err != nil {
+		return nil, fmt.Errorf("GetDNSRecordTypes: %s", err)
+	}
+
 	var records []DNSRecord
+	err = json.Unmarshal(doc, &records)
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Failed to unmarshall an DNSRecord json document with error %q, document was %s", err, result.Stdout))
+		return nil, fmt.Errorf("failed while unmarshalling DNSRecord json document: %s", err)
+	}
+
+	var types []string
+	for _, r := range records {
+		if !recordExistsInList(r.recordType(), types) {
+			types = append(types, r.recordType())
+		}
+	}
+	return types, nil
+}
 
+// jsonDocument strips any leading noise from the powershell output and returns the JSON document.
+func jsonDocument(input []byte) ([]byte, error) {
 	t := bytes.TrimSpace(input)
 	if len(t) == 0 {
 		return nil, fmt.Errorf("empty json document")
@@ -313,7 +1104,21 @@ func unmarshallRecord(ctx context.Context, input []byte) (*Record, error) {
 		return nil, fmt.Errorf("no JSON object found in input")
 	}
 
-	jsonStart := t[startIdx:]
+	return t[startIdx:], nil
+}
+
+// handle if powershell returns single object or list of objects.
+// unmarshallRecord returns the records of recordType returned by Get-DnsServerResourceRecord for a single name. Records
+// of other types are left out, so the records of one type are never mixed with those of other types at the same name,
+// and having none of the type is an ObjectNotFound error.
+func unmarshallRecord(ctx context.Context, input []byte, recordType string) (*Record, error) {
+	var err error
+	var records []DNSRecord
+
+	jsonStart, err := jsonDocument(input)
+	if err != nil {
+		return nil, err
+	}
 
 	err = json.Unmarshal(jsonStart, &records)
 	if err != nil {
@@ -325,22 +1130,80 @@ func unmarshallRecord(ctx context.Context, input []byte) (*Record, error) {
 		return nil, fmt.Errorf("invalid data while unmarshalling DNSRecord data, json doc was: %s", string(input))
 	}
 
+	records = slices.DeleteFunc(records, func(v DNSRecord) bool {
+		return !strings.EqualFold(v.recordType(), recordType)
+	})
+	if len(records) == 0 {
+		tflog.Debug(ctx, "Records of other types returned, ignoring them", map[string]any{"type": recordType, "document": string(input)})
+		return nil, fmt.Errorf("ObjectNotFound: no %s records found in the records returned", recordType)
+	}
+
 	var rs []string
 	for _, v := range records {
-		recordData := v.RecordData.CimInstanceProperties[0].Value
+		recordData := formatRecordData(v.recordType(), v.RecordData.CimInstanceProperties)
 		rs = append(rs, recordData)
 	}
 
 	record := Record{
 		HostName:   records[0].HostName,
-		RecordType: records[0].RecordType,
-		//		TTL:        records[0].TimeToLive.TotalSeconds,
-		Records: rs,
+		RecordType: records[0].recordType(),
+		TTL:        commonTTL(records),
+		Records:    rs,
+		DN:         records[0].DN,
+		Timestamp:  latestTimestamp(records),
 	}
 
 	return &record, nil
 }
 
+// unmarshallRecords groups the records returned by Get-DnsServerResourceRecord by name and type, in the order returned.
+func unmarshallRecords(ctx context.Context, input []byte) ([]*Record, error) {
+	records, err := unmarshallDNSRecords(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return groupRecords(records), nil
+}
+
+func unmarshallDNSRecords(ctx context.Context, input []byte) ([]DNSRecord, error) {
+	var records []DNSRecord
+
+	doc, err := jsonDocument(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(doc, &records)
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Failed to unmarshall an DNSRecord json document with error %q, document was %s", err, string(input)))
+		return nil, fmt.Errorf("failed while unmarshalling DNSRecord json document: %s", err)
+	}
+	return records, nil
+}
+
+// groupRecords groups the records returned by the server by name and type, in the order the server returned them.
+func groupRecords(records []DNSRecord) []*Record {
+	var grouped []*Record
+	index := make(map[string]*Record)
+	members := make(map[*Record][]DNSRecord)
+	for _, v := range records {
+		key := strings.ToLower(v.HostName) + IDSeparator + v.recordType()
+		r, ok := index[key]
+		if !ok {
+			r = &Record{HostName: v.HostName, RecordType: v.recordType(), DN: v.DN}
+			index[key] = r
+			grouped = append(grouped, r)
+		}
+		r.Records = append(r.Records, formatRecordData(v.recordType(), v.RecordData.CimInstanceProperties))
+		members[r] = append(members[r], v)
+	}
+	for _, r := range grouped {
+		r.TTL = commonTTL(members[r])
+		r.Timestamp = latestTimestamp(members[r])
+	}
+	return grouped
+}
+
 func recordExistsInList(r string, list []string) bool {
 	for _, item := range list {
 		if r == item {
@@ -350,19 +1213,88 @@ func recordExistsInList(r string, list []string) bool {
 	return false
 }
 
-func diffRecordLists(expectedRecords, existingRecords []string) ([]string, []string) {
+// createRank orders record types so that records are created after the records they refer to: addresses first, as
+// the targets of e.g. NS and CNAME records, and PTR records, which point back at the addresses, last.
+func createRank(recordType string) int {
+	switch recordType {
+	case RecordTypeA, RecordTypeAAAA:
+		return 0
+	case RecordTypePTR:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// SortRecordsForCreate sorts records in the order they are created in: A and AAAA records first, PTR records last,
+// and the others in between. Records of the same rank are sorted by name and type, so the order is the same in every
+// run. Records are deleted in the reverse order.
+func SortRecordsForCreate(records []*Record) {
+	slices.SortFunc(records, func(a, b *Record) int {
+		if rank := createRank(a.RecordType) - createRank(b.RecordType); rank != 0 {
+			return rank
+		}
+		if c := strings.Compare(strings.ToLower(a.HostName), strings.ToLower(b.HostName)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.RecordType, b.RecordType)
+	})
+}
+
+// sameRecordData reports whether a and b are the same record data, comparing them in their normalized form, and
+// ignoring case unless the record type is case-sensitive.
+func sameRecordData(recordType, a, b string) bool {
+	a, b = NormalizeRecordData(recordType, a), NormalizeRecordData(recordType, b)
+	return a == b || (!IsCaseSensitiveRecordType(recordType) && strings.EqualFold(a, b))
+}
+
+// recordDataInList reports whether list holds recordData, comparing the record data in its normalized form.
+func recordDataInList(recordType, recordData string, list []string) bool {
+	for _, item := range list {
+		if sameRecordData(recordType, recordData, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterRecordData returns the records in keep, comparing the record data in its normalized form.
+func FilterRecordData(recordType string, records, keep []string) []string {
+	var filtered []string
+	for _, v := range records {
+		if recordDataInList(recordType, v, keep) {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
+
+// diffRecordLists returns the records to add and remove for existingRecords to match expectedRecords.
+// Record data written in different forms, e.g. a domain name with and without the trailing dot, is considered equal.
+func diffRecordLists(recordType string, expectedRecords, existingRecords []string) ([]string, []string) {
 	var toAdd, toRemove []string
 
 	for _, record := range expectedRecords {
-		if !recordExistsInList(record, existingRecords) {
+		if !recordDataInList(recordType, record, existingRecords) {
 			toAdd = append(toAdd, record)
 		}
 	}
 
 	for _, record := range existingRecords {
-		if !recordExistsInList(record, expectedRecords) {
+		if !recordDataInList(recordType, record, expectedRecords) {
 			toRemove = append(toRemove, record)
 		}
 	}
 	return toAdd, toRemove
 }
+
+// diffOrderedRecordLists returns the records to add and remove, in order, for existingRecords to end up in the order
+// of expectedRecords. The server appends added records, so every existing record after the first one out of place is
+// removed and added again.
+func diffOrderedRecordLists(recordType string, expectedRecords, existingRecords []string) ([]string, []string) {
+	i := 0
+	for i < len(expectedRecords) && i < len(existingRecords) && sameRecordData(recordType, expectedRecords[i], existingRecords[i]) {
+		i++
+	}
+	return expectedRecords[i:], existingRecords[i:]
+}
@@ -0,0 +1,526 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"test-nil", nil, false},
+		{"test-object-not-found", errors.New("Get-DnsServerResourceRecord exited with a non zero exit code (1), stderr: + CategoryInfo : ObjectNotFound: (www:root/Microsoft/...erResourceRecord) [Get-DnsServerResourceRecord], CimException"), true},
+		{"test-win32-9714", errors.New("Remove-DnsServerResourceRecord exited with a non zero exit code (1), stderr: + FullyQualifiedErrorId : WIN32 9714,Remove-DnsServerResourceRecord"), true},
+		{"test-other", errors.New("Remove-DnsServerResourceRecord exited with a non zero exit code (1), stderr: Access is denied."), false},
+		{
+			"test-localized",
+			newPSCommandError("Get-DnsServerResourceRecord", &PSCommandResult{
+				ExitCode: 1,
+				StdErr: "FullyQualifiedErrorId : WIN32 9714,Get-DnsServerResourceRecord, CategoryInfo : ObjectNotFound, Exception : Microsoft.Management.Infrastructure.CimException\r\n" +
+					"Get-DnsServerResourceRecord : Der DNS-Name ist nicht vorhanden.",
+			}),
+			true,
+		},
+		{"test-other-category", errors.New("stderr: FullyQualifiedErrorId : WIN32 5,Get-DnsServerZone, CategoryInfo : PermissionDenied"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsNotFound(tt.err); got != tt.want {
+				t.Errorf("IsNotFound() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffOrderedRecordLists(t *testing.T) {
+	tests := []struct {
+		name         string
+		recordType   string
+		expected     []string
+		existing     []string
+		wantToAdd    []string
+		wantToRemove []string
+	}{
+		{"test-unchanged", RecordTypeA, []string{"203.0.113.11", "203.0.113.12"}, []string{"203.0.113.11", "203.0.113.12"}, []string{}, []string{}},
+		{"test-appended", RecordTypeA, []string{"203.0.113.11", "203.0.113.12"}, []string{"203.0.113.11"}, []string{"203.0.113.12"}, []string{}},
+		{"test-reordered", RecordTypeA, []string{"203.0.113.12", "203.0.113.11", "203.0.113.13"}, []string{"203.0.113.11", "203.0.113.12", "203.0.113.13"}, []string{"203.0.113.12", "203.0.113.11", "203.0.113.13"}, []string{"203.0.113.11", "203.0.113.12", "203.0.113.13"}},
+		{"test-tail-reordered", RecordTypeA, []string{"203.0.113.11", "203.0.113.13", "203.0.113.12"}, []string{"203.0.113.11", "203.0.113.12", "203.0.113.13"}, []string{"203.0.113.13", "203.0.113.12"}, []string{"203.0.113.12", "203.0.113.13"}},
+		{"test-normalized", RecordTypeAFSDB, []string{"1 a.example.com", "1 b.example.com"}, []string{"1 A.example.com.", "1 b.example.com."}, []string{}, []string{}},
+		{"test-case-txt", RecordTypeTXT, []string{"abc", "def"}, []string{"abc", "DEF"}, []string{"def"}, []string{"DEF"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toAdd, toRemove := diffOrderedRecordLists(tt.recordType, tt.expected, tt.existing)
+			if !reflect.DeepEqual(toAdd, tt.wantToAdd) {
+				t.Errorf("diffOrderedRecordLists() toAdd = %v, want %v", toAdd, tt.wantToAdd)
+			}
+			if !reflect.DeepEqual(toRemove, tt.wantToRemove) {
+				t.Errorf("diffOrderedRecordLists() toRemove = %v, want %v", toRemove, tt.wantToRemove)
+			}
+		})
+	}
+}
+
+func TestDiffRecordLists(t *testing.T) {
+	tests := []struct {
+		name         string
+		recordType   string
+		expected     []string
+		existing     []string
+		wantToAdd    []string
+		wantToRemove []string
+	}{
+		{"test-unchanged", RecordTypeA, []string{"203.0.113.11"}, []string{"203.0.113.11"}, nil, nil},
+		{"test-changed", RecordTypeA, []string{"203.0.113.12"}, []string{"203.0.113.11"}, []string{"203.0.113.12"}, []string{"203.0.113.11"}},
+		{"test-ptr-without-dot", RecordTypePTR, []string{"example-host.example.com"}, []string{"example-host.example.com."}, nil, nil},
+		{"test-cname-case", RecordTypeCNAME, []string{"Example-Host.example.com."}, []string{"example-host.example.com."}, nil, nil},
+		{"test-txt-case", RecordTypeTXT, []string{"Token=AbC"}, []string{"token=abc"}, []string{"Token=AbC"}, []string{"token=abc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toAdd, toRemove := diffRecordLists(tt.recordType, tt.expected, tt.existing)
+			if !reflect.DeepEqual(toAdd, tt.wantToAdd) {
+				t.Errorf("diffRecordLists() toAdd = %v, want %v", toAdd, tt.wantToAdd)
+			}
+			if !reflect.DeepEqual(toRemove, tt.wantToRemove) {
+				t.Errorf("diffRecordLists() toRemove = %v, want %v", toRemove, tt.wantToRemove)
+			}
+		})
+	}
+}
+
+func TestFilterRecordData(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordType string
+		records    []string
+		keep       []string
+		want       []string
+	}{
+		{"test-others-dropped", RecordTypeA, []string{"203.0.113.11", "203.0.113.12"}, []string{"203.0.113.12", "203.0.113.13"}, []string{"203.0.113.12"}},
+		{"test-normalized", RecordTypePTR, []string{"Example-Host.example.com."}, []string{"example-host.example.com"}, []string{"Example-Host.example.com."}},
+		{"test-txt-case", RecordTypeTXT, []string{"Token=AbC"}, []string{"token=abc"}, nil},
+		{"test-none-kept", RecordTypeA, []string{"203.0.113.11"}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FilterRecordData(tt.recordType, tt.records, tt.keep); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterRecordData() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshallRecords(t *testing.T) {
+	input := `[
+  {
+    "DistinguishedName": "DC=www,DC=example.com,cn=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com",
+    "HostName": "www",
+    "RecordType": "A",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "IPv4Address", "value": "203.0.113.11" } ] },
+    "TimeToLive": { "TotalSeconds": 3600 }
+  },
+  {
+    "DistinguishedName": "DC=www,DC=example.com,cn=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com",
+    "HostName": "www",
+    "RecordType": "TXT",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "DescriptiveText", "value": "hello" } ] }
+  },
+  {
+    "DistinguishedName": "DC=WWW,DC=example.com,cn=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com",
+    "HostName": "WWW",
+    "RecordType": "A",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "IPv4Address", "value": "203.0.113.12" } ] },
+    "TimeToLive": { "TotalSeconds": 3600 }
+  },
+  {
+    "DistinguishedName": "DC=mail,DC=example.com,cn=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com",
+    "HostName": "mail",
+    "RecordType": "A",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "IPv4Address", "value": "203.0.113.21" } ] },
+    "TimeToLive": { "TotalSeconds": 300 }
+  },
+  {
+    "DistinguishedName": "DC=mail,DC=example.com,cn=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com",
+    "HostName": "mail",
+    "RecordType": "A",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "IPv4Address", "value": "203.0.113.22" } ] },
+    "TimeToLive": { "TotalSeconds": 600 }
+  }
+]`
+
+	got, err := unmarshallRecords(context.Background(), []byte(input))
+	if err != nil {
+		t.Fatalf("unmarshallRecords() error = %v", err)
+	}
+
+	dn := "DC=www,DC=example.com,cn=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com"
+	want := []*Record{
+		{HostName: "www", RecordType: "A", DN: dn, TTL: 3600, Records: []string{"203.0.113.11", "203.0.113.12"}},
+		{HostName: "www", RecordType: "TXT", DN: dn, Records: []string{"hello"}},
+		// The records of mail have different TTLs, so they have no common TTL.
+		{HostName: "mail", RecordType: "A", DN: strings.Replace(dn, "www", "mail", 1), Records: []string{"203.0.113.21", "203.0.113.22"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unmarshallRecords() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTimestampUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"test-null", `null`, time.Time{}, false},
+		{"test-windows-powershell", `"\/Date(1704103200000)\/"`, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), false},
+		{"test-powershell-7", `"2024-01-01T10:00:00"`, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), false},
+		{"test-powershell-7-offset", `"2024-01-01T11:00:00+01:00"`, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), false},
+		{"test-invalid", `"yesterday"`, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Timestamp
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Timestamp.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Timestamp.UnmarshalJSON() = %v, want %v", got.Time, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordDynamic(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp string
+		want      bool
+	}{
+		{"test-static", `null`, false},
+		{"test-dynamic", `"\/Date(1704103200000)\/"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := fmt.Sprintf(`[{"HostName": "www", "RecordType": "A", "Timestamp": %s, `+
+				`"RecordData": {"CimInstanceProperties": [{"Name": "IPv4Address", "value": "203.0.113.11"}]}}]`, tt.timestamp)
+			record, err := unmarshallRecord(context.Background(), []byte(input), RecordTypeA)
+			if err != nil {
+				t.Fatalf("unmarshallRecord() error = %v", err)
+			}
+			if got := record.Dynamic(); got != tt.want {
+				t.Errorf("Record.Dynamic() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRecordDN(t *testing.T) {
+	tests := []struct {
+		name         string
+		dn           string
+		wantHostName string
+		wantZoneName string
+		wantErr      bool
+	}{
+		{"test-domain-partition", "DC=www,DC=example.com,cn=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com", "www", "example.com", false},
+		{"test-apex", "DC=@,DC=example.com,CN=MicrosoftDNS,DC=ForestDnsZones,DC=example,DC=com", "@", "example.com", false},
+		{"test-subdomain", "dc=host.sub,dc=example.com,CN=MicrosoftDNS,CN=System,DC=example,DC=com", "host.sub", "example.com", false},
+		{"test-deep-name", "DC=a.b.c,DC=example.com,CN=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com", "a.b.c", "example.com", false},
+		{"test-not-a-record", "DC=example,DC=com", "", "", true},
+		{"test-not-dns", "DC=www,DC=example.com,CN=Users,DC=example,DC=com", "", "", true},
+		{"test-cn-node", "CN=www,DC=example.com,CN=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hostName, zoneName, err := ParseRecordDN(tt.dn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRecordDN() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if hostName != tt.wantHostName || zoneName != tt.wantZoneName {
+				t.Errorf("ParseRecordDN() = %q, %q, want %q, %q", hostName, zoneName, tt.wantHostName, tt.wantZoneName)
+			}
+		})
+	}
+}
+
+func TestParseRecordID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    *Record
+		wantErr bool
+	}{
+		{"test-simple", "www_example.com_A_false", &Record{HostName: "www", ZoneName: "example.com", RecordType: "A"}, false},
+		{"test-apex", "@_example.com_NS_false", &Record{HostName: "@", ZoneName: "example.com", RecordType: "NS"}, false},
+		{"test-create-ptr", "www_example.com_AAAA_true", &Record{HostName: "www", ZoneName: "example.com", RecordType: "AAAA", CreatePtr: true}, false},
+		{"test-acme-challenge", "_acme-challenge.www_example.com_TXT_false", &Record{HostName: "_acme-challenge.www", ZoneName: "example.com", RecordType: "TXT"}, false},
+		{"test-acme-challenge-apex", "_acme-challenge_example.com_TXT_false", &Record{HostName: "_acme-challenge", ZoneName: "example.com", RecordType: "TXT"}, false},
+		{"test-service", "_sip._tcp_example.com_SRV_false", &Record{HostName: "_sip._tcp", ZoneName: "example.com", RecordType: "SRV"}, false},
+		{"test-underscore-zone", "_ldap._tcp.dc__msdcs.example.com_SRV_false", &Record{HostName: "_ldap._tcp.dc", ZoneName: "_msdcs.example.com", RecordType: "SRV"}, false},
+		{"test-underscore-in-label", "my_host_example.com_A_false", &Record{HostName: "my_host", ZoneName: "example.com", RecordType: "A"}, false},
+		{"test-without-create-ptr", "_acme-challenge.www_example.com_TXT", &Record{HostName: "_acme-challenge.www", ZoneName: "example.com", RecordType: "TXT"}, false},
+		{"test-virtualization-instance", "_acme-challenge_example.com_TXT_false_vi1", &Record{HostName: "_acme-challenge", ZoneName: "example.com", RecordType: "TXT", VirtualizationInstance: "vi1"}, false},
+		{"test-zone-scope", "_acme-challenge_example.com_TXT_false__eu", &Record{HostName: "_acme-challenge", ZoneName: "example.com", RecordType: "TXT", ZoneScope: "eu"}, false},
+		{"test-short-form", "_acme-challenge.www_example.com", nil, true},
+		{"test-no-zone", "www_A_false", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRecordID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRecordID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRecordID() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTimeSpan(t *testing.T) {
+	tests := []struct {
+		seconds int64
+		want    string
+	}{
+		{0, "0.00:00:00"},
+		{59, "0.00:00:59"},
+		{300, "0.00:05:00"},
+		{3600, "0.01:00:00"},
+		{86400, "1.00:00:00"},
+		{90061, "1.01:01:01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatTimeSpan(tt.seconds); got != tt.want {
+				t.Errorf("formatTimeSpan(%d) = %q, want %q", tt.seconds, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTTL(t *testing.T) {
+	tests := []struct {
+		ttl     string
+		want    int64
+		wantErr bool
+	}{
+		{"3600", 3600, false},
+		{"45s", 45, false},
+		{"1h30m", 5400, false},
+		{"1w1d", 691200, false},
+		{"2147483647", 2147483647, false},
+		{"2147483648", 0, true},
+		{"h", 0, true},
+		{"1h 30m", 0, true},
+		{"-1", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ttl, func(t *testing.T) {
+			got, err := ParseTTL(tt.ttl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTTL(%q) error = %v, wantErr %v", tt.ttl, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTTL(%q) = %d, want %d", tt.ttl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwnerNameAndRecordName(t *testing.T) {
+	conf := config.NewProviderConf(&config.Settings{NamePrefix: "dev-", NameSuffix: ".env"})
+	tests := []struct {
+		name   string
+		conf   *config.ProviderConf
+		record string
+		owner  string
+	}{
+		{"test-affixes", conf, "www", "dev-www.env"},
+		{"test-apex", conf, "@", "@"},
+		{"test-apex-empty", conf, "", ""},
+		{"test-no-conf", nil, "www", "www"},
+		{"test-no-affixes", config.NewProviderConf(&config.Settings{}), "www", "www"},
+		{"test-suffix-only", config.NewProviderConf(&config.Settings{NameSuffix: "-dev"}), "www", "www-dev"},
+		{"test-wildcard", conf, "*", "*"},
+		{"test-wildcard-subdomain", conf, "*.www", "*.dev-www.env"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OwnerName(tt.conf, tt.record); got != tt.owner {
+				t.Errorf("OwnerName() = %q, want %q", got, tt.owner)
+			}
+			if got := RecordName(tt.conf, tt.owner); got != tt.record {
+				t.Errorf("RecordName() = %q, want %q", got, tt.record)
+			}
+		})
+	}
+
+	readTests := []struct {
+		name  string
+		owner string
+		want  string
+	}{
+		{"test-mixed-case", "DEV-www.ENV", "www"},
+		{"test-missing-prefix", "www.env", "www.env"},
+		{"test-missing-suffix", "dev-www", "dev-www"},
+		{"test-only-affixes", "dev-.env", "dev-.env"},
+	}
+
+	for _, tt := range readTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RecordName(conf, tt.owner); got != tt.want {
+				t.Errorf("RecordName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeHostName(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostName string
+		wantErr  bool
+	}{
+		{"test-name", "www", false},
+		{"test-subdomain", "www.sub", false},
+		{"test-wildcard", "*", false},
+		{"test-wildcard-subdomain", "*.sub", false},
+		{"test-wildcard-not-leading", "www.*", true},
+		{"test-wildcard-in-label", "w*w", true},
+		{"test-double-wildcard", "*.*", true},
+		{"test-illegal-character", "www;", true},
+		{"test-wildcard-illegal-character", "*.www;", true},
+		{"test-classless-delegation", "0/26", false},
+		{"test-classless-name", "11.0/26", false},
+		{"test-slash", "www/26", true},
+		{"test-invalid-idn", "a\u05d0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SanitizeHostName(tt.hostName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SanitizeHostName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.hostName {
+				t.Errorf("SanitizeHostName() = %q, want %q", got, tt.hostName)
+			}
+		})
+	}
+}
+
+func TestIsProtectedRecord(t *testing.T) {
+	tests := []struct {
+		name       string
+		hostName   string
+		recordType string
+		want       bool
+	}{
+		{"test-apex-soa", "@", RecordTypeSOA, true},
+		{"test-apex-ns", "@", RecordTypeNS, true},
+		{"test-empty-name-ns", "", "ns", true},
+		{"test-apex-a", "@", RecordTypeA, false},
+		{"test-delegation-ns", "sub", RecordTypeNS, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsProtectedRecord(tt.hostName, tt.recordType); got != tt.want {
+				t.Errorf("IsProtectedRecord() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordDelete_Protected(t *testing.T) {
+	conf, executor := newFakeConf()
+	record := Record{ZoneName: "example.com", HostName: ApexName, RecordType: RecordTypeNS, Records: []string{"dc01.example.com."}}
+
+	if err := record.Delete(context.Background(), conf); err == nil {
+		t.Errorf("Delete() of the apex NS records did not fail")
+	}
+	if len(executor.scripts) != 0 {
+		t.Errorf("Delete() of the apex NS records ran %d commands, want none", len(executor.scripts))
+	}
+}
+
+func TestSortRecordsForCreate(t *testing.T) {
+	records := []*Record{
+		{HostName: "10", RecordType: RecordTypePTR},
+		{HostName: "www", RecordType: RecordTypeCNAME},
+		{HostName: "sub", RecordType: RecordTypeNS},
+		{HostName: "ns1.sub", RecordType: RecordTypeAAAA},
+		{HostName: "ns1.sub", RecordType: RecordTypeA},
+		{HostName: "Host", RecordType: RecordTypeA},
+	}
+
+	SortRecordsForCreate(records)
+	var got []string
+	for _, r := range records {
+		got = append(got, r.HostName+" "+r.RecordType)
+	}
+	want := []string{"Host A", "ns1.sub A", "ns1.sub AAAA", "sub NS", "www CNAME", "10 PTR"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortRecordsForCreate() = %q, want %q", got, want)
+	}
+}
+
+func TestSanitizeRecordList(t *testing.T) {
+	tests := []struct {
+		name           string
+		recordType     string
+		records        []interface{}
+		trimWhitespace bool
+		ordered        bool
+		want           []string
+		wantErr        bool
+	}{
+		{"test-trim-a", RecordTypeA, []interface{}{" 203.0.113.11 ", "203.0.113.12\n"}, true, false, []string{"203.0.113.11", "203.0.113.12"}, false},
+		{"test-untrimmed-a", RecordTypeA, []interface{}{"203.0.113.11 "}, false, false, nil, true},
+		{"test-trim-txt", RecordTypeTXT, []interface{}{"  v=spf1  include:example.com -all "}, true, false, []string{"v=spf1  include:example.com -all"}, false},
+		{"test-untrimmed-txt", RecordTypeTXT, []interface{}{" v=spf1 -all "}, false, false, []string{" v=spf1 -all "}, false},
+		{"test-duplicate", RecordTypeA, []interface{}{"203.0.113.11", "203.0.113.12", "203.0.113.11"}, false, false, []string{"203.0.113.11", "203.0.113.12"}, false},
+		{"test-trimmed-duplicate", RecordTypeA, []interface{}{"203.0.113.11", "203.0.113.11 "}, true, false, []string{"203.0.113.11"}, false},
+		{"test-case-duplicate-txt", RecordTypeTXT, []interface{}{"abc", "ABC"}, false, false, []string{"abc", "ABC"}, false},
+		{"test-duplicate-ordered", RecordTypeA, []interface{}{"203.0.113.11", "203.0.113.12", "203.0.113.11"}, false, true, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitizeRecordList(tt.recordType, tt.records, false, tt.trimWhitespace, tt.ordered)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sanitizeRecordList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sanitizeRecordList() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,39 +4,192 @@ package dnshelper
 
 import (
 	"fmt"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
 )
 
 var recordInputPattern = regexp.MustCompile(`^[a-zA-Z0-9:.\-_]+$`)
 
+// classlessReverseNamePattern matches the names of RFC 2317 classless reverse zones and the names within them, e.g. the
+// zone 0/26.113.0.203.in-addr.arpa, the CNAME target 11.0/26.113.0.203.in-addr.arpa. or the delegation 0/26 in the
+// zone 113.0.203.in-addr.arpa. Their `/` is not otherwise allowed.
+var classlessReverseNamePattern = regexp.MustCompile(`(?i)^([0-9]+\.)?[0-9]+/[0-9]+((\.[0-9]+){1,3}\.in-addr\.arpa\.?)?$`)
+
 func SanitizeInputString(recordType string, input string) (string, error) {
 	if recordType == "TXT" {
 		if len(input) > 255 {
 			return "", fmt.Errorf("TXT record can only be 255 characters long")
 		}
-		return escapePowerShellInput(input), nil
+		// Record values are passed to the remote host as parameters rather than
+		// as part of the script, so TXT data needs no escaping.
+		return input, nil
 	}
 
-	if recordInputPattern.MatchString(input) {
+	if recordInputPattern.MatchString(input) || classlessReverseNamePattern.MatchString(input) {
 		return input, nil
 	}
 	return "", fmt.Errorf("invalid characters detected in input: %s", input)
 }
 
+// SanitizeRecordData validates the record data of recordType. The fields of
+// multi-field record data are validated individually.
+func SanitizeRecordData(recordType string, input string) (string, error) {
+	if recordType == RecordTypeCERT {
+		return SanitizeCERTRecordData(input)
+	}
+	if recordType == RecordTypeDHCID {
+		return SanitizeDHCIDRecordData(input)
+	}
+	if !IsMultiFieldRecordType(recordType) {
+		return SanitizeInputString(recordType, input)
+	}
+
+	values, err := splitRecordData(recordType, input)
+	if err != nil {
+		return "", err
+	}
+	for i, v := range values {
+		values[i], err = SanitizeInputString(recordType, v)
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(values, " "), nil
+}
+
+// SanitizeZoneName validates the name of a zone, returning internationalized labels as A-labels.
+func SanitizeZoneName(zoneName string) (string, error) {
+	zoneName, err := ToASCIIName(zoneName)
+	if err != nil {
+		return "", err
+	}
+	return SanitizeInputString("", zoneName)
+}
+
+// IsApexName reports whether name refers to the zone apex, written either as "@" or as an empty name.
+func IsApexName(name string) bool {
+	return name == "" || name == ApexName
+}
+
+// IsProtectedRecord reports whether the records of hostName and recordType must never all be removed from their zone:
+// the SOA record and the NS records at the zone apex, without which the zone cannot be served or delegated to.
+func IsProtectedRecord(hostName, recordType string) bool {
+	if strings.EqualFold(recordType, RecordTypeSOA) {
+		return true
+	}
+	return IsApexName(hostName) && strings.EqualFold(recordType, RecordTypeNS)
+}
+
+// SanitizeHostName validates the name of a record. Besides the characters allowed by SanitizeInputString, the name may
+// start with a wildcard label, e.g. `*` or `*.sub`. Parameters are passed to the remote host encoded rather than as
+// part of the script, so the `*` is never expanded by a shell. Internationalized labels are returned as A-labels.
+func SanitizeHostName(name string) (string, error) {
+	name, err := ToASCIIName(name)
+	if err != nil {
+		return "", err
+	}
+	if name == WildcardLabel {
+		return name, nil
+	}
+	if rest, ok := strings.CutPrefix(name, WildcardLabel+"."); ok {
+		if _, err := SanitizeInputString("", rest); err != nil {
+			return "", err
+		}
+		return name, nil
+	}
+	return SanitizeInputString("", name)
+}
+
+// OwnerName returns the name of a record on the server, adding the provider's name prefix and suffix to name.
+// The zone apex has no name to add them to and is returned as is.
+func OwnerName(conf *config.ProviderConf, name string) string {
+	if conf == nil || IsApexName(name) || name == WildcardLabel {
+		return name
+	}
+	// The wildcard label must stay the leading label, so the prefix goes after it.
+	if rest, ok := strings.CutPrefix(name, WildcardLabel+"."); ok {
+		return WildcardLabel + "." + OwnerName(conf, rest)
+	}
+	return conf.Settings.NamePrefix + name + conf.Settings.NameSuffix
+}
+
+// RecordName returns the name of a record without the provider's name prefix and suffix, reversing OwnerName.
+// Names on the server lacking either of them, e.g. records created outside of Terraform, are returned as is.
+func RecordName(conf *config.ProviderConf, owner string) string {
+	if conf == nil || IsApexName(owner) || owner == WildcardLabel {
+		return owner
+	}
+	if rest, ok := strings.CutPrefix(owner, WildcardLabel+"."); ok {
+		return WildcardLabel + "." + RecordName(conf, rest)
+	}
+	prefix, suffix := conf.Settings.NamePrefix, conf.Settings.NameSuffix
+	if len(owner) <= len(prefix)+len(suffix) {
+		return owner
+	}
+	// DNS names are case-insensitive, so the prefix and suffix match in any casing.
+	if !strings.EqualFold(owner[:len(prefix)], prefix) || !strings.EqualFold(owner[len(owner)-len(suffix):], suffix) {
+		return owner
+	}
+	return owner[len(prefix) : len(owner)-len(suffix)]
+}
+
 func SanitiseTFInput(d *schema.ResourceData, key string) (string, error) {
 	return SanitizeInputString(d.Get("type").(string), d.Get(key).(string))
 }
 
-func escapePowerShellInput(input string) string {
-	replacer := strings.NewReplacer(
-		"`", "``",
-		";", "`;",
-		"&", "`&",
-		"(", "`(",
-		")", "`)",
-	)
-	return replacer.Replace(input)
+// formatTimeSpan formats seconds as a TimeSpan in the d.hh:mm:ss form PowerShell converts to a [TimeSpan] parameter.
+// A plain number would be taken as a count of ticks.
+func formatTimeSpan(seconds int64) string {
+	return fmt.Sprintf("%d.%02d:%02d:%02d", seconds/86400, seconds/3600%24, seconds/60%60, seconds%60)
+}
+
+// ttlUnits maps the units of TTLs written with units to their length in seconds.
+var ttlUnits = map[byte]int64{
+	's': 1,
+	'm': 60,
+	'h': 3600,
+	'd': 86400,
+	'w': 604800,
+}
+
+// ttlPattern matches TTLs written as a number of seconds, e.g. 3600, or as numbers with units, e.g. 1h or 1h30m.
+var ttlPattern = regexp.MustCompile(`^(?:[0-9]+|(?:[0-9]+[smhdw])+)$`)
+
+// ParseTTL returns the number of seconds of a TTL written either as a number of seconds, e.g. 3600, or as numbers with
+// the units s, m, h, d and w, e.g. 1h, 30m, 1d or 1h30m, as in BIND zone files. Units are case-insensitive.
+func ParseTTL(ttl string) (int64, error) {
+	ttl = strings.ToLower(strings.TrimSpace(ttl))
+	if !ttlPattern.MatchString(ttl) {
+		return 0, fmt.Errorf("invalid TTL %q, expected a number of seconds, e.g. 3600, or a duration with the units s, m, h, d and w, e.g. 1h or 1h30m", ttl)
+	}
+
+	var seconds, value int64
+	for i := 0; i < len(ttl); i++ {
+		c := ttl[i]
+		if c >= '0' && c <= '9' {
+			value = value*10 + int64(c-'0')
+		} else {
+			value *= ttlUnits[c]
+			seconds += value
+			value = 0
+		}
+		if value > math.MaxInt32 || seconds > math.MaxInt32 {
+			return 0, fmt.Errorf("TTL %q is longer than the largest TTL of %d seconds", ttl, math.MaxInt32)
+		}
+	}
+	seconds += value
+	if seconds > math.MaxInt32 {
+		return 0, fmt.Errorf("TTL %q is longer than the largest TTL of %d seconds", ttl, math.MaxInt32)
+	}
+	return seconds, nil
+}
+
+// FormatTTL writes a TTL of seconds in the canonical form of the ttl attribute, the number of seconds.
+func FormatTTL(seconds int64) string {
+	return strconv.FormatInt(seconds, 10)
 }
@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// SignZone signs zoneName with DNSSEC, using the server's default signing parameters.
+func SignZone(ctx context.Context, conf *config.ProviderConf, zoneName string) error {
+	params := map[string]any{
+		"ZoneName":        zoneName,
+		"SignWithDefault": true,
+		"Force":           true,
+	}
+	return runZoneCommand(ctx, conf, "Invoke-DnsServerZoneSign", params)
+}
+
+// UnsignZone removes the DNSSEC signatures and keys of zoneName.
+func UnsignZone(ctx context.Context, conf *config.ProviderConf, zoneName string) error {
+	params := map[string]any{
+		"ZoneName": zoneName,
+		"Force":    true,
+	}
+	return runZoneCommand(ctx, conf, "Invoke-DnsServerZoneUnsign", params)
+}
@@ -0,0 +1,612 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// fakeExecutor records the commands it is given, and answers them with outputs in order, or with an empty successful
+// output once outputs run out.
+type fakeExecutor struct {
+	outputs  []*config.CommandOutput
+	commands []string
+	scripts  []string
+	inputs   []string
+}
+
+func (f *fakeExecutor) Execute(ctx context.Context, command, stdin string) (*config.CommandOutput, error) {
+	f.commands = append(f.commands, command)
+	f.scripts = append(f.scripts, decodePSCommandLine(command))
+	f.inputs = append(f.inputs, stdin)
+	if len(f.outputs) == 0 {
+		return &config.CommandOutput{}, nil
+	}
+	output := f.outputs[0]
+	f.outputs = f.outputs[1:]
+	return output, nil
+}
+
+// params returns the parameters passed to the i'th command.
+func (f *fakeExecutor) params(t *testing.T, i int) map[string]any {
+	t.Helper()
+	if i >= len(f.scripts) {
+		t.Fatalf("got %d commands, want at least %d", len(f.scripts), i+1)
+	}
+	encoded := f.inputs[i]
+	if m := encodedParamsPattern.FindStringSubmatch(f.scripts[i]); m != nil {
+		encoded = m[1]
+	} else if encoded == "" {
+		t.Fatalf("no encoded parameters found in command: %s", f.scripts[i])
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("failed decoding parameters: %s", err)
+	}
+	var params map[string]any
+	if err := json.Unmarshal(decoded, &params); err != nil {
+		t.Fatalf("failed unmarshalling parameters: %s", err)
+	}
+	return params
+}
+
+// decodePSCommandLine returns the script of a command line built by encodedPSCommand.
+func decodePSCommandLine(command string) string {
+	fields := strings.Fields(command)
+	encoded, err := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+	if err != nil {
+		return command
+	}
+	units := make([]uint16, len(encoded)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(encoded[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// newFakeConf returns a provider configuration running commands with a fakeExecutor answering with outputs.
+func newFakeConf(outputs ...*config.CommandOutput) (*config.ProviderConf, *fakeExecutor) {
+	conf := config.NewProviderConf(&config.Settings{DnsServer: "dc01.example.com"})
+	executor := &fakeExecutor{outputs: outputs}
+	conf.Executor = executor
+	return conf, executor
+}
+
+func TestUpdate_NoChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(ctx context.Context, conf *config.ProviderConf, changes map[string]any) error
+	}{
+		{"test-forwarders", (&Forwarders{}).Update},
+		{"test-zone-transfer", (&ZoneTransfer{ZoneName: "example.com"}).Update},
+		{"test-server-scavenging", (&ServerScavenging{}).Update},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, changes := range []map[string]any{nil, {}} {
+				conf, executor := newFakeConf()
+				if err := tt.update(context.Background(), conf, changes); err != nil || len(executor.scripts) != 0 {
+					t.Errorf("Update(%v) ran %d commands, error = %v, want no command", changes, len(executor.scripts), err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetDNSRecordFromId_Executor(t *testing.T) {
+	// record returns the output of Get-DnsServerResourceRecord for a record with a single value.
+	record := func(hostName, recordType, property, value string, ttl int) string {
+		return fmt.Sprintf(`{"HostName":%q,"RecordType":%q,"RecordData":{"CimInstanceProperties":[{"Name":%q,"value":%q}]},"TimeToLive":{"TotalSeconds":%d}}`,
+			hostName, recordType, property, value, ttl)
+	}
+
+	tests := []struct {
+		name       string
+		id         string
+		output     string
+		want       *Record
+		wantParams map[string]any
+	}{
+		{
+			name:       "test-simple",
+			id:         "www_example.com_A_false",
+			output:     record("www", "A", "IPv4Address", "203.0.113.11", 3600),
+			want:       &Record{ZoneName: "example.com", HostName: "www", RecordType: "A", TTL: 3600, Records: []string{"203.0.113.11"}},
+			wantParams: map[string]any{"ZoneName": "example.com", "Name": "www", "RRType": "A", "ComputerName": "dc01.example.com"},
+		},
+		{
+			name:       "test-deep-name",
+			id:         "a.b.c_example.com_A_false",
+			output:     record("a.b.c", "A", "IPv4Address", "203.0.113.11", 3600),
+			want:       &Record{ZoneName: "example.com", HostName: "a.b.c", RecordType: "A", TTL: 3600, Records: []string{"203.0.113.11"}},
+			wantParams: map[string]any{"ZoneName": "example.com", "Name": "a.b.c", "RRType": "A", "ComputerName": "dc01.example.com"},
+		},
+		{
+			name:       "test-underscore-name",
+			id:         "_acme-challenge.www_example.com_TXT_false",
+			output:     record("_acme-challenge.www", "TXT", "DescriptiveText", "gfj9Xq-Kvx_8Z1c2d3e4f5g6h7i8j9k0l1m2n3o4p5q", 60),
+			want:       &Record{ZoneName: "example.com", HostName: "_acme-challenge.www", RecordType: "TXT", TTL: 60, Records: []string{"gfj9Xq-Kvx_8Z1c2d3e4f5g6h7i8j9k0l1m2n3o4p5q"}},
+			wantParams: map[string]any{"ZoneName": "example.com", "Name": "_acme-challenge.www", "RRType": "TXT", "ComputerName": "dc01.example.com"},
+		},
+		{
+			name:       "test-zone-scope",
+			id:         "www_example.com_A_false__branch",
+			output:     record("www", "A", "IPv4Address", "198.51.100.11", 3600),
+			want:       &Record{ZoneName: "example.com", HostName: "www", RecordType: "A", TTL: 3600, Records: []string{"198.51.100.11"}, ZoneScope: "branch"},
+			wantParams: map[string]any{"ZoneName": "example.com", "Name": "www", "RRType": "A", "ZoneScope": "branch", "ComputerName": "dc01.example.com"},
+		},
+		{
+			name:       "test-classless",
+			id:         "11_0/26.113.0.203.in-addr.arpa_PTR_false",
+			output:     record("11", "PTR", "PtrDomainName", "www.example.com.", 3600),
+			want:       &Record{ZoneName: "0/26.113.0.203.in-addr.arpa", HostName: "11", RecordType: "PTR", TTL: 3600, Records: []string{"www.example.com."}},
+			wantParams: map[string]any{"ZoneName": "0/26.113.0.203.in-addr.arpa", "Name": "11", "RRType": "PTR", "ComputerName": "dc01.example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf, executor := newFakeConf(&config.CommandOutput{Stdout: tt.output})
+
+			got, err := GetDNSRecordFromId(context.Background(), conf, tt.id)
+			if err != nil {
+				t.Fatalf("GetDNSRecordFromId() error = %s", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) || got.Id() != tt.id {
+				t.Errorf("GetDNSRecordFromId() = %+v with ID %q, want %+v with ID %q", got, got.Id(), tt.want, tt.id)
+			}
+
+			if !strings.Contains(executor.scripts[0], "Get-DnsServerResourceRecord @params | ConvertTo-Json") {
+				t.Errorf("command does not run Get-DnsServerResourceRecord: %s", executor.scripts[0])
+			}
+			if params := executor.params(t, 0); !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("params = %v, want %v", params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestGetDNSRecordFromId_ExecutorJSONDepth(t *testing.T) {
+	// The services of WKS records are a list in a record data property, nested deeper than the default depth of
+	// ConvertTo-Json, which would write the properties as their string form.
+	conf, executor := newFakeConf(&config.CommandOutput{Stdout: `{"HostName":"www","RecordType":"WKS",` +
+		`"RecordData":{"CimInstanceProperties":[{"Name":"InternetAddress","value":"203.0.113.11"},` +
+		`{"Name":"InternetProtocol","value":"TCP"},{"Name":"Service","value":["smtp","ftp"]}]},` +
+		`"TimeToLive":{"TotalSeconds":3600}}`})
+
+	got, err := GetDNSRecordFromId(context.Background(), conf, "www_example.com_WKS_false")
+	if err != nil {
+		t.Fatalf("GetDNSRecordFromId() error = %s", err)
+	}
+	want := &Record{ZoneName: "example.com", HostName: "www", RecordType: "WKS", TTL: 3600, Records: []string{"203.0.113.11 TCP smtp ftp"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDNSRecordFromId() = %+v, want %+v", got, want)
+	}
+
+	if !strings.Contains(executor.scripts[0], "| ConvertTo-Json -Depth 4 -Compress") {
+		t.Errorf("command does not serialize its output to an explicit depth: %s", executor.scripts[0])
+	}
+}
+
+func TestRecordCreate_ExecutorUnderscoreName(t *testing.T) {
+	conf, executor := newFakeConf()
+
+	r := &Record{ZoneName: "example.com", HostName: "_acme-challenge.www", RecordType: RecordTypeTXT, Records: []string{"gfj9Xq-Kvx_8Z1c2d3e4f5g6h7i8j9k0l1m2n3o4p5q"}}
+	id, err := r.Create(context.Background(), conf)
+	if err != nil {
+		t.Fatalf("Create() error = %s", err)
+	}
+	if id != "_acme-challenge.www_example.com_TXT_false" {
+		t.Errorf("Create() = %q, want %q", id, "_acme-challenge.www_example.com_TXT_false")
+	}
+
+	params := executor.params(t, 0)
+	if params["Name"] != "_acme-challenge.www" || params["DescriptiveText"] != "gfj9Xq-Kvx_8Z1c2d3e4f5g6h7i8j9k0l1m2n3o4p5q" {
+		t.Errorf("params = %v, want the name and data unchanged", params)
+	}
+}
+
+func TestGetDNSRecordFromId_ExecutorNotFound(t *testing.T) {
+	conf, _ := newFakeConf(&config.CommandOutput{
+		ExitCode: 1,
+		Stderr:   "FullyQualifiedErrorId : WIN32 9714,Get-DnsServerResourceRecord, CategoryInfo : ObjectNotFound, Exception : Microsoft.Management.Infrastructure.CimException\r\nDer DNS-Name ist nicht vorhanden.",
+	})
+
+	_, err := GetDNSRecordFromId(context.Background(), conf, "www_example.com_A_false")
+	if !IsNotFound(err) {
+		t.Errorf("GetDNSRecordFromId() error = %v, want a not found error", err)
+	}
+}
+
+func TestGetDNSRecordFromId_ExecutorMultipleTypes(t *testing.T) {
+	// The records of every type at the name, as if the type was not filtered on the server.
+	output := `[
+  {
+    "HostName": "host",
+    "RecordType": "A",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "IPv4Address", "value": "203.0.113.11" } ] },
+    "TimeToLive": { "TotalSeconds": 3600 }
+  },
+  {
+    "HostName": "host",
+    "RecordType": "TXT",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "DescriptiveText", "value": "v=spf1 -all" } ] },
+    "TimeToLive": { "TotalSeconds": 300 }
+  }
+]`
+	tests := []struct {
+		name        string
+		id          string
+		want        *Record
+		wantRRType  string
+		wantMissing bool
+	}{
+		{
+			name:       "test-a",
+			id:         "host_example.com_A_false",
+			want:       &Record{ZoneName: "example.com", HostName: "host", RecordType: "A", TTL: 3600, Records: []string{"203.0.113.11"}},
+			wantRRType: "A",
+		},
+		{
+			name:       "test-txt",
+			id:         "host_example.com_TXT_false",
+			want:       &Record{ZoneName: "example.com", HostName: "host", RecordType: "TXT", TTL: 300, Records: []string{"v=spf1 -all"}},
+			wantRRType: "TXT",
+		},
+		{
+			name:        "test-other-type",
+			id:          "host_example.com_AAAA_false",
+			wantRRType:  "AAAA",
+			wantMissing: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf, executor := newFakeConf(&config.CommandOutput{Stdout: output})
+			got, err := GetDNSRecordFromId(context.Background(), conf, tt.id)
+			if params := executor.params(t, 0); params["RRType"] != tt.wantRRType {
+				t.Errorf("params = %v, want RRType %s", params, tt.wantRRType)
+			}
+			if tt.wantMissing {
+				if !IsNotFound(err) {
+					t.Errorf("GetDNSRecordFromId() error = %v, want a not found error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetDNSRecordFromId() error = %s", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDNSRecordFromId() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPtrRecord_Executor(t *testing.T) {
+	conf, executor := newFakeConf(
+		&config.CommandOutput{Stdout: `[ { "ZoneName": "example.com" }, { "ZoneName": "113.0.203.in-addr.arpa" }, { "ZoneName": "0/26.113.0.203.in-addr.arpa" } ]`},
+		&config.CommandOutput{Stdout: `{
+  "HostName": "11",
+  "RecordType": "PTR",
+  "RecordData": { "CimInstanceProperties": [ { "Name": "PtrDomainName", "value": "www.example.com." } ] },
+  "TimeToLive": { "TotalSeconds": 3600 }
+}`})
+
+	got, err := GetPtrRecord(context.Background(), conf, "203.0.113.11", "")
+	if err != nil {
+		t.Fatalf("GetPtrRecord() error = %s", err)
+	}
+	want := &Record{ZoneName: "0/26.113.0.203.in-addr.arpa", HostName: "11", RecordType: "PTR", TTL: 3600, Records: []string{"www.example.com."}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetPtrRecord() = %+v, want %+v", got, want)
+	}
+	if params := executor.params(t, 1); params["ZoneName"] != "0/26.113.0.203.in-addr.arpa" || params["Name"] != "11" {
+		t.Errorf("params = %v, want record 11 in zone 0/26.113.0.203.in-addr.arpa", params)
+	}
+}
+
+func TestGetPtrRecord_ExecutorNotFound(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		zoneName string
+		outputs  []*config.CommandOutput
+		want     *Record
+	}{
+		{
+			name:    "test-no-reverse-zone",
+			address: "198.51.100.11",
+			outputs: []*config.CommandOutput{{Stdout: `[ { "ZoneName": "example.com" }, { "ZoneName": "113.0.203.in-addr.arpa" } ]`}},
+			want:    &Record{RecordType: "PTR", Records: []string{}},
+		},
+		{
+			name:     "test-no-ptr-record",
+			address:  "203.0.113.12",
+			zoneName: "113.0.203.in-addr.arpa",
+			outputs: []*config.CommandOutput{{
+				ExitCode: 1,
+				Stderr:   "FullyQualifiedErrorId : WIN32 9714,Get-DnsServerResourceRecord, CategoryInfo : ObjectNotFound",
+			}},
+			want: &Record{ZoneName: "113.0.203.in-addr.arpa", HostName: "12", RecordType: "PTR", Records: []string{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf, _ := newFakeConf(tt.outputs...)
+			got, err := GetPtrRecord(context.Background(), conf, tt.address, tt.zoneName)
+			if err != nil {
+				t.Fatalf("GetPtrRecord() error = %s", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPtrRecord() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordCreate_Executor(t *testing.T) {
+	conf, executor := newFakeConf()
+	r := &Record{ZoneName: "example.com", HostName: "www", RecordType: RecordTypeA, TTL: 300, Records: []string{"203.0.113.11", "203.0.113.12"}}
+
+	id, err := r.Create(context.Background(), conf)
+	if err != nil {
+		t.Fatalf("Create() error = %s", err)
+	}
+	if id != "www_example.com_A_false" {
+		t.Errorf("Create() = %q, want %q", id, "www_example.com_A_false")
+	}
+
+	if len(executor.scripts) != 2 {
+		t.Fatalf("got %d commands, want 2", len(executor.scripts))
+	}
+	for i, address := range r.Records {
+		params := executor.params(t, i)
+		if params["IPv4Address"] != address || params["A"] != true || params["TimeToLive"] != "0.00:05:00" {
+			t.Errorf("params of command %d = %v, want A record %s with TTL 0.00:05:00", i, params, address)
+		}
+	}
+}
+
+func TestRecord_ExecutorMultiplePTR(t *testing.T) {
+	conf, executor := newFakeConf()
+	r := &Record{ZoneName: "113.0.203.in-addr.arpa", HostName: "11", RecordType: RecordTypePTR, TTL: 3600, Records: []string{"www.example.com.", "mail.example.com"}}
+
+	if _, err := r.Create(context.Background(), conf); err != nil {
+		t.Fatalf("Create() error = %s", err)
+	}
+	if len(executor.scripts) != 2 {
+		t.Fatalf("got %d commands, want 2", len(executor.scripts))
+	}
+	for i, target := range r.Records {
+		params := executor.params(t, i)
+		if params["PtrDomainName"] != target || params["Name"] != "11" || params["PTR"] != true {
+			t.Errorf("params of command %d = %v, want PTR record 11 for %s", i, params, target)
+		}
+	}
+
+	// The server returns the records of the name in its own order, with the domain names fully qualified.
+	conf, _ = newFakeConf(&config.CommandOutput{Stdout: `[
+  {
+    "HostName": "11",
+    "RecordType": "PTR",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "PtrDomainName", "value": "mail.example.com." } ] },
+    "TimeToLive": { "TotalSeconds": 3600 }
+  },
+  {
+    "HostName": "11",
+    "RecordType": "PTR",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "PtrDomainName", "value": "www.example.com." } ] },
+    "TimeToLive": { "TotalSeconds": 3600 }
+  }
+]`})
+	got, err := GetDNSRecordFromId(context.Background(), conf, "11_113.0.203.in-addr.arpa_PTR_false")
+	if err != nil {
+		t.Fatalf("GetDNSRecordFromId() error = %s", err)
+	}
+	want := &Record{ZoneName: "113.0.203.in-addr.arpa", HostName: "11", RecordType: "PTR", TTL: 3600, Records: []string{"mail.example.com.", "www.example.com."}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDNSRecordFromId() = %+v, want %+v", got, want)
+	}
+}
+
+// testReverseZones lists a forward zone and a reverse zone covering 203.0.113.0/24, as returned by Get-DnsServerZone.
+const testReverseZones = `[
+  { "ZoneName": "example.com", "ZoneType": "Primary", "IsReverseLookupZone": false },
+  { "ZoneName": "113.0.203.in-addr.arpa", "ZoneType": "Primary", "IsReverseLookupZone": true }
+]`
+
+func TestRecordCreate_ExecutorMissingPtrZone(t *testing.T) {
+	conf, executor := newFakeConf(&config.CommandOutput{Stdout: testReverseZones})
+	r := &Record{ZoneName: "example.com", HostName: "www", RecordType: RecordTypeA, CreatePtr: true, Records: []string{"203.0.113.11", "198.51.100.11"}}
+
+	_, err := r.Create(context.Background(), conf)
+	if err == nil || !strings.Contains(err.Error(), "no reverse zone for 198.51.100.11") {
+		t.Fatalf("Create() error = %v, want no reverse zone for 198.51.100.11", err)
+	}
+	// Only the zones were listed, nothing was added.
+	if len(executor.scripts) != 1 {
+		t.Errorf("got %d commands, want only the zones to be listed", len(executor.scripts))
+	}
+}
+
+func TestRecordCreate_ExecutorSkipMissingPtrZone(t *testing.T) {
+	conf, executor := newFakeConf(&config.CommandOutput{Stdout: testReverseZones})
+	r := &Record{ZoneName: "example.com", HostName: "www", RecordType: RecordTypeA, CreatePtr: true, SkipMissingPtrZone: true, Records: []string{"203.0.113.11", "198.51.100.11"}}
+
+	if _, err := r.Create(context.Background(), conf); err != nil {
+		t.Fatalf("Create() error = %s", err)
+	}
+	if !reflect.DeepEqual(r.SkippedPtrAddresses, []string{"198.51.100.11"}) {
+		t.Errorf("SkippedPtrAddresses = %v, want [198.51.100.11]", r.SkippedPtrAddresses)
+	}
+	if len(executor.scripts) != 3 {
+		t.Fatalf("got %d commands, want the zones to be listed and 2 records added", len(executor.scripts))
+	}
+	if params := executor.params(t, 1); params["CreatePtr"] != true {
+		t.Errorf("params of 203.0.113.11 = %v, want CreatePtr", params)
+	}
+	if params := executor.params(t, 2); params["IPv4Address"] != "198.51.100.11" || params["CreatePtr"] != nil {
+		t.Errorf("params of 198.51.100.11 = %v, want no CreatePtr", params)
+	}
+}
+
+func TestRecordCreate_ExecutorMissingPtrZoneName(t *testing.T) {
+	conf, executor := newFakeConf(&config.CommandOutput{Stdout: testReverseZones})
+	r := &Record{ZoneName: "example.com", HostName: "www", RecordType: RecordTypeA, CreatePtr: true, PtrZoneName: "0/26.113.0.203.in-addr.arpa", Records: []string{"203.0.113.11"}}
+
+	_, err := r.Create(context.Background(), conf)
+	if err == nil || !strings.Contains(err.Error(), `reverse zone "0/26.113.0.203.in-addr.arpa" for the PTR records of 203.0.113.11 does not exist`) {
+		t.Fatalf("Create() error = %v, want the reverse zone to be missing", err)
+	}
+	if len(executor.scripts) != 1 {
+		t.Errorf("got %d commands, want only the zones to be listed", len(executor.scripts))
+	}
+}
+
+func TestRecordCreate_ExecutorSpecialCharacters(t *testing.T) {
+	conf, executor := newFakeConf()
+	txt := "say \"hi\" & 'bye' `$env:PATH` $(Remove-Item C:\\) æøå ☃ 🦀 %TEMP% ^|<>"
+	r := &Record{ZoneName: "example.com", HostName: "special", RecordType: RecordTypeTXT, Records: []string{txt}}
+
+	if _, err := r.Create(context.Background(), conf); err != nil {
+		t.Fatalf("Create() error = %s", err)
+	}
+
+	// The command line only holds the encoded script, so no character of the value reaches a shell.
+	for _, c := range executor.commands[0] {
+		if c > 127 || strings.ContainsRune("\"'`$&|<>^%", c) {
+			t.Fatalf("command line contains %q: %s", c, executor.commands[0])
+		}
+	}
+	if got := executor.params(t, 0)["DescriptiveText"]; got != txt {
+		t.Errorf("DescriptiveText = %q, want %q", got, txt)
+	}
+}
+
+func TestRecordUpdate_ExecutorAppendOnly(t *testing.T) {
+	conf, executor := newFakeConf(&config.CommandOutput{Stdout: `[
+  { "HostName": "www", "RecordType": "A", "RecordData": { "CimInstanceProperties": [ { "Name": "IPv4Address", "value": "203.0.113.11" } ] } },
+  { "HostName": "www", "RecordType": "A", "RecordData": { "CimInstanceProperties": [ { "Name": "IPv4Address", "value": "203.0.113.12" } ] } }
+]`})
+	r := &Record{ZoneName: "example.com", HostName: "www", RecordType: RecordTypeA, AppendOnly: true, Records: []string{"203.0.113.13"}}
+
+	changes := map[string]interface{}{
+		"records":          []interface{}{"203.0.113.13"},
+		"previous_records": []interface{}{"203.0.113.11"},
+	}
+	if err := r.Update(context.Background(), conf, changes); err != nil {
+		t.Fatalf("Update() error = %s", err)
+	}
+
+	// 203.0.113.12 was not added by the resource, so it is left in place.
+	if len(executor.scripts) != 3 {
+		t.Fatalf("got %d commands, want 3", len(executor.scripts))
+	}
+	if params := executor.params(t, 1); params["IPv4Address"] != "203.0.113.13" {
+		t.Errorf("params of the added record = %v, want 203.0.113.13", params)
+	}
+	if params := executor.params(t, 2); params["RecordData"] != "203.0.113.11" {
+		t.Errorf("params of the removed record = %v, want 203.0.113.11", params)
+	}
+}
+
+func TestRecordUpdate_ExecutorEmpty(t *testing.T) {
+	conf, executor := newFakeConf(&config.CommandOutput{Stdout: `[
+  { "HostName": "www", "RecordType": "A", "RecordData": { "CimInstanceProperties": [ { "Name": "IPv4Address", "value": "203.0.113.11" } ] } },
+  { "HostName": "www", "RecordType": "A", "RecordData": { "CimInstanceProperties": [ { "Name": "IPv4Address", "value": "203.0.113.12" } ] } }
+]`})
+	r := &Record{ZoneName: "example.com", HostName: "www", RecordType: RecordTypeA}
+
+	changes := map[string]interface{}{"records": []interface{}{}, "ttl": "300"}
+	if err := r.Update(context.Background(), conf, changes); err != nil {
+		t.Fatalf("Update() error = %s", err)
+	}
+
+	// Both records are removed, and there is no TTL left to set.
+	if len(executor.scripts) != 3 {
+		t.Fatalf("got %d commands, want 3", len(executor.scripts))
+	}
+	for i, address := range []string{"203.0.113.11", "203.0.113.12"} {
+		if params := executor.params(t, i+1); params["RecordData"] != address {
+			t.Errorf("params of removed record %d = %v, want %s", i, params, address)
+		}
+	}
+}
+
+func TestRecordUpdate_ExecutorNoRecords(t *testing.T) {
+	conf, executor := newFakeConf(&config.CommandOutput{
+		ExitCode: 1,
+		Stderr:   "FullyQualifiedErrorId : WIN32 9714,Get-DnsServerResourceRecord, CategoryInfo : ObjectNotFound",
+	})
+	r := &Record{ZoneName: "example.com", HostName: "www", RecordType: RecordTypeA, TTL: 300, Records: []string{"203.0.113.11"}}
+
+	changes := map[string]interface{}{"records": []interface{}{"203.0.113.11"}}
+	if err := r.Update(context.Background(), conf, changes); err != nil {
+		t.Fatalf("Update() error = %s", err)
+	}
+
+	if len(executor.scripts) != 2 {
+		t.Fatalf("got %d commands, want 2", len(executor.scripts))
+	}
+	if params := executor.params(t, 1); params["IPv4Address"] != "203.0.113.11" || params["TimeToLive"] != "0.00:05:00" {
+		t.Errorf("params of the added record = %v, want 203.0.113.11 with TTL 0.00:05:00", params)
+	}
+}
+
+func TestPSCommandRun_ExecutorRetriesBusy(t *testing.T) {
+	conf, executor := newFakeConf(
+		&config.CommandOutput{ExitCode: 1, Stderr: "FullyQualifiedErrorId : WIN32 9608,Add-DnsServerResourceRecord, CategoryInfo : ResourceBusy"},
+		&config.CommandOutput{Stdout: "[]"},
+	)
+	conf.Settings.BusyRetries = 1
+
+	psCmd, err := NewPSCommand("Get-DnsServerZone", nil, CreatePSCommandOpts{})
+	if err != nil {
+		t.Fatalf("NewPSCommand() error = %s", err)
+	}
+	result, err := psCmd.Run(context.Background(), conf)
+	if err != nil {
+		t.Fatalf("Run() error = %s", err)
+	}
+	if result.ExitCode != 0 || len(executor.scripts) != 2 {
+		t.Errorf("Run() exit code = %d after %d commands, want 0 after 2", result.ExitCode, len(executor.scripts))
+	}
+}
+
+func TestRecordCreate_ExecutorLongTXT(t *testing.T) {
+	conf, executor := newFakeConf()
+	long := strings.Repeat("v=DKIM1; k=rsa; p=MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA", 128)
+	r := &Record{ZoneName: "example.com", HostName: "dkim", RecordType: RecordTypeTXT, Records: []string{long}}
+
+	if _, err := r.Create(context.Background(), conf); err != nil {
+		t.Fatalf("Create() error = %s", err)
+	}
+
+	// cmd.exe limits command lines to 8191 characters, so the parameters are passed on stdin.
+	if len(executor.commands[0]) > 8191 {
+		t.Errorf("command line is %d characters long, want at most 8191", len(executor.commands[0]))
+	}
+	if executor.inputs[0] == "" {
+		t.Errorf("parameters of %d bytes were not passed on stdin", len(long))
+	}
+	text, _ := executor.params(t, 0)["DescriptiveText"].(string)
+	for _, v := range strings.Split(text, txtStringSeparator) {
+		if len(v) > maxTXTStringLength {
+			t.Fatalf("character-string of %d bytes, want at most %d", len(v), maxTXTStringLength)
+		}
+	}
+	if got := RecordValues(RecordTypeTXT, []string{text}, false); !reflect.DeepEqual(got, r.Records) {
+		t.Errorf("RecordValues() of the added record does not match the %d bytes long value", len(long))
+	}
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// Forwarders holds the server level forwarders of a DNS server, which resolve the queries the server cannot answer
+// from its zones, conditional forwarders or cache.
+type Forwarders struct {
+	// IPAddresses are the addresses of the forwarders, in the order the server queries them.
+	IPAddresses []string
+	// UseRootHint tells whether the server queries the root hints when none of the forwarders answer.
+	UseRootHint bool
+}
+
+// forwarders holds the fields we use from the object returned by Get-DnsServerForwarder.
+type forwarders struct {
+	IPAddress   []IPAddress `json:"IPAddress"`
+	UseRootHint bool        `json:"UseRootHint"`
+}
+
+// setForwardersScript runs Set-DnsServerForwarder with the parameters in $params. Set-DnsServerForwarder does not take
+// an empty list of addresses, so an empty IPAddress removes the current forwarders with Remove-DnsServerForwarder
+// instead.
+const setForwardersScript = `$ErrorActionPreference = 'Stop'; ` +
+	`$target = @{}; if ($params.ContainsKey('ComputerName')) { $target['ComputerName'] = $params['ComputerName'] }; ` +
+	`if ($params.ContainsKey('IPAddress') -and -not $params['IPAddress']) { $params.Remove('IPAddress'); ` +
+	`$current = (Get-DnsServerForwarder @target).IPAddress; ` +
+	`if ($current) { Remove-DnsServerForwarder @target -IPAddress $current -Force } }; ` +
+	`if ($params.Count -gt $target.Count) { Set-DnsServerForwarder @params }`
+
+// GetForwarders returns the server level forwarders of the DNS server.
+func GetForwarders(ctx context.Context, conf *config.ProviderConf) (*Forwarders, error) {
+	psOpts := CreatePSCommandOpts{
+		JSONOutput: true,
+		JSONDepth:  2,
+		Username:   conf.Settings.SshUsername,
+		Password:   conf.Settings.SshPassword,
+		Server:     conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSCommand("Get-DnsServerForwarder", nil, psOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return nil, fmt.Errorf("ssh execution failure in GetForwarders: %s", err)
+	}
+
+	if result.ExitCode != 0 {
+		return nil, newPSCommandError("Get-DnsServerForwarder", result)
+	}
+
+	fwd, err := unmarshallForwarders(ctx, []byte(result.Stdout))
+	if err != nil {
+		return nil, fmt.Errorf("GetForwarders: %s", err)
+	}
+	return fwd, nil
+}
+
+// Update changes the server level forwarders of the DNS server. The keys of changes are the Set-DnsServerForwarder
+// parameters to change: IPAddress, which replaces the forwarders in the order given, and UseRootHint.
+func (f *Forwarders) Update(ctx context.Context, conf *config.ProviderConf, changes map[string]any) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	params := make(map[string]any, len(changes))
+	for k, v := range changes {
+		params[k] = v
+	}
+
+	psOpts := CreatePSCommandOpts{
+		Username: conf.Settings.SshUsername,
+		Password: conf.Settings.SshPassword,
+		Server:   conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSScript("Set-DnsServerForwarder", setForwardersScript, params, psOpts)
+	if err != nil {
+		return err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("ssh execution failure while updating forwarders: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return newPSCommandError("Set-DnsServerForwarder", result)
+	}
+	return nil
+}
+
+func unmarshallForwarders(ctx context.Context, input []byte) (*Forwarders, error) {
+	var fwd forwarders
+
+	doc, err := jsonDocument(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(doc, &fwd)
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Failed to unmarshall a forwarders json document with error %q, document was %s", err, string(input)))
+		return nil, fmt.Errorf("failed while unmarshalling forwarders json document: %s", err)
+	}
+
+	return &Forwarders{
+		IPAddresses: ipAddressStrings(fwd.IPAddress),
+		UseRootHint: fwd.UseRootHint,
+	}, nil
+}
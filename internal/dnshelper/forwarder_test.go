@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+func TestGetForwarders(t *testing.T) {
+	conf, executor := newFakeConf(&config.CommandOutput{Stdout: `{
+  "IPAddress": [
+    { "AddressFamily": 2, "IPAddressToString": "198.51.100.53" },
+    { "AddressFamily": 23, "IPAddressToString": "2001:db8::53" },
+    { "AddressFamily": 2, "IPAddressToString": "192.0.2.53" }
+  ],
+  "UseRootHint": true,
+  "Timeout": 3,
+  "EnableReordering": true
+}`})
+
+	got, err := GetForwarders(context.Background(), conf)
+	if err != nil {
+		t.Fatalf("GetForwarders() error = %s", err)
+	}
+	want := &Forwarders{
+		IPAddresses: []string{"198.51.100.53", "2001:db8::53", "192.0.2.53"},
+		UseRootHint: true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetForwarders() = %+v, want %+v", got, want)
+	}
+	if params := executor.params(t, 0); params["ComputerName"] != "dc01.example.com" {
+		t.Errorf("params = %v, want ComputerName dc01.example.com", params)
+	}
+}
+
+func TestGetForwarders_None(t *testing.T) {
+	conf, _ := newFakeConf(&config.CommandOutput{Stdout: `{ "IPAddress": null, "UseRootHint": true }`})
+
+	got, err := GetForwarders(context.Background(), conf)
+	if err != nil {
+		t.Fatalf("GetForwarders() error = %s", err)
+	}
+	want := &Forwarders{IPAddresses: []string{}, UseRootHint: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetForwarders() = %+v, want %+v", got, want)
+	}
+}
+
+func TestForwardersUpdate(t *testing.T) {
+	conf, executor := newFakeConf()
+	fwd := Forwarders{}
+
+	changes := map[string]any{"IPAddress": []string{"198.51.100.53", "192.0.2.53"}, "UseRootHint": false}
+	if err := fwd.Update(context.Background(), conf, changes); err != nil {
+		t.Fatalf("Update() error = %s", err)
+	}
+	want := map[string]any{
+		"IPAddress":    []any{"198.51.100.53", "192.0.2.53"},
+		"UseRootHint":  false,
+		"ComputerName": "dc01.example.com",
+	}
+	if params := executor.params(t, 0); !reflect.DeepEqual(params, want) {
+		t.Errorf("params = %v, want %v", params, want)
+	}
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// Internationalized domain names are stored on the server in their ASCII form, with every label holding other
+// characters replaced by its A-label, e.g. xn--bcher-kva for bücher.
+
+// ToASCIIName returns name with its internationalized labels replaced by their A-labels, e.g. xn--bcher-kva.example.com
+// for bücher.example.com. ASCII labels are returned as they are, so labels that are not host names, e.g. _sip, and the
+// casing of ASCII labels are kept. Labels that are not valid internationalized labels are rejected.
+func ToASCIIName(name string) (string, error) {
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+		aLabel, err := idna.Lookup.ToASCII(label)
+		if err != nil {
+			return "", fmt.Errorf("invalid internationalized domain name %q: %s", name, err)
+		}
+		labels[i] = aLabel
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// ToUnicodeName returns name with its A-labels replaced by the internationalized labels they encode, reversing
+// ToASCIIName. Labels that are not valid A-labels are returned as they are.
+func ToUnicodeName(name string) string {
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if len(label) < 4 || !strings.EqualFold(label[:4], "xn--") {
+			continue
+		}
+		if uLabel, err := idna.Lookup.ToUnicode(label); err == nil {
+			labels[i] = uLabel
+		}
+	}
+	return strings.Join(labels, ".")
+}
+
+// SameDomainName reports whether a and b are the same domain name, ignoring casing and whether internationalized
+// labels are written as Unicode or as A-labels.
+func SameDomainName(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	asciiA, err := ToASCIIName(a)
+	if err != nil {
+		return false
+	}
+	asciiB, err := ToASCIIName(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(asciiA, asciiB)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
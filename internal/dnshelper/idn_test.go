@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import "testing"
+
+func TestToASCIIName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"test-ascii", "www.Example.com", "www.Example.com", false},
+		{"test-service", "_sip._tcp", "_sip._tcp", false},
+		{"test-wildcard", "*.bücher", "*.xn--bcher-kva", false},
+		{"test-label", "bücher", "xn--bcher-kva", false},
+		{"test-upper-case", "BÜCHER", "xn--bcher-kva", false},
+		{"test-zone", "bücher.example.com", "xn--bcher-kva.example.com", false},
+		{"test-several-labels", "www.müller.bücher.no", "www.xn--mller-kva.xn--bcher-kva.no", false},
+		{"test-a-label", "xn--bcher-kva", "xn--bcher-kva", false},
+		{"test-leading-combining-mark", "\u0301bcher", "", true},
+		{"test-mixed-direction", "a\u05d0", "", true},
+		{"test-trailing-hyphen", "bücher-", "", true},
+		{"test-underscore", "bü_cher", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToASCIIName(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToASCIIName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ToASCIIName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToUnicodeName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"test-ascii", "www.example.com", "www.example.com"},
+		{"test-a-label", "xn--bcher-kva", "bücher"},
+		{"test-upper-case", "XN--BCHER-KVA.example.com", "bücher.example.com"},
+		{"test-wildcard", "*.xn--bcher-kva", "*.bücher"},
+		{"test-invalid-a-label", "xn--zz.example.com", "xn--zz.example.com"},
+		{"test-apex", "@", "@"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToUnicodeName(tt.input); got != tt.want {
+				t.Errorf("ToUnicodeName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameDomainName(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{"test-same", "www", "www", true},
+		{"test-case", "WWW", "www", true},
+		{"test-a-label", "bücher.example.com", "xn--bcher-kva.example.com", true},
+		{"test-a-label-case", "Bücher", "XN--BCHER-KVA", true},
+		{"test-different", "bücher", "bucher", false},
+		{"test-invalid", "a\u05d0", "xn--a-0hc", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SameDomainName(tt.a, tt.b); got != tt.want {
+				t.Errorf("SameDomainName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeHostName_IDN(t *testing.T) {
+	got, err := SanitizeHostName("*.bücher")
+	if err != nil {
+		t.Fatalf("SanitizeHostName() error = %s", err)
+	}
+	if got != "*.xn--bcher-kva" {
+		t.Errorf("SanitizeHostName() = %q, want %q", got, "*.xn--bcher-kva")
+	}
+
+	got, err = SanitizeZoneName("bücher.example.com")
+	if err != nil {
+		t.Fatalf("SanitizeZoneName() error = %s", err)
+	}
+	if got != "xn--bcher-kva.example.com" {
+		t.Errorf("SanitizeZoneName() = %q, want %q", got, "xn--bcher-kva.example.com")
+	}
+
+	if _, err := SanitizeZoneName("a\u05d0.example.com"); err == nil {
+		t.Errorf("SanitizeZoneName() of an invalid internationalized name did not fail")
+	}
+}
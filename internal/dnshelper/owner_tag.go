@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// Windows DNS has no notes field on records, so records are tagged with their owner by a TXT record at a sibling
+// name, e.g. tf-owner-a.www for the A records of www, holding the text ownerTagText returns.
+const (
+	ownerTagLabelPrefix = "tf-owner-"
+	ownerTagTextPrefix  = "managed-by=terraform; owner="
+)
+
+// OwnerTagName returns the name of the TXT record tagging the records of hostName and recordType with their owner.
+func OwnerTagName(hostName, recordType string) string {
+	label := ownerTagLabelPrefix + strings.ToLower(recordType)
+	if IsApexName(hostName) {
+		return label
+	}
+	return label + "." + hostName
+}
+
+// ownerTagText returns the text of the TXT record tagging records with owner.
+func ownerTagText(owner string) string {
+	return ownerTagTextPrefix + owner
+}
+
+// parseOwnerTagText returns the owner in the text of a TXT record tagging records, or false if text is not a tag.
+func parseOwnerTagText(text string) (string, bool) {
+	owner, ok := strings.CutPrefix(text, ownerTagTextPrefix)
+	if !ok || owner == "" {
+		return "", false
+	}
+	return owner, true
+}
+
+// ownerTag returns the TXT record tagging the records of r with their owner.
+func (r *Record) ownerTag() *Record {
+	return &Record{
+		ZoneName:               r.ZoneName,
+		HostName:               OwnerTagName(r.HostName, r.RecordType),
+		RecordType:             RecordTypeTXT,
+		VirtualizationInstance: r.VirtualizationInstance,
+		ZoneScope:              r.ZoneScope,
+	}
+}
+
+// GetOwnerTag returns the owner the records of r are tagged with, or an empty string if they are not tagged.
+func (r *Record) GetOwnerTag(ctx context.Context, conf *config.ProviderConf) (string, error) {
+	tag, err := GetDNSRecordFromId(ctx, conf, r.ownerTag().Id())
+	if err != nil {
+		if IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	for _, text := range RecordValues(RecordTypeTXT, tag.Records, false) {
+		if owner, ok := parseOwnerTagText(text); ok {
+			return owner, nil
+		}
+	}
+	return "", nil
+}
+
+// SetOwnerTag tags the records of r with owner, replacing any other text at the name of the tag. An empty owner
+// removes the tag.
+func (r *Record) SetOwnerTag(ctx context.Context, conf *config.ProviderConf, owner string) error {
+	tag := r.ownerTag()
+	records := []interface{}{}
+	if owner != "" {
+		tag.Records = []string{ownerTagText(owner)}
+		records = append(records, tag.Records[0])
+	}
+	return tag.Update(ctx, conf, map[string]interface{}{"records": records})
+}
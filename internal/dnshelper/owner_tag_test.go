@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+func TestOwnerTagName(t *testing.T) {
+	tests := []struct {
+		name       string
+		hostName   string
+		recordType string
+		want       string
+	}{
+		{"test-name", "www", RecordTypeA, "tf-owner-a.www"},
+		{"test-subdomain", "www.sub", RecordTypeCNAME, "tf-owner-cname.www.sub"},
+		{"test-apex", ApexName, RecordTypeTXT, "tf-owner-txt"},
+		{"test-empty-apex", "", RecordTypeNS, "tf-owner-ns"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OwnerTagName(tt.hostName, tt.recordType); got != tt.want {
+				t.Errorf("OwnerTagName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOwnerTagText(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		want   string
+		wantOk bool
+	}{
+		{"test-tag", "managed-by=terraform; owner=team-dns", "team-dns", true},
+		{"test-owner-with-spaces", ownerTagText("Team DNS; ops"), "Team DNS; ops", true},
+		{"test-empty-owner", "managed-by=terraform; owner=", "", false},
+		{"test-other-text", "v=spf1 -all", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseOwnerTagText(tt.text)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("parseOwnerTagText() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestRecordSetOwnerTag_Executor(t *testing.T) {
+	conf, executor := newFakeConf(&config.CommandOutput{Stdout: `{
+  "HostName": "tf-owner-a.www",
+  "RecordType": "TXT",
+  "RecordData": { "CimInstanceProperties": [ { "Name": "DescriptiveText", "value": "managed-by=terraform; owner=team-web" } ] }
+}`})
+	r := &Record{ZoneName: "example.com", HostName: "www", RecordType: RecordTypeA}
+
+	if err := r.SetOwnerTag(context.Background(), conf, "team-dns"); err != nil {
+		t.Fatalf("SetOwnerTag() error = %s", err)
+	}
+
+	// The new tag is added before the old one is removed.
+	if len(executor.scripts) != 3 {
+		t.Fatalf("got %d commands, want 3", len(executor.scripts))
+	}
+	if params := executor.params(t, 0); params["Name"] != "tf-owner-a.www" || params["RRType"] != RecordTypeTXT {
+		t.Errorf("params of the query = %v, want TXT records of tf-owner-a.www", params)
+	}
+	if params := executor.params(t, 1); params["DescriptiveText"] != "managed-by=terraform; owner=team-dns" {
+		t.Errorf("params of the added tag = %v, want owner team-dns", params)
+	}
+	if params := executor.params(t, 2); params["RecordData"] != "managed-by=terraform; owner=team-web" {
+		t.Errorf("params of the removed tag = %v, want owner team-web", params)
+	}
+}
+
+func TestRecordGetOwnerTag_ExecutorNotFound(t *testing.T) {
+	conf, _ := newFakeConf(&config.CommandOutput{
+		ExitCode: 1,
+		Stderr:   "FullyQualifiedErrorId : WIN32 9714,Get-DnsServerResourceRecord, CategoryInfo : ObjectNotFound",
+	})
+	r := &Record{ZoneName: "example.com", HostName: "www", RecordType: RecordTypeA}
+
+	owner, err := r.GetOwnerTag(context.Background(), conf)
+	if err != nil || owner != "" {
+		t.Errorf("GetOwnerTag() = %q, %v, want no owner", owner, err)
+	}
+}
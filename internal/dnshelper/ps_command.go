@@ -3,16 +3,26 @@
 package dnshelper
 
 import (
-	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/nrkno/terraform-provider-windns/internal/config"
-	"golang.org/x/crypto/ssh"
 
 	"github.com/masterzen/winrm"
 )
 
+// maxLoggedOutput is the number of bytes of stdout and stderr included in log entries.
+const maxLoggedOutput = 4096
+
+// defaultPowerShellPath is the executable running the commands unless the provider configures another one.
+const defaultPowerShellPath = "powershell.exe"
+
 type CreatePSCommandOpts struct {
 	ForceArray bool
 	JSONOutput bool
@@ -24,80 +34,249 @@ type CreatePSCommandOpts struct {
 
 type PSCommand struct {
 	CreatePSCommandOpts
-	cmd string
+	cmdlet string
+	params map[string]any
+	cmd    string
+	// stdin holds the encoded parameters when they are too large to be part of cmd.
+	stdin string
 }
 
-func NewPSCommand(cmds []string, opts CreatePSCommandOpts) *PSCommand {
-	cmd := strings.Join(cmds, " ")
+// psParamsPrelude decodes the base64 encoded JSON document holding the cmdlet
+// parameters into the $params hashtable, ready to be splatted into the cmdlet.
+// The document is given by a PowerShell expression, either a string literal or
+// psParamsStdin.
+const psParamsPrelude = `$params = @{}; ` +
+	`(ConvertFrom-Json ([System.Text.Encoding]::UTF8.GetString([System.Convert]::FromBase64String(%s)))).PSObject.Properties | ` +
+	`ForEach-Object { $params[$_.Name] = $_.Value };`
 
-	if opts.Server != "" {
-		cmd = fmt.Sprintf("%s -ComputerName %s", cmd, opts.Server)
+// psParamsStdin reads the encoded parameters from stdin.
+const psParamsStdin = `[Console]::In.ReadToEnd().Trim()`
+
+// maxInlineParamsLength is the length of the largest encoded parameters passed as part of the command line. The
+// command line is run by cmd.exe, the default shell of the OpenSSH server on Windows, which limits it to 8191
+// characters, and the encoded command is almost three times as long as the script. Larger parameters, e.g. long TXT
+// records, are passed on stdin instead.
+const maxInlineParamsLength = 1024
+
+// psErrorReport writes the identifiers of the errors raised by a command to stderr. Unlike the messages and the error
+// view PowerShell writes, which are translated on localized Windows, the error ID, the error category and the type of
+// the exception are the same in every language, so errors are recognized from these lines.
+const psErrorReport = `foreach ($e in @($Error)) { if ($e -is [System.Management.Automation.ErrorRecord]) { ` +
+	`[Console]::Error.WriteLine('FullyQualifiedErrorId : {0}, CategoryInfo : {1}, Exception : {2}' -f ` +
+	`$e.FullyQualifiedErrorId, $e.CategoryInfo.Category, $e.Exception.GetType().FullName) } }`
+
+// psCommandBody returns the command running body after the prelude, reporting the errors it raised with psErrorReport.
+func psCommandBody(prelude, body string) string {
+	return fmt.Sprintf("%s try { %s } finally { %s }", prelude, body, psErrorReport)
+}
+
+// defaultJSONDepth is the depth the output of commands setting no JSONDepth is serialized to. ConvertTo-Json defaults
+// to a depth of 2, and writes deeper objects as their string form, e.g. the record data properties of records, which
+// then fail to parse.
+const defaultJSONDepth = 4
+
+// convertToJSON returns the pipeline stage serializing the output of a command to JSON, to an explicit depth so nested
+// data is never truncated, and compressed to a single line, as the output is only parsed and not read by people.
+func convertToJSON(depth int) string {
+	if depth == 0 {
+		depth = defaultJSONDepth
+	}
+	return fmt.Sprintf("| ConvertTo-Json -Depth %d -Compress", depth)
+}
+
+// NewPSCommand returns a PSCommand running cmdlet with the given parameters.
+// The parameters are passed to the remote host as a JSON document and splatted
+// into the cmdlet, so user supplied values are never interpreted by PowerShell.
+// Switch parameters are enabled by setting them to true.
+func NewPSCommand(cmdlet string, params map[string]any, opts CreatePSCommandOpts) (*PSCommand, error) {
+	args, prelude, stdin, err := encodePSParams(cmdlet, params, opts)
+	if err != nil {
+		return nil, err
 	}
 
+	cmd := fmt.Sprintf("%s @params", cmdlet)
+
 	if opts.JSONOutput {
-		cmd = fmt.Sprintf("%s %s", cmd, "| ConvertTo-Json")
-		if opts.JSONDepth != 0 {
-			cmd = fmt.Sprintf("%s %s", cmd, fmt.Sprintf("-Depth %d", opts.JSONDepth))
-		}
+		cmd = fmt.Sprintf("%s %s", cmd, convertToJSON(opts.JSONDepth))
 	}
 
 	res := PSCommand{
 		CreatePSCommandOpts: opts,
-		cmd:                 cmd,
+		cmdlet:              cmdlet,
+		params:              args,
+		cmd:                 psCommandBody(prelude, cmd),
+		stdin:               stdin,
 	}
 
-	return &res
+	return &res, nil
 }
 
-// Run will run a powershell command and return the stdout and stderr
-// The output is converted to JSON if the json parameter is set to true.
-func (p *PSCommand) Run(conf *config.ProviderConf) (*PSCommandResult, error) {
-	var (
-		err      error
-		exitCode int
-		stderr   bytes.Buffer
-		stdout   bytes.Buffer
-	)
-	conn, err := conf.AcquireSshClient()
+// NewPSScript returns a PSCommand running script, for operations that cannot be expressed as a single cmdlet call.
+// As with NewPSCommand the parameters are passed in the $params hashtable, which script must read them from.
+// The name is used in place of the cmdlet when logging the command.
+func NewPSScript(name, script string, params map[string]any, opts CreatePSCommandOpts) (*PSCommand, error) {
+	args, prelude, stdin, err := encodePSParams(name, params, opts)
 	if err != nil {
-		return nil, fmt.Errorf("while acquiring ssh client: %s", err)
+		return nil, err
 	}
-	defer conf.ReleaseSshClient(conn)
 
-	encodedCmd := winrm.Powershell(p.cmd)
+	return &PSCommand{
+		CreatePSCommandOpts: opts,
+		cmdlet:              name,
+		params:              args,
+		cmd:                 psCommandBody(prelude, script),
+		stdin:               stdin,
+	}, nil
+}
+
+// encodePSParams returns the parameters of a command, including ComputerName when a DNS server is given,
+// the prelude decoding them into $params on the remote host, and the input of the command, which holds the
+// encoded parameters when they are longer than maxInlineParamsLength.
+func encodePSParams(name string, params map[string]any, opts CreatePSCommandOpts) (map[string]any, string, string, error) {
+	args := make(map[string]any, len(params)+1)
+	for k, v := range params {
+		args[k] = v
+	}
+	if opts.Server != "" {
+		args["ComputerName"] = opts.Server
+	}
 
-	cmd, err := conn.Command(encodedCmd)
+	encodedArgs, err := json.Marshal(args)
 	if err != nil {
-		return nil, err
+		return nil, "", "", fmt.Errorf("while encoding parameters for %s: %s", name, err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(encodedArgs)
+	if len(encoded) > maxInlineParamsLength {
+		return args, fmt.Sprintf(psParamsPrelude, psParamsStdin), encoded, nil
 	}
+	return args, fmt.Sprintf(psParamsPrelude, quotePSString(encoded)), "", nil
+}
 
-	cmd.Session.Stderr = &stderr
-	cmd.Session.Stdout = &stdout
+// Run runs the command. Commands failing because the DNS server is temporarily busy are run again up to
+// BusyRetries times, BusyRetryDelay apart. Commands are added to the CommandLog of ctx once, however often they run.
+func (p *PSCommand) Run(ctx context.Context, conf *config.ProviderConf) (*PSCommandResult, error) {
+	logCommand(ctx, conf, p)
+	for attempt := 0; ; attempt++ {
+		result, err := p.run(ctx, conf)
+		if err != nil || result.ExitCode == 0 || !isTransientPSError(result.StdErr) || attempt >= conf.Settings.BusyRetries {
+			return result, err
+		}
 
-	err = cmd.Run()
-	if err != nil {
-		if v, ok := err.(*ssh.ExitError); ok {
-			exitCode = v.ExitStatus()
-		} else {
-			return nil, fmt.Errorf("run error: %s", err)
+		tflog.Debug(ctx, "DNS server is busy, retrying powershell command", map[string]any{
+			"command": p.String(),
+			"attempt": attempt + 1,
+			"stderr":  truncate(result.StdErr, maxLoggedOutput),
+		})
+		select {
+		case <-ctx.Done():
+			return result, nil
+		case <-time.After(conf.Settings.BusyRetryDelay):
 		}
 	}
+}
+
+func (p *PSCommand) run(ctx context.Context, conf *config.ProviderConf) (*PSCommandResult, error) {
+	if conf.Settings.SshPassword != "" {
+		ctx = tflog.MaskAllFieldValuesStrings(ctx, conf.Settings.SshPassword)
+		ctx = tflog.MaskMessageStrings(ctx, conf.Settings.SshPassword)
+	}
+	ctx = tflog.SetField(ctx, "command", p.String())
+	ctx = tflog.SetField(ctx, "ssh_hostname", conf.Settings.SshHostname)
+	ctx = tflog.SetField(ctx, "dns_server", p.Server)
+
+	err := conf.AcquireOperation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conf.ReleaseOperation()
 
-	out := stdout.String()
-	if p.ForceArray && stdout.String() != "" && stdout.String()[0] != '[' {
-		out = fmt.Sprintf("[%s]", stdout.String())
+	tflog.Debug(ctx, "Running powershell command")
+	output, err := conf.Executor.Execute(ctx, encodedPSCommand(conf.Settings.PowerShellPath, p.cmd), p.stdin)
+	if err != nil {
+		tflog.Debug(ctx, "Failed to run powershell command", map[string]any{"error": err.Error()})
+		return nil, err
+	}
+
+	errOut := decodeStderr(output.Stderr)
+	tflog.Debug(ctx, "Powershell command finished", map[string]any{
+		"exit_code": output.ExitCode,
+		"stdout":    truncate(output.Stdout, maxLoggedOutput),
+		"stderr":    truncate(errOut, maxLoggedOutput),
+	})
+
+	out := output.Stdout
+	if p.ForceArray && out != "" && out[0] != '[' {
+		out = fmt.Sprintf("[%s]", out)
 	}
 
 	result := &PSCommandResult{
 		Stdout:   out,
-		StdErr:   stderr.String(),
-		ExitCode: exitCode,
+		StdErr:   errOut,
+		ExitCode: output.ExitCode,
 	}
 	return result, nil
 }
 
+// encodedPSCommand returns the command line running script with the PowerShell executable at path.
+// The script is passed encoded, so it needs no quoting.
+func encodedPSCommand(path, script string) string {
+	if path == "" {
+		path = defaultPowerShellPath
+	}
+	if strings.ContainsAny(path, " \t") {
+		path = `"` + path + `"`
+	}
+	return path + strings.TrimPrefix(winrm.Powershell(script), defaultPowerShellPath)
+}
+
+// truncate shortens s to at most n bytes, noting how much was left out.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return fmt.Sprintf("%s... (%d bytes truncated)", s[:n], len(s)-n)
+}
+
+// String returns a human readable representation of the command, with the
+// parameters rendered inline rather than in their encoded form.
 func (p *PSCommand) String() string {
-	return p.cmd
+	keys := make([]string, 0, len(p.params))
+	for k := range p.params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := []string{p.cmdlet}
+	for _, k := range keys {
+		switch v := p.params[k].(type) {
+		case bool:
+			if v {
+				parts = append(parts, fmt.Sprintf("-%s", k))
+			} else {
+				parts = append(parts, fmt.Sprintf("-%s:$false", k))
+			}
+		case []string:
+			quoted := make([]string, 0, len(v))
+			for _, item := range v {
+				quoted = append(quoted, quotePSString(item))
+			}
+			parts = append(parts, fmt.Sprintf("-%s %s", k, strings.Join(quoted, ",")))
+		case string:
+			parts = append(parts, fmt.Sprintf("-%s %s", k, quotePSString(v)))
+		default:
+			parts = append(parts, fmt.Sprintf("-%s %v", k, v))
+		}
+	}
+
+	if p.JSONOutput {
+		parts = append(parts, convertToJSON(p.JSONDepth))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quotePSString quotes s as a single quoted PowerShell string literal.
+func quotePSString(s string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "''"))
 }
 
 // PSCommandResult holds the stdout, stderr and exit code of a powershell command
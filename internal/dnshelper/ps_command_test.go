@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var encodedParamsPattern = regexp.MustCompile(`FromBase64String\('([A-Za-z0-9+/=]*)'\)`)
+
+func TestNewPSCommand_ParamsAreNotInterpolated(t *testing.T) {
+	txt := "TxTdATa9 &!#$%&'()*+,-./:;<=>?@[]^_{|}~\"`"
+	params := map[string]any{
+		"ZoneName":        "example.com",
+		"Name":            "host",
+		"Txt":             true,
+		"DescriptiveText": txt,
+	}
+
+	psCmd, err := NewPSCommand("Add-DnsServerResourceRecord", params, CreatePSCommandOpts{Server: "dns01"})
+	if err != nil {
+		t.Fatalf("NewPSCommand() error = %s", err)
+	}
+
+	if strings.Contains(psCmd.cmd, txt) {
+		t.Errorf("command contains raw parameter value: %s", psCmd.cmd)
+	}
+
+	m := encodedParamsPattern.FindStringSubmatch(psCmd.cmd)
+	if m == nil {
+		t.Fatalf("no encoded parameters found in command: %s", psCmd.cmd)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(m[1])
+	if err != nil {
+		t.Fatalf("failed decoding parameters: %s", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(decoded, &got); err != nil {
+		t.Fatalf("failed unmarshalling parameters: %s", err)
+	}
+	if got["DescriptiveText"] != txt {
+		t.Errorf("DescriptiveText = %q, want %q", got["DescriptiveText"], txt)
+	}
+	if got["ComputerName"] != "dns01" {
+		t.Errorf("ComputerName = %q, want %q", got["ComputerName"], "dns01")
+	}
+	if got["Txt"] != true {
+		t.Errorf("Txt = %v, want true", got["Txt"])
+	}
+}
+
+func TestNewPSScript(t *testing.T) {
+	params := map[string]any{
+		"ZoneName":      "example.com",
+		"PrimaryServer": "dc01.example.com'; Remove-Item C:\\",
+	}
+
+	psCmd, err := NewPSScript("Set-DnsServerResourceRecord", setSOAScript, params, CreatePSCommandOpts{Server: "dns01"})
+	if err != nil {
+		t.Fatalf("NewPSScript() error = %s", err)
+	}
+
+	if strings.Contains(psCmd.cmd, "Remove-Item") {
+		t.Errorf("command contains raw parameter value: %s", psCmd.cmd)
+	}
+	if !strings.Contains(psCmd.cmd, setSOAScript) {
+		t.Errorf("command does not contain the script: %s", psCmd.cmd)
+	}
+	if !strings.HasSuffix(psCmd.cmd, "finally { "+psErrorReport+" }") {
+		t.Errorf("command does not report its errors: %s", psCmd.cmd)
+	}
+	if !encodedParamsPattern.MatchString(psCmd.cmd) {
+		t.Errorf("no encoded parameters found in command: %s", psCmd.cmd)
+	}
+	if psCmd.params["ComputerName"] != "dns01" {
+		t.Errorf("ComputerName = %q, want %q", psCmd.params["ComputerName"], "dns01")
+	}
+}
+
+func TestNewPSCommand_JSONOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		opts CreatePSCommandOpts
+		want string
+	}{
+		{"test-depth", CreatePSCommandOpts{JSONOutput: true, JSONDepth: 2}, "Get-DnsServerZone @params | ConvertTo-Json -Depth 2 -Compress"},
+		{"test-default-depth", CreatePSCommandOpts{JSONOutput: true}, "Get-DnsServerZone @params | ConvertTo-Json -Depth 4 -Compress"},
+		{"test-no-json", CreatePSCommandOpts{}, "Get-DnsServerZone @params }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			psCmd, err := NewPSCommand("Get-DnsServerZone", nil, tt.opts)
+			if err != nil {
+				t.Fatalf("NewPSCommand() error = %s", err)
+			}
+			if !strings.Contains(psCmd.cmd, tt.want) {
+				t.Errorf("command does not contain %q: %s", tt.want, psCmd.cmd)
+			}
+		})
+	}
+}
+
+func TestEncodedPSCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantPrefix string
+	}{
+		{"test-default", "", "powershell.exe -EncodedCommand "},
+		{"test-pwsh", "pwsh.exe", "pwsh.exe -EncodedCommand "},
+		{"test-path-with-spaces", `C:\Program Files\PowerShell\7\pwsh.exe`, `"C:\Program Files\PowerShell\7\pwsh.exe" -EncodedCommand `},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := encodedPSCommand(tt.path, "Get-DnsServerZone")
+			if !strings.HasPrefix(got, tt.wantPrefix) {
+				t.Errorf("encodedPSCommand() = %q, want prefix %q", got, tt.wantPrefix)
+			}
+			if strings.Contains(got, "Get-DnsServerZone") {
+				t.Errorf("encodedPSCommand() = %q, contains the script unencoded", got)
+			}
+		})
+	}
+}
+
+func TestPSCommand_String(t *testing.T) {
+	params := map[string]any{
+		"ZoneName":    "example.com",
+		"Name":        "o'brien",
+		"A":           true,
+		"IPv4Address": "203.0.113.11",
+	}
+	psCmd, err := NewPSCommand("Add-DnsServerResourceRecord", params, CreatePSCommandOpts{})
+	if err != nil {
+		t.Fatalf("NewPSCommand() error = %s", err)
+	}
+
+	want := "Add-DnsServerResourceRecord -A -IPv4Address '203.0.113.11' -Name 'o''brien' -ZoneName 'example.com'"
+	if got := psCmd.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// clixmlHeader prefixes the stderr of powershell.exe when it runs an encoded
+// command with its output redirected, as it is over SSH.
+const clixmlHeader = "#< CLIXML"
+
+// clixmlEscape matches the _xHHHH_ escapes CLIXML uses for control characters.
+var clixmlEscape = regexp.MustCompile(`_x([0-9A-Fa-f]{4})_`)
+
+// knownPSErrors maps the identifiers of common DnsServer module errors to a short explanation. The messages of the
+// errors are translated on localized Windows, so only error IDs, error categories and exception types, as written by
+// psErrorReport, are matched.
+var knownPSErrors = []struct {
+	fragments []string
+	hint      string
+}{
+	{[]string{"WIN32 9711"}, "the record already exists on the DNS server"},
+	{[]string{"WIN32 9601"}, "the zone does not exist on the DNS server"},
+	{[]string{"PermissionDenied", "WIN32 5,", "UnauthorizedAccessException"}, "access denied, check the permissions of the SSH user on the DNS server"},
+	{[]string{"WIN32 1722"}, "the DNS server could not be reached from the SSH host"},
+	{[]string{"CommandNotFoundException"}, "the DnsServer PowerShell module is not available on the SSH host"},
+	{[]string{"WIN32 9608"}, "the zone is locked, e.g. by an administrator editing it in the DNS console"},
+}
+
+// transientPSError matches errors caused by the DNS server being temporarily busy, e.g. while an administrator
+// edits the zone in the DNS console, after which the command may succeed when run again: the zone being locked
+// (9608), the RPC server being too busy (1723) and the requested resource being in use (170). These errors are
+// returned before the server changes anything, so retrying commands that add records is safe.
+var transientPSError = regexp.MustCompile(`WIN32 (170|1723|9608)\b`)
+
+// isTransientPSError reports whether the stderr of a command tells that it failed on a temporary condition.
+func isTransientPSError(stderr string) bool {
+	return transientPSError.MatchString(stderr)
+}
+
+// notFoundPSError matches the error category of missing objects and the error ID of missing records (9714).
+var notFoundPSError = regexp.MustCompile(`\bObjectNotFound\b|WIN32 9714\b`)
+
+// decodeStderr returns the error messages from the stderr of a powershell
+// command, decoding them from CLIXML if needed. Lines written directly to
+// stderr, e.g. by psErrorReport, are kept in front of the decoded messages.
+func decodeStderr(stderr string) string {
+	trimmed := strings.TrimSpace(stderr)
+	i := strings.Index(trimmed, clixmlHeader)
+	if i == -1 {
+		return trimmed
+	}
+	before, xmlDoc := trimmed[:i], strings.TrimPrefix(trimmed[i:], clixmlHeader)
+	end := strings.LastIndex(xmlDoc, "</Objs>")
+	if end == -1 {
+		return trimmed
+	}
+	after := xmlDoc[end+len("</Objs>"):]
+	xmlDoc = xmlDoc[:end+len("</Objs>")]
+
+	var doc struct {
+		Streams []struct {
+			Stream string `xml:"S,attr"`
+			Text   string `xml:",chardata"`
+		} `xml:"S"`
+	}
+	err := xml.Unmarshal([]byte(strings.TrimSpace(xmlDoc)), &doc)
+	if err != nil {
+		return trimmed
+	}
+
+	var b strings.Builder
+	for _, s := range []string{before, after} {
+		if s = strings.TrimSpace(s); s != "" {
+			b.WriteString(s)
+			b.WriteString("\r\n")
+		}
+	}
+	for _, s := range doc.Streams {
+		if s.Stream != "Error" {
+			continue
+		}
+		b.WriteString(clixmlEscape.ReplaceAllStringFunc(s.Text, func(m string) string {
+			r, err := strconv.ParseUint(m[2:6], 16, 32)
+			if err != nil {
+				return m
+			}
+			return string(rune(r))
+		}))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// newPSCommandError returns the error for a command that exited with a non zero exit code.
+// The error always contains the full error output of the command, preceded by an
+// explanation if the error is a known one.
+func newPSCommandError(cmdlet string, result *PSCommandResult) error {
+	for _, known := range knownPSErrors {
+		for _, fragment := range known.fragments {
+			if strings.Contains(result.StdErr, fragment) {
+				return fmt.Errorf("%s exited with a non zero exit code (%d), %s. stderr: %s", cmdlet, result.ExitCode, known.hint, result.StdErr)
+			}
+		}
+	}
+	return fmt.Errorf("%s exited with a non zero exit code (%d), stderr: %s", cmdlet, result.ExitCode, result.StdErr)
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeStderr(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+		want   string
+	}{
+		{"test-plain", "  some error\r\n", "some error"},
+		{"test-empty", "", ""},
+		{
+			"test-clixml",
+			`#< CLIXML
+<Objs Version="1.1.0.1" xmlns="http://schemas.microsoft.com/powershell/2004/04"><S S="Error">Add-DnsServerResourceRecord : Failed to create resource record www in example.com zone._x000D__x000A_</S><S S="Error">    + FullyQualifiedErrorId : WIN32 9711_x000D__x000A_</S><S S="progress">ignored</S></Objs>`,
+			"Add-DnsServerResourceRecord : Failed to create resource record www in example.com zone.\r\n    + FullyQualifiedErrorId : WIN32 9711",
+		},
+		{
+			"test-clixml-with-error-report",
+			"FullyQualifiedErrorId : WIN32 9714,Get-DnsServerResourceRecord, CategoryInfo : ObjectNotFound, Exception : Microsoft.Management.Infrastructure.CimException\r\n" +
+				`#< CLIXML
+<Objs Version="1.1.0.1" xmlns="http://schemas.microsoft.com/powershell/2004/04"><S S="Error">Get-DnsServerResourceRecord : Fehler_x000D__x000A_</S></Objs>`,
+			"FullyQualifiedErrorId : WIN32 9714,Get-DnsServerResourceRecord, CategoryInfo : ObjectNotFound, Exception : Microsoft.Management.Infrastructure.CimException\r\nGet-DnsServerResourceRecord : Fehler",
+		},
+		{"test-invalid-clixml", "#< CLIXML\n<Objs", "#< CLIXML\n<Objs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeStderr(tt.stderr); got != tt.want {
+				t.Errorf("decodeStderr() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPSCommandError(t *testing.T) {
+	tests := []struct {
+		name     string
+		stderr   string
+		wantHint string
+	}{
+		{"test-already-exists", "Failed to create resource record www. + FullyQualifiedErrorId : WIN32 9711", "the record already exists"},
+		{"test-zone-missing", "FullyQualifiedErrorId : WIN32 9601,Get-DnsServerZone, CategoryInfo : ObjectNotFound, Exception : Microsoft.Management.Infrastructure.CimException", "the zone does not exist"},
+		{"test-access-denied", "FullyQualifiedErrorId : WIN32 5,Get-DnsServerZone, CategoryInfo : PermissionDenied, Exception : Microsoft.Management.Infrastructure.CimException", "access denied"},
+		{"test-module-missing", "FullyQualifiedErrorId : CommandNotFoundException, CategoryInfo : ObjectNotFound, Exception : System.Management.Automation.CommandNotFoundException", "module is not available"},
+		{
+			"test-localized",
+			"Die Zone \"example.com\" wurde auf dem Server \"DC01\" nicht gefunden.\r\n" +
+				"FullyQualifiedErrorId : WIN32 9601,Get-DnsServerZone, CategoryInfo : ObjectNotFound, Exception : Microsoft.Management.Infrastructure.CimException",
+			"the zone does not exist",
+		},
+		{"test-unknown", "something else went wrong", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newPSCommandError("Add-DnsServerResourceRecord", &PSCommandResult{StdErr: tt.stderr, ExitCode: 1})
+			if !strings.Contains(err.Error(), tt.stderr) {
+				t.Errorf("newPSCommandError() = %q, does not contain stderr %q", err, tt.stderr)
+			}
+			if tt.wantHint != "" && !strings.Contains(err.Error(), tt.wantHint) {
+				t.Errorf("newPSCommandError() = %q, does not contain hint %q", err, tt.wantHint)
+			}
+		})
+	}
+}
+
+func TestIsTransientPSError(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+		want   bool
+	}{
+		{"test-zone-locked", "Failed to update zone example.com. + FullyQualifiedErrorId : WIN32 9608,Add-DnsServerResourceRecord", true},
+		{"test-rpc-busy", "The RPC server is too busy to complete this operation. + FullyQualifiedErrorId : WIN32 1723,Add-DnsServerResourceRecord", true},
+		{"test-in-use", "+ FullyQualifiedErrorId : WIN32 170,Remove-DnsServerResourceRecord", true},
+		{"test-already-exists", "+ FullyQualifiedErrorId : WIN32 9711,Add-DnsServerResourceRecord", false},
+		{"test-other-code", "+ FullyQualifiedErrorId : WIN32 1700,Add-DnsServerResourceRecord", false},
+		{"test-access-denied", "Access is denied.", false},
+		{"test-localized-zone-locked", "Die Zone ist gesperrt.\r\nFullyQualifiedErrorId : WIN32 9608,Add-DnsServerResourceRecord, CategoryInfo : ResourceBusy, Exception : Microsoft.Management.Infrastructure.CimException", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientPSError(tt.stderr); got != tt.want {
+				t.Errorf("isTransientPSError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
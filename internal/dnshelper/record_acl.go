@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// RecordAccessRule grants a principal rights on the node of a record in an AD-integrated zone, e.g. so a delegated
+// team may update the records of a name.
+type RecordAccessRule struct {
+	// Principal is the account or group granted the rights, e.g. EXAMPLE\dns-admins, or its SID.
+	Principal string `json:"Principal"`
+	// Rights is the ActiveDirectoryRights mask of the rights granted, see ActiveDirectoryRightsMask.
+	Rights int64 `json:"Rights"`
+}
+
+// activeDirectoryRights maps the names of the System.DirectoryServices.ActiveDirectoryRights values to their masks.
+// The generic rights combine several of the others.
+var activeDirectoryRights = map[string]int64{
+	"CreateChild":          0x1,
+	"DeleteChild":          0x2,
+	"ListChildren":         0x4,
+	"Self":                 0x8,
+	"ReadProperty":         0x10,
+	"WriteProperty":        0x20,
+	"DeleteTree":           0x40,
+	"ListObject":           0x80,
+	"ExtendedRight":        0x100,
+	"Delete":               0x10000,
+	"ReadControl":          0x20000,
+	"GenericExecute":       0x20004,
+	"GenericWrite":         0x20028,
+	"GenericRead":          0x20094,
+	"WriteDacl":            0x40000,
+	"WriteOwner":           0x80000,
+	"GenericAll":           0xF01FF,
+	"Synchronize":          0x100000,
+	"AccessSystemSecurity": 0x1000000,
+}
+
+// ActiveDirectoryRightNames returns the names of the rights an access rule may grant, sorted.
+func ActiveDirectoryRightNames() []string {
+	names := make([]string, 0, len(activeDirectoryRights))
+	for name := range activeDirectoryRights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ActiveDirectoryRightsMask returns the mask of the rights named by names.
+func ActiveDirectoryRightsMask(names []string) (int64, error) {
+	var mask int64
+	for _, name := range names {
+		right, ok := activeDirectoryRights[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown right %q, expected one of %s", name, strings.Join(ActiveDirectoryRightNames(), ", "))
+		}
+		mask |= right
+	}
+	return mask, nil
+}
+
+// ActiveDirectoryRightsNames returns the names of the rights in mask, sorted, preferring the generic rights to the
+// rights they combine.
+func ActiveDirectoryRightsNames(mask int64) []string {
+	names := ActiveDirectoryRightNames()
+	sort.SliceStable(names, func(i, j int) bool {
+		return bits.OnesCount64(uint64(activeDirectoryRights[names[i]])) > bits.OnesCount64(uint64(activeDirectoryRights[names[j]]))
+	})
+
+	var covered int64
+	var result []string
+	for _, name := range names {
+		right := activeDirectoryRights[name]
+		if mask&right == right && right&^covered != 0 {
+			result = append(result, name)
+			covered |= right
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// recordNodeScript binds $entry to the node with the distinguished name in $params, on the DNS server when given. It
+// defines sid, which resolves an account name or SID string to a SecurityIdentifier, and managedRules, which returns the
+// explicit allow rules of a principal that apply to the whole node. Only the DACL of the node is read and written, so
+// neither the owner nor the SACL needs to be accessible.
+const recordNodeScript = `$ErrorActionPreference = 'Stop'; ` +
+	`$path = $params.DistinguishedName.Replace('/', '\/'); ` +
+	`if ($params.ContainsKey('ComputerName')) { $path = $params.ComputerName + '/' + $path }; ` +
+	`$entry = [ADSI]('LDAP://' + $path); ` +
+	`$entry.psbase.Options.SecurityMasks = [System.DirectoryServices.SecurityMasks]::Dacl; ` +
+	`function sid($principal) { if ($principal -match '^S-1-') { return New-Object System.Security.Principal.SecurityIdentifier($principal) }; ` +
+	`(New-Object System.Security.Principal.NTAccount($principal)).Translate([System.Security.Principal.SecurityIdentifier]) }; ` +
+	`function managedRules($sd, $sid) { @($sd.GetAccessRules($true, $false, [System.Security.Principal.SecurityIdentifier]) | ` +
+	`Where-Object { $_.AccessControlType -eq 'Allow' -and $_.ObjectType -eq [Guid]::Empty -and $_.IdentityReference -eq $sid }) }; `
+
+// getRecordAccessRulesScript writes the rights the explicit access rules of the node grant each of the principals in
+// $params. Rules limited to a property or child object type are left out.
+const getRecordAccessRulesScript = recordNodeScript +
+	`$sd = $entry.psbase.ObjectSecurity; ` +
+	`@(foreach ($principal in @($params.Principals)) { $mask = 0; ` +
+	`foreach ($rule in (managedRules $sd (sid $principal))) { $mask = $mask -bor [int]$rule.ActiveDirectoryRights }; ` +
+	`[pscustomobject]@{ Principal = $principal; Rights = $mask } }) | ConvertTo-Json -Depth 2 -Compress`
+
+// setRecordAccessRulesScript replaces the explicit access rules of the principals in $params with the rules in
+// $params, leaving the rules of other principals in place.
+const setRecordAccessRulesScript = recordNodeScript +
+	`$sd = $entry.psbase.ObjectSecurity; ` +
+	`foreach ($principal in @($params.Principals)) { foreach ($rule in (managedRules $sd (sid $principal))) { [void]$sd.RemoveAccessRuleSpecific($rule) } }; ` +
+	`foreach ($rule in @($params.Rules)) { $sd.AddAccessRule((New-Object System.DirectoryServices.ActiveDirectoryAccessRule(` +
+	`(sid $rule.Principal), [System.DirectoryServices.ActiveDirectoryRights][int]$rule.Rights, [System.Security.AccessControl.AccessControlType]::Allow))) }; ` +
+	`$entry.psbase.ObjectSecurity = $sd; ` +
+	`$entry.psbase.CommitChanges()`
+
+// GetRecordAccessRules returns the rights the node with the distinguished name dn grants each of principals, read from
+// the access rules set on the node itself. Inherited rules, deny rules and rules limited to a property or child object
+// type are left out. A principal granted no rights has a zero mask.
+func GetRecordAccessRules(ctx context.Context, conf *config.ProviderConf, dn string, principals []string) ([]RecordAccessRule, error) {
+	if len(principals) == 0 {
+		return nil, nil
+	}
+	params := map[string]any{
+		"DistinguishedName": dn,
+		"Principals":        principals,
+	}
+
+	psOpts := CreatePSCommandOpts{
+		ForceArray: true,
+		Username:   conf.Settings.SshUsername,
+		Password:   conf.Settings.SshPassword,
+		Server:     conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSScript("ADSI", getRecordAccessRulesScript, params, psOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return nil, fmt.Errorf("ssh execution failure in GetRecordAccessRules: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, newPSCommandError("ADSI", result)
+	}
+
+	return unmarshallRecordAccessRules(ctx, []byte(result.Stdout))
+}
+
+// SetRecordAccessRules grants the rights of rules on the node with the distinguished name dn, replacing the access rules
+// set on the node for their principals. The rules of the principals in removed are removed. The rules of other
+// principals, e.g. those granted by delegation tools or by the DNS server itself, are left in place.
+func SetRecordAccessRules(ctx context.Context, conf *config.ProviderConf, dn string, rules []RecordAccessRule, removed []string) error {
+	principals := append([]string{}, removed...)
+	for _, rule := range rules {
+		principals = append(principals, rule.Principal)
+	}
+	if len(principals) == 0 {
+		return nil
+	}
+	params := map[string]any{
+		"DistinguishedName": dn,
+		"Principals":        principals,
+		"Rules":             rules,
+	}
+
+	psOpts := CreatePSCommandOpts{
+		Username: conf.Settings.SshUsername,
+		Password: conf.Settings.SshPassword,
+		Server:   conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSScript("ADSI", setRecordAccessRulesScript, params, psOpts)
+	if err != nil {
+		return err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("ssh execution failure in SetRecordAccessRules: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return newPSCommandError("ADSI", result)
+	}
+	return nil
+}
+
+func unmarshallRecordAccessRules(ctx context.Context, input []byte) ([]RecordAccessRule, error) {
+	var rules []RecordAccessRule
+
+	doc, err := jsonDocument(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(doc, &rules)
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Failed to unmarshall an access rule json document with error %q, document was %s", err, string(input)))
+		return nil, fmt.Errorf("failed while unmarshalling access rule json document: %s", err)
+	}
+	return rules, nil
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+func TestActiveDirectoryRightsNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		{name: "single right", names: []string{"WriteProperty"}, want: []string{"WriteProperty"}},
+		{name: "generic right", names: []string{"GenericRead"}, want: []string{"GenericRead"}},
+		{name: "generic right and its parts", names: []string{"GenericRead", "ReadProperty", "ListChildren"}, want: []string{"GenericRead"}},
+		{name: "parts of a generic right", names: []string{"ReadControl", "ListChildren", "ReadProperty", "ListObject"}, want: []string{"GenericRead"}},
+		{name: "generic right and another", names: []string{"WriteProperty", "GenericRead"}, want: []string{"GenericRead", "WriteProperty"}},
+		{name: "full control", names: []string{"GenericAll", "Delete"}, want: []string{"GenericAll"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mask, err := ActiveDirectoryRightsMask(tt.names)
+			if err != nil {
+				t.Fatalf("ActiveDirectoryRightsMask() error = %s", err)
+			}
+			if got := ActiveDirectoryRightsNames(mask); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ActiveDirectoryRightsNames(%#x) = %v, want %v", mask, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ActiveDirectoryRightsMask([]string{"FullControl"}); err == nil {
+		t.Errorf("ActiveDirectoryRightsMask() of an unknown right did not return an error")
+	}
+}
+
+func TestGetRecordAccessRules(t *testing.T) {
+	conf, executor := newFakeConf(&config.CommandOutput{Stdout: `[
+  { "Principal": "EXAMPLE\\dns-admins", "Rights": 983551 },
+  { "Principal": "S-1-5-21-1004336348-1177238915-682003330-1106", "Rights": 0 }
+]`})
+
+	dn := "DC=www,DC=example.com,cn=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com"
+	principals := []string{`EXAMPLE\dns-admins`, "S-1-5-21-1004336348-1177238915-682003330-1106"}
+	got, err := GetRecordAccessRules(context.Background(), conf, dn, principals)
+	if err != nil {
+		t.Fatalf("GetRecordAccessRules() error = %s", err)
+	}
+	want := []RecordAccessRule{
+		{Principal: `EXAMPLE\dns-admins`, Rights: 0xF01FF},
+		{Principal: "S-1-5-21-1004336348-1177238915-682003330-1106"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetRecordAccessRules() = %+v, want %+v", got, want)
+	}
+
+	wantParams := map[string]any{
+		"DistinguishedName": dn,
+		"Principals":        []any{`EXAMPLE\dns-admins`, "S-1-5-21-1004336348-1177238915-682003330-1106"},
+		"ComputerName":      "dc01.example.com",
+	}
+	if params := executor.params(t, 0); !reflect.DeepEqual(params, wantParams) {
+		t.Errorf("params = %v, want %v", params, wantParams)
+	}
+}
+
+func TestSetRecordAccessRules(t *testing.T) {
+	conf, executor := newFakeConf()
+
+	dn := "DC=11,DC=0/26.113.0.203.in-addr.arpa,cn=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com"
+	rules := []RecordAccessRule{{Principal: `EXAMPLE\dns-admins`, Rights: 0x20094}}
+	if err := SetRecordAccessRules(context.Background(), conf, dn, rules, []string{`EXAMPLE\former-admins`}); err != nil {
+		t.Fatalf("SetRecordAccessRules() error = %s", err)
+	}
+
+	wantParams := map[string]any{
+		"DistinguishedName": dn,
+		"Principals":        []any{`EXAMPLE\former-admins`, `EXAMPLE\dns-admins`},
+		"Rules":             []any{map[string]any{"Principal": `EXAMPLE\dns-admins`, "Rights": float64(0x20094)}},
+		"ComputerName":      "dc01.example.com",
+	}
+	if params := executor.params(t, 0); !reflect.DeepEqual(params, wantParams) {
+		t.Errorf("params = %v, want %v", params, wantParams)
+	}
+	if !strings.Contains(executor.scripts[0], "CommitChanges()") {
+		t.Errorf("command does not commit the access rules: %s", executor.scripts[0])
+	}
+
+	if err := SetRecordAccessRules(context.Background(), conf, dn, nil, nil); err != nil || len(executor.scripts) != 1 {
+		t.Errorf("SetRecordAccessRules() without rules ran %d commands, error = %v, want no command", len(executor.scripts)-1, err)
+	}
+}
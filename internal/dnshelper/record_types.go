@@ -0,0 +1,297 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// recordField describes one field of a record's data. The name is both the
+// Add-DnsServerResourceRecord parameter used when creating the record and the
+// name of the record data property returned by Get-DnsServerResourceRecord.
+type recordField struct {
+	Name string
+	// DomainName marks fields holding a domain name, which the server always
+	// returns fully qualified with a trailing dot.
+	DomainName bool
+	// Optional marks a trailing field that may be left out.
+	Optional bool
+	// List marks a trailing field holding one or more values. Their order is insignificant.
+	List bool
+	// Names maps the numeric values of an enumerated field to the names the server uses for them.
+	Names map[string]string
+}
+
+// dnssecAlgorithms maps the DNSSEC algorithm numbers to the names used by the DnsServer module.
+var dnssecAlgorithms = map[string]string{
+	"5":  "RsaSha1",
+	"7":  "RsaSha1NSec3",
+	"8":  "RsaSha256",
+	"10": "RsaSha512",
+	"13": "ECDsaP256Sha256",
+	"14": "ECDsaP384Sha384",
+}
+
+// dsDigestTypes maps the DS digest type numbers to the names used by the DnsServer module.
+var dsDigestTypes = map[string]string{
+	"1": "Sha1",
+	"2": "Sha256",
+	"4": "Sha384",
+}
+
+// atmaAddressTypes maps the ATM address format numbers to the names used by the DnsServer module.
+var atmaAddressTypes = map[string]string{
+	"0": "NSAP",
+	"1": "E164",
+}
+
+// recordTypeFields lists the fields making up the record data of each supported
+// record type, in the order they are written in the records attribute.
+// Multi-field record data is written space separated, as in a zone file.
+var recordTypeFields = map[string][]recordField{
+	RecordTypeA:     {{Name: "IPv4Address"}},
+	RecordTypeAAAA:  {{Name: "IPv6Address"}},
+	RecordTypeTXT:   {{Name: "DescriptiveText"}},
+	RecordTypePTR:   {{Name: "PtrDomainName", DomainName: true}},
+	RecordTypeCNAME: {{Name: "HostNameAlias", DomainName: true}},
+	RecordTypeNS:    {{Name: "NameServer", DomainName: true}},
+	RecordTypeAFSDB: {{Name: "SubType"}, {Name: "ServerName", DomainName: true}},
+	RecordTypeRP:    {{Name: "ResponsiblePerson", DomainName: true}, {Name: "Description", DomainName: true}},
+	RecordTypeRT:    {{Name: "Preference"}, {Name: "IntermediateHost", DomainName: true}},
+	RecordTypeX25:   {{Name: "PsdnAddress"}},
+	RecordTypeISDN:  {{Name: "IsdnNumber"}, {Name: "IsdnSubAddress", Optional: true}},
+	RecordTypeWKS:   {{Name: "InternetAddress"}, {Name: "InternetProtocol"}, {Name: "Service", List: true}},
+	RecordTypeDS:    {{Name: "KeyTag"}, {Name: "CryptoAlgorithm", Names: dnssecAlgorithms}, {Name: "DigestType", Names: dsDigestTypes}, {Name: "Digest"}},
+	RecordTypeMB:    {{Name: "MBHost", DomainName: true}},
+	RecordTypeMG:    {{Name: "MGMailbox", DomainName: true}},
+	RecordTypeMR:    {{Name: "MRMailbox", DomainName: true}},
+	RecordTypeMINFO: {{Name: "ResponsibleMailbox", DomainName: true}, {Name: "ErrorMailbox", DomainName: true}},
+	RecordTypeATMA:  {{Name: "AddressType", Names: atmaAddressTypes}, {Name: "Address"}},
+	// CERT and DHCID records are added with their hex encoded RDATA, see certRecordData and dhcidRecordData.
+	RecordTypeCERT:  {{Name: "RecordData"}},
+	RecordTypeDHCID: {{Name: "RecordData"}},
+}
+
+// rrTypeNumbers maps the record types added, read and removed by their type number to the number. The DnsServer module
+// has no parameters for their record data, which is given as the hex encoded RDATA instead.
+var rrTypeNumbers = map[string]uint16{
+	RecordTypeCERT:  certRRType,
+	RecordTypeDHCID: dhcidRRType,
+}
+
+// dnscmdRecordTypes lists the record types Add-DnsServerResourceRecord has no parameters for. They are created with
+// dnscmd.exe instead, which takes the record data fields in order, so their field names are only used when reading.
+var dnscmdRecordTypes = map[string]bool{
+	RecordTypeMB:    true,
+	RecordTypeMG:    true,
+	RecordTypeMR:    true,
+	RecordTypeMINFO: true,
+}
+
+// name returns the name the server uses for the value v of an enumerated field, or v if it has none.
+func (f recordField) name(v string) string {
+	if name, ok := f.Names[v]; ok {
+		return name
+	}
+	return v
+}
+
+// IsMultiFieldRecordType reports whether the record data of recordType consists of several fields.
+func IsMultiFieldRecordType(recordType string) bool {
+	return len(recordTypeFields[recordType]) > 1
+}
+
+// IsCaseSensitiveRecordType reports whether the record data of recordType must be compared case-sensitively.
+// The text of TXT records is stored and returned by the server exactly as written, and applications may depend on
+// its case, and the certificates of CERT records and the data of DHCID records are base64 encoded, while other record
+// data consists of addresses and domain names, which are case-insensitive.
+func IsCaseSensitiveRecordType(recordType string) bool {
+	return strings.EqualFold(recordType, RecordTypeTXT) || recordType == RecordTypeCERT || recordType == RecordTypeDHCID
+}
+
+// splitRecordData splits the record data of a multi-field record type into its fields.
+func splitRecordData(recordType, recordData string) ([]string, error) {
+	fields, ok := recordTypeFields[recordType]
+	if !ok {
+		return nil, fmt.Errorf("record type %s is not supported", recordType)
+	}
+
+	if len(fields) == 1 {
+		return []string{recordData}, nil
+	}
+
+	required := 0
+	for _, f := range fields {
+		if !f.Optional {
+			required++
+		}
+	}
+	limit := len(fields)
+	if fields[len(fields)-1].List {
+		limit = -1
+	}
+
+	values := strings.Fields(recordData)
+	if len(values) < required || (limit != -1 && len(values) > limit) {
+		return nil, fmt.Errorf("%s record data %q must have the form %q", recordType, recordData, recordDataFormat(fields))
+	}
+	return values, nil
+}
+
+// recordDataValues splits the record data of a multi-field record type into its fields,
+// using the names the server uses for the values of enumerated fields.
+func recordDataValues(recordType, recordData string) ([]string, error) {
+	values, err := splitRecordData(recordType, recordData)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := recordTypeFields[recordType]
+	for i := range values {
+		if fields[i].List {
+			break
+		}
+		values[i] = fields[i].name(values[i])
+	}
+	return values, nil
+}
+
+// recordDataFormat describes the expected format of record data made up of fields.
+func recordDataFormat(fields []recordField) string {
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f.Optional {
+			names = append(names, fmt.Sprintf("[<%s>]", f.Name))
+			continue
+		}
+		if f.List {
+			names = append(names, fmt.Sprintf("<%s>...", f.Name))
+			continue
+		}
+		names = append(names, fmt.Sprintf("<%s>", f.Name))
+	}
+	return strings.Join(names, " ")
+}
+
+// recordDataParams returns the Add-DnsServerResourceRecord parameters holding recordData.
+func recordDataParams(recordType, recordData string) (map[string]any, error) {
+	values, err := splitRecordData(recordType, recordData)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := recordTypeFields[recordType]
+	params := make(map[string]any, len(values))
+	for i, v := range values {
+		if fields[i].List {
+			params[fields[i].Name] = values[i:]
+			break
+		}
+		params[fields[i].Name] = fields[i].name(v)
+	}
+	return params, nil
+}
+
+// formatRecordData renders the record data properties returned by the server in
+// the same form as the records attribute.
+func formatRecordData(recordType string, properties []CimInstanceProperties) string {
+	if len(properties) == 0 {
+		return ""
+	}
+
+	if recordType == RecordTypeCERT {
+		return formatCERTRecordData(formatCimValue(properties[0].Value))
+	}
+	if recordType == RecordTypeDHCID {
+		return formatDHCIDRecordData(properties[0])
+	}
+
+	fields, ok := recordTypeFields[recordType]
+	if !ok || len(fields) == 1 {
+		return formatCimValue(properties[0].Value)
+	}
+
+	values := make([]string, 0, len(fields))
+	for _, f := range fields {
+		for _, p := range properties {
+			if strings.EqualFold(p.Name, f.Name) {
+				if v := formatCimValue(p.Value); v != "" || !f.Optional {
+					values = append(values, v)
+				}
+				break
+			}
+		}
+	}
+
+	// Fall back to the order of the properties when they are named differently than expected.
+	if len(values) == 0 && len(properties) == len(fields) {
+		for _, p := range properties {
+			values = append(values, formatCimValue(p.Value))
+		}
+	}
+	return strings.Join(values, " ")
+}
+
+func formatCimValue(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			values = append(values, formatCimValue(v))
+		}
+		return strings.Join(values, " ")
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// NormalizeRecordData adds the trailing dot to every domain name field of
+// recordData, matching the form the server returns the record data in.
+// The values of a list field are sorted. Whitespace around the data is
+// removed, except for TXT records, whose whitespace may be significant.
+func NormalizeRecordData(recordType, recordData string) string {
+	if strings.EqualFold(recordType, RecordTypeTXT) {
+		return recordData
+	}
+	if recordType == RecordTypeCERT {
+		return normalizeCERTRecordData(recordData)
+	}
+	if recordType == RecordTypeDHCID {
+		return normalizeDHCIDRecordData(recordData)
+	}
+
+	recordData = strings.TrimSpace(recordData)
+	fields, ok := recordTypeFields[recordType]
+	if !ok {
+		return recordData
+	}
+
+	values, err := splitRecordData(recordType, recordData)
+	if err != nil {
+		return recordData
+	}
+
+	for i, v := range values {
+		if fields[i].List {
+			slices.SortFunc(values[i:], func(a, b string) int {
+				return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+			})
+			break
+		}
+		if fields[i].DomainName && !strings.HasSuffix(v, ".") {
+			values[i] = fmt.Sprintf("%s.", values[i])
+		}
+		values[i] = fields[i].name(values[i])
+	}
+	return strings.Join(values, " ")
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecordDataParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordType string
+		recordData string
+		want       map[string]any
+		wantErr    bool
+	}{
+		{"test-a", RecordTypeA, "203.0.113.11", map[string]any{"IPv4Address": "203.0.113.11"}, false},
+		{"test-afsdb", RecordTypeAFSDB, "1 afsdb.example.com", map[string]any{"SubType": "1", "ServerName": "afsdb.example.com"}, false},
+		{"test-afsdb-missing-field", RecordTypeAFSDB, "1", nil, true},
+		{"test-x25", RecordTypeX25, "311061700956", map[string]any{"PsdnAddress": "311061700956"}, false},
+		{"test-rt", RecordTypeRT, "10 relay.example.com", map[string]any{"Preference": "10", "IntermediateHost": "relay.example.com"}, false},
+		{"test-rt-missing-host", RecordTypeRT, "10", nil, true},
+		{"test-atma", RecordTypeATMA, "E164 358400123456", map[string]any{"AddressType": "E164", "Address": "358400123456"}, false},
+		{"test-atma-number", RecordTypeATMA, "0 39246f000e7c9c0312000100010000123456789000", map[string]any{"AddressType": "NSAP", "Address": "39246f000e7c9c0312000100010000123456789000"}, false},
+		{"test-atma-missing-address", RecordTypeATMA, "E164", nil, true},
+		{"test-isdn", RecordTypeISDN, "150862028003217 004", map[string]any{"IsdnNumber": "150862028003217", "IsdnSubAddress": "004"}, false},
+		{"test-isdn-without-subaddress", RecordTypeISDN, "150862028003217", map[string]any{"IsdnNumber": "150862028003217"}, false},
+		{"test-isdn-too-many-fields", RecordTypeISDN, "150862028003217 004 1", nil, true},
+		{"test-wks", RecordTypeWKS, "203.0.113.11 tcp smtp ftp", map[string]any{"InternetAddress": "203.0.113.11", "InternetProtocol": "tcp", "Service": []string{"smtp", "ftp"}}, false},
+		{"test-wks-missing-service", RecordTypeWKS, "203.0.113.11 tcp", nil, true},
+		{"test-ds", RecordTypeDS, "12345 8 2 49FD46E6C4B45C55D4AC", map[string]any{"KeyTag": "12345", "CryptoAlgorithm": "RsaSha256", "DigestType": "Sha256", "Digest": "49FD46E6C4B45C55D4AC"}, false},
+		{"test-ds-names", RecordTypeDS, "12345 ECDsaP256Sha256 Sha384 49FD46E6C4B45C55D4AC", map[string]any{"KeyTag": "12345", "CryptoAlgorithm": "ECDsaP256Sha256", "DigestType": "Sha384", "Digest": "49FD46E6C4B45C55D4AC"}, false},
+		{"test-unsupported", "MX", "10 mx.example.com", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := recordDataParams(tt.recordType, tt.recordData)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("recordDataParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recordDataParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatRecordData(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordType string
+		properties []CimInstanceProperties
+		want       string
+	}{
+		{"test-a", RecordTypeA, []CimInstanceProperties{{Name: "IPv4Address", Value: "203.0.113.11"}}, "203.0.113.11"},
+		{"test-afsdb", RecordTypeAFSDB, []CimInstanceProperties{{Name: "ServerName", Value: "afsdb.example.com."}, {Name: "SubType", Value: float64(1)}}, "1 afsdb.example.com."},
+		{"test-isdn", RecordTypeISDN, []CimInstanceProperties{{Name: "IsdnNumber", Value: "150862028003217"}, {Name: "IsdnSubAddress", Value: "004"}}, "150862028003217 004"},
+		{"test-isdn-without-subaddress", RecordTypeISDN, []CimInstanceProperties{{Name: "IsdnNumber", Value: "150862028003217"}, {Name: "IsdnSubAddress", Value: ""}}, "150862028003217"},
+		{"test-wks", RecordTypeWKS, []CimInstanceProperties{{Name: "InternetAddress", Value: "203.0.113.11"}, {Name: "InternetProtocol", Value: "TCP"}, {Name: "Service", Value: []any{"smtp", "ftp"}}}, "203.0.113.11 TCP smtp ftp"},
+		{"test-mb", RecordTypeMB, []CimInstanceProperties{{Name: "MBHost", Value: "mail.example.com."}}, "mail.example.com."},
+		{"test-rt", RecordTypeRT, []CimInstanceProperties{{Name: "IntermediateHost", Value: "relay.example.com."}, {Name: "Preference", Value: float64(10)}}, "10 relay.example.com."},
+		{"test-ns", RecordTypeNS, []CimInstanceProperties{{Name: "NameServer", Value: "ns1.sub.example.com."}}, "ns1.sub.example.com."},
+		{"test-atma", RecordTypeATMA, []CimInstanceProperties{{Name: "Address", Value: "358400123456"}, {Name: "AddressType", Value: float64(1)}}, "1 358400123456"},
+		{"test-minfo", RecordTypeMINFO, []CimInstanceProperties{{Name: "ErrorMailbox", Value: "errors.example.com."}, {Name: "ResponsibleMailbox", Value: "admin.example.com."}}, "admin.example.com. errors.example.com."},
+		{"test-minfo-unknown-names", RecordTypeMINFO, []CimInstanceProperties{{Name: "Mailbox", Value: "admin.example.com."}, {Name: "ErrorsMailbox", Value: "errors.example.com."}}, "admin.example.com. errors.example.com."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatRecordData(tt.recordType, tt.properties); got != tt.want {
+				t.Errorf("formatRecordData() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeRecordData(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordType string
+		recordData string
+		want       string
+	}{
+		{"test-afsdb", RecordTypeAFSDB, "1 afsdb.example.com", "1 afsdb.example.com."},
+		{"test-rp", RecordTypeRP, "admin.example.com. txt.example.com", "admin.example.com. txt.example.com."},
+		{"test-wks", RecordTypeWKS, "203.0.113.11 tcp smtp FTP http", "203.0.113.11 tcp FTP http smtp"},
+		{"test-ds", RecordTypeDS, "12345 13 2 49fd46e6c4b45c55d4ac", "12345 ECDsaP256Sha256 Sha256 49fd46e6c4b45c55d4ac"},
+		{"test-invalid", RecordTypeAFSDB, "1", "1"},
+		{"test-atma", RecordTypeATMA, "1 358400123456", "E164 358400123456"},
+		{"test-atma-name", RecordTypeATMA, "NSAP 39246f000e7c9c0312000100010000123456789000", "NSAP 39246f000e7c9c0312000100010000123456789000"},
+		{"test-minfo", RecordTypeMINFO, "admin.example.com errors.example.com.", "admin.example.com. errors.example.com."},
+		{"test-ns", RecordTypeNS, "ns1.sub.example.com", "ns1.sub.example.com."},
+		{"test-rt", RecordTypeRT, "10 relay.example.com", "10 relay.example.com."},
+		{"test-txt", RecordTypeTXT, `v=DKIM1; p=MIGf+/AbC=="quoted" \ $Var.`, `v=DKIM1; p=MIGf+/AbC=="quoted" \ $Var.`},
+		{"test-a-whitespace", RecordTypeA, " 203.0.113.11\t", "203.0.113.11"},
+		{"test-afsdb-whitespace", RecordTypeAFSDB, "1  afsdb.example.com ", "1 afsdb.example.com."},
+		{"test-txt-whitespace", RecordTypeTXT, " v=spf1  -all ", " v=spf1  -all "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeRecordData(tt.recordType, tt.recordData); got != tt.want {
+				t.Errorf("NormalizeRecordData() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ReverseName returns the reverse lookup name of address, e.g. 11.113.0.203.in-addr.arpa for 203.0.113.11.
+func ReverseName(address string) (string, error) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return "", fmt.Errorf("%q is not a valid IP address", address)
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		labels := make([]string, 0, net.IPv4len+1)
+		for i := len(ip4) - 1; i >= 0; i-- {
+			labels = append(labels, strconv.Itoa(int(ip4[i])))
+		}
+		return strings.Join(append(labels, "in-addr.arpa"), "."), nil
+	}
+
+	labels := make([]string, 0, net.IPv6len*2+1)
+	for i := len(ip) - 1; i >= 0; i-- {
+		labels = append(labels, strconv.FormatUint(uint64(ip[i]&0x0f), 16), strconv.FormatUint(uint64(ip[i]>>4), 16))
+	}
+	return strings.Join(append(labels, "ip6.arpa"), "."), nil
+}
+
+// classlessRange returns the range of last octets covered by an RFC 2317 classless delegation label, written either as
+// `<first>/<prefix-length>`, e.g. 0/26 for 0 to 63, or as `<first>-<last>`, e.g. 0-63.
+func classlessRange(label string) (int, int, bool) {
+	if first, prefix, ok := strings.Cut(label, "/"); ok {
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			return 0, 0, false
+		}
+		length, err := strconv.Atoi(prefix)
+		if err != nil || length < 24 || length > 32 {
+			return 0, 0, false
+		}
+		size := 1 << (32 - length)
+		if start < 0 || start%size != 0 || start+size > 256 {
+			return 0, 0, false
+		}
+		return start, start + size - 1, true
+	}
+	if first, last, ok := strings.Cut(label, "-"); ok {
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			return 0, 0, false
+		}
+		end, err := strconv.Atoi(last)
+		if err != nil || start < 0 || end < start || end > 255 {
+			return 0, 0, false
+		}
+		return start, end, true
+	}
+	return 0, 0, false
+}
+
+// classlessNameInZone returns the name of the PTR record for the reverse lookup name of an IPv4 address relative to
+// zoneName, when zoneName is an RFC 2317 classless reverse zone covering the address, e.g. 0/26.113.0.203.in-addr.arpa
+// for 203.0.113.11, whose PTR record is 11.
+func classlessNameInZone(reverseName, zoneName string) (string, bool) {
+	label, parent, ok := strings.Cut(zoneName, ".")
+	if !ok || !strings.HasSuffix(parent, ".in-addr.arpa") {
+		return "", false
+	}
+	start, end, ok := classlessRange(label)
+	if !ok {
+		return "", false
+	}
+
+	octet, rest, _ := strings.Cut(reverseName, ".")
+	value, err := strconv.Atoi(octet)
+	if err != nil || rest != parent || value < start || value > end {
+		return "", false
+	}
+	return octet, true
+}
+
+// ReverseZoneFor returns the most specific of zoneNames covering the reverse lookup name of address, which is the
+// zone the DNS server adds its PTR record to. The zone names must be lower case and without a trailing dot. RFC 2317
+// classless reverse zones are more specific than the zone they are delegated from.
+func ReverseZoneFor(address string, zoneNames map[string]bool) (string, bool) {
+	reverseName, err := ReverseName(address)
+	if err != nil {
+		return "", false
+	}
+	for zoneName := range zoneNames {
+		if _, ok := classlessNameInZone(reverseName, zoneName); ok {
+			return zoneName, true
+		}
+	}
+
+	labels := strings.Split(reverseName, ".")
+	for i := 1; i < len(labels); i++ {
+		zoneName := strings.Join(labels[i:], ".")
+		if zoneNames[zoneName] {
+			return zoneName, true
+		}
+	}
+	return "", false
+}
+
+// ReverseNameInZone returns the name of the PTR record for address relative to
+// the reverse zone zoneName, or an error if the zone does not cover the address.
+// The zone may be an RFC 2317 classless reverse zone, e.g. 0/26.113.0.203.in-addr.arpa.
+func ReverseNameInZone(address, zoneName string) (string, error) {
+	reverseName, err := ReverseName(address)
+	if err != nil {
+		return "", err
+	}
+	if name, ok := classlessNameInZone(reverseName, strings.ToLower(strings.TrimSuffix(zoneName, "."))); ok {
+		return name, nil
+	}
+
+	suffix := "." + strings.ToLower(strings.TrimSuffix(zoneName, "."))
+	if !strings.HasSuffix(reverseName, suffix) {
+		return "", fmt.Errorf("reverse zone %q does not cover address %s (%s)", zoneName, address, reverseName)
+	}
+	return strings.TrimSuffix(reverseName, suffix), nil
+}
+
+// IsReverseZoneName reports whether zoneName is a reverse lookup zone, below in-addr.arpa or ip6.arpa.
+func IsReverseZoneName(zoneName string) bool {
+	zoneName = strings.ToLower(strings.TrimSuffix(zoneName, "."))
+	return strings.HasSuffix(zoneName, ".in-addr.arpa") || strings.HasSuffix(zoneName, ".ip6.arpa")
+}
+
+// isDNSSDName reports whether hostName is a DNS-SD service name, e.g. _http._tcp, whose PTR records list the instances
+// of a service in a forward lookup zone.
+func isDNSSDName(hostName string) bool {
+	for _, label := range strings.Split(hostName, ".") {
+		if strings.HasPrefix(label, "_") {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckRecordZone rejects records of recordType named hostName that do not belong in the kind of zone zoneName is: A
+// and AAAA records in reverse lookup zones, PTR records in forward lookup zones, except for DNS-SD service names, and
+// PTR records in reverse lookup zones whose names are not the remaining labels of an address.
+func CheckRecordZone(zoneName, hostName, recordType string) error {
+	recordType = strings.ToUpper(recordType)
+	zoneName = strings.ToLower(strings.TrimSuffix(zoneName, "."))
+	reverse := IsReverseZoneName(zoneName)
+
+	switch {
+	case reverse && (recordType == RecordTypeA || recordType == RecordTypeAAAA):
+		return fmt.Errorf("%s records cannot be created in the reverse lookup zone %q, which maps addresses to names. Create them in a forward lookup zone, and their PTR records with create_ptr", recordType, zoneName)
+	case !reverse && recordType == RecordTypePTR:
+		if isDNSSDName(hostName) {
+			return nil
+		}
+		return fmt.Errorf("PTR records cannot be created in the forward lookup zone %q, as they map addresses to names and belong in reverse lookup zones below in-addr.arpa or ip6.arpa. Only DNS-SD service names, e.g. _http._tcp, have PTR records in forward lookup zones", zoneName)
+	case reverse && recordType == RecordTypePTR:
+		return checkReverseName(zoneName, hostName)
+	}
+	return nil
+}
+
+// checkReverseName rejects names of PTR records in the reverse lookup zone zoneName that are not the remaining labels
+// of the reverse lookup name of an address: decimal octets in in-addr.arpa, and hexadecimal nibbles in ip6.arpa.
+func checkReverseName(zoneName, hostName string) error {
+	if IsApexName(hostName) {
+		return nil
+	}
+	names := strings.Split(strings.TrimPrefix(hostName, WildcardLabel+"."), ".")
+
+	if zoneLabels, ok := strings.CutSuffix(zoneName, ".in-addr.arpa"); ok {
+		octets := strings.Split(zoneLabels, ".")
+		// The records of an RFC 2317 classless reverse zone are named by the last octet, which its first label is not.
+		if _, _, ok := classlessRange(octets[0]); ok {
+			octets = octets[1:]
+		}
+		if len(names)+len(octets) > net.IPv4len {
+			return fmt.Errorf("%q is not a valid name for a PTR record in the reverse lookup zone %q, as an IPv4 address has only %d octets. The name is the remaining octets of the address in reverse order, e.g. 11 for 203.0.113.11 in 113.0.203.in-addr.arpa", hostName, zoneName, net.IPv4len)
+		}
+		for _, label := range names {
+			if octet, err := strconv.Atoi(label); err != nil || octet < 0 || octet > 255 || strconv.Itoa(octet) != label {
+				return fmt.Errorf("%q is not a valid name for a PTR record in the reverse lookup zone %q. The name is the remaining octets of the address in reverse order, e.g. 11 for 203.0.113.11 in 113.0.203.in-addr.arpa", hostName, zoneName)
+			}
+		}
+		return nil
+	}
+
+	zoneLabels := strings.TrimSuffix(zoneName, ".ip6.arpa")
+	if len(names)+len(strings.Split(zoneLabels, ".")) > net.IPv6len*2 {
+		return fmt.Errorf("%q is not a valid name for a PTR record in the reverse lookup zone %q, as an IPv6 address has only %d nibbles. The name is the remaining nibbles of the address in reverse order, e.g. 1.0.0.0 for 2001:db8::1 in a zone of 2001:db8::/112", hostName, zoneName, net.IPv6len*2)
+	}
+	for _, label := range names {
+		if len(label) != 1 || !strings.Contains("0123456789abcdef", strings.ToLower(label)) {
+			return fmt.Errorf("%q is not a valid name for a PTR record in the reverse lookup zone %q. The name is the remaining nibbles of the address in reverse order, each a single hexadecimal digit, e.g. 1.0.0.0 for 2001:db8::1 in a zone of 2001:db8::/112", hostName, zoneName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import "testing"
+
+func TestReverseNameInZone(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		zoneName string
+		want     string
+		wantErr  bool
+	}{
+		{"test-ipv4", "10.10.113.12", "10.10.in-addr.arpa", "12.113", false},
+		{"test-ipv4-trailing-dot", "10.10.113.12", "10.10.in-addr.arpa.", "12.113", false},
+		{"test-ipv4-uncovered", "10.11.113.12", "10.10.in-addr.arpa", "", true},
+		{"test-ipv4-partial-label", "10.110.113.12", "10.in-addr.arpa", "12.113.110", false},
+		{"test-ipv4-label-boundary", "110.10.113.12", "10.in-addr.arpa", "", true},
+		{"test-ipv6", "2001:db8::1", "8.b.d.0.1.0.0.2.ip6.arpa", "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0", false},
+		{"test-ipv6-in-ipv4-zone", "2001:db8::1", "10.10.in-addr.arpa", "", true},
+		{"test-classless", "203.0.113.11", "0/26.113.0.203.in-addr.arpa", "11", false},
+		{"test-classless-upper-bound", "203.0.113.63", "0/26.113.0.203.in-addr.arpa.", "63", false},
+		{"test-classless-uncovered", "203.0.113.70", "0/26.113.0.203.in-addr.arpa", "", true},
+		{"test-classless-other-network", "203.0.114.11", "0/26.113.0.203.in-addr.arpa", "", true},
+		{"test-classless-range", "203.0.113.70", "64-127.113.0.203.in-addr.arpa", "70", false},
+		{"test-classless-misaligned", "203.0.113.11", "10/26.113.0.203.in-addr.arpa", "", true},
+		{"test-invalid-address", "example.com", "10.10.in-addr.arpa", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReverseNameInZone(tt.address, tt.zoneName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReverseNameInZone() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ReverseNameInZone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReverseZoneFor(t *testing.T) {
+	zoneNames := map[string]bool{
+		"10.in-addr.arpa":             true,
+		"10.10.in-addr.arpa":          true,
+		"8.b.d.0.1.0.0.2.ip6.arpa":    true,
+		"113.0.203.in-addr.arpa":      true,
+		"0/26.113.0.203.in-addr.arpa": true,
+	}
+	tests := []struct {
+		name    string
+		address string
+		want    string
+		wantOk  bool
+	}{
+		{"test-ipv4", "10.11.113.12", "10.in-addr.arpa", true},
+		{"test-ipv4-most-specific", "10.10.113.12", "10.10.in-addr.arpa", true},
+		{"test-ipv4-uncovered", "198.51.100.11", "", false},
+		{"test-classless", "203.0.113.11", "0/26.113.0.203.in-addr.arpa", true},
+		{"test-classless-uncovered", "203.0.113.70", "113.0.203.in-addr.arpa", true},
+		{"test-ipv6", "2001:db8::1", "8.b.d.0.1.0.0.2.ip6.arpa", true},
+		{"test-invalid-address", "example.com", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ReverseZoneFor(tt.address, zoneNames)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ReverseZoneFor() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestCheckRecordZone(t *testing.T) {
+	tests := []struct {
+		name       string
+		zoneName   string
+		hostName   string
+		recordType string
+		wantErr    bool
+	}{
+		{"test-ptr", "10.10.in-addr.arpa", "12.113", "PTR", false},
+		{"test-ptr-trailing-dot", "10.10.in-addr.arpa.", "12.113", "ptr", false},
+		{"test-ptr-apex", "12.113.10.10.in-addr.arpa", "@", "PTR", false},
+		{"test-ptr-wildcard", "10.10.in-addr.arpa", "*.113", "PTR", false},
+		{"test-ptr-classless", "0/26.113.10.10.in-addr.arpa", "12", "PTR", false},
+		{"test-ptr-classless-too-many-octets", "0/26.113.10.10.in-addr.arpa", "12.113", "PTR", true},
+		{"test-ptr-forward-name", "10.10.in-addr.arpa", "www", "PTR", true},
+		{"test-ptr-octet-out-of-range", "10.10.in-addr.arpa", "12.300", "PTR", true},
+		{"test-ptr-leading-zero", "10.10.in-addr.arpa", "012.113", "PTR", true},
+		{"test-ptr-too-many-octets", "10.10.in-addr.arpa", "12.113.10.10", "PTR", true},
+		{"test-ptr-ipv6", "8.b.d.0.1.0.0.2.ip6.arpa", "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0", "PTR", false},
+		{"test-ptr-ipv6-upper", "8.b.d.0.1.0.0.2.ip6.arpa", "A.0", "PTR", false},
+		{"test-ptr-ipv6-not-nibble", "8.b.d.0.1.0.0.2.ip6.arpa", "10.0", "PTR", true},
+		{"test-ptr-ipv6-too-many-nibbles", "8.b.d.0.1.0.0.2.ip6.arpa", "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0", "PTR", true},
+		{"test-ptr-forward-zone", "example.com", "www", "PTR", true},
+		{"test-ptr-dns-sd", "example.com", "_http._tcp", "PTR", false},
+		{"test-ptr-dns-sd-browse", "example.com", "b._dns-sd._udp", "PTR", false},
+		{"test-a-reverse-zone", "10.10.in-addr.arpa", "12.113", "A", true},
+		{"test-aaaa-reverse-zone", "8.b.d.0.1.0.0.2.ip6.arpa", "www", "aaaa", true},
+		{"test-a-forward-zone", "example.com", "www", "A", false},
+		{"test-cname-reverse-zone", "113.10.10.in-addr.arpa", "12", "CNAME", false},
+		{"test-ns-reverse-zone", "113.10.10.in-addr.arpa", "0/26", "NS", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRecordZone(tt.zoneName, tt.hostName, tt.recordType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckRecordZone() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
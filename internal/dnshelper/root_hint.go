@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// RootHint is a root hint of a DNS server: a root name server the server queries for the names it cannot answer from
+// its zones, conditional forwarders or cache, and the glue addresses it reaches the root server on.
+type RootHint struct {
+	// NameServer is the fully qualified name of the root server, with a trailing `.`.
+	NameServer string `json:"NameServer"`
+	// IPAddresses are the glue addresses of the root server, in the order the server returns them.
+	IPAddresses []string `json:"IPAddress"`
+}
+
+// getRootHintsScript writes the root hints of the server as RootHint objects. Get-DnsServerRootHint returns the NS
+// record of every root server along with its A and AAAA records, so the name and addresses are taken from their record
+// data. The hints are passed with -InputObject, so the output is an array even with no or a single hint.
+const getRootHintsScript = `$ErrorActionPreference = 'Stop'; ` +
+	`$hints = @(Get-DnsServerRootHint @params | ForEach-Object { [pscustomobject]@{ ` +
+	`NameServer = $_.NameServer.RecordData.NameServer; ` +
+	`IPAddress = @($_.IPAddress | ForEach-Object { if ($_.RecordType -eq 'AAAA') { $_.RecordData.IPv6Address.IPAddressToString } ` +
+	`else { $_.RecordData.IPv4Address.IPAddressToString } }) } }); ` +
+	`ConvertTo-Json -InputObject $hints -Depth 3 -Compress`
+
+// GetRootHints returns the root hints of the DNS server.
+func GetRootHints(ctx context.Context, conf *config.ProviderConf) ([]RootHint, error) {
+	psOpts := CreatePSCommandOpts{
+		Username: conf.Settings.SshUsername,
+		Password: conf.Settings.SshPassword,
+		Server:   conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSScript("Get-DnsServerRootHint", getRootHintsScript, nil, psOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return nil, fmt.Errorf("ssh execution failure in GetRootHints: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, newPSCommandError("Get-DnsServerRootHint", result)
+	}
+
+	return unmarshallRootHints(ctx, []byte(result.Stdout))
+}
+
+// GetRootHint returns the root hint of the root server nameServer, which may be written with or without the trailing
+// `.`. A root server without a root hint is an ObjectNotFound error.
+func GetRootHint(ctx context.Context, conf *config.ProviderConf, nameServer string) (*RootHint, error) {
+	hints, err := GetRootHints(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+	for _, hint := range hints {
+		if sameRootServer(hint.NameServer, nameServer) {
+			return &hint, nil
+		}
+	}
+	return nil, fmt.Errorf("ObjectNotFound: no root hint found for %q", nameServer)
+}
+
+// AddRootHint adds the addresses to the root hint of the root server nameServer, adding the root hint when the server
+// has none for it.
+func AddRootHint(ctx context.Context, conf *config.ProviderConf, nameServer string, addresses []string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+	params := map[string]any{
+		"NameServer": nameServer,
+		"IPAddress":  addresses,
+	}
+	return runZoneCommand(ctx, conf, "Add-DnsServerRootHint", params)
+}
+
+// RemoveRootHint removes the addresses from the root hint of the root server nameServer, or the whole root hint when
+// addresses is empty.
+func RemoveRootHint(ctx context.Context, conf *config.ProviderConf, nameServer string, addresses []string) error {
+	params := map[string]any{
+		"NameServer": nameServer,
+		"Force":      true,
+	}
+	if len(addresses) > 0 {
+		params["IPAddress"] = addresses
+	}
+	return runZoneCommand(ctx, conf, "Remove-DnsServerRootHint", params)
+}
+
+// sameRootServer reports whether a and b name the same root server, ignoring the case and the trailing `.`.
+func sameRootServer(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
+
+func unmarshallRootHints(ctx context.Context, input []byte) ([]RootHint, error) {
+	var hints []RootHint
+
+	doc, err := jsonDocument(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(doc, &hints)
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Failed to unmarshall a root hints json document with error %q, document was %s", err, string(input)))
+		return nil, fmt.Errorf("failed while unmarshalling root hints json document: %s", err)
+	}
+	return hints, nil
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+const testRootHints = `[{"NameServer":"a.root-servers.net.","IPAddress":["198.41.0.4","2001:503:ba3e::2:30"]},` +
+	`{"NameServer":"root.example.test.","IPAddress":["192.0.2.1"]}]`
+
+func TestGetRootHints(t *testing.T) {
+	conf, executor := newFakeConf(&config.CommandOutput{Stdout: testRootHints})
+
+	got, err := GetRootHints(context.Background(), conf)
+	if err != nil {
+		t.Fatalf("GetRootHints() error = %s", err)
+	}
+	want := []RootHint{
+		{NameServer: "a.root-servers.net.", IPAddresses: []string{"198.41.0.4", "2001:503:ba3e::2:30"}},
+		{NameServer: "root.example.test.", IPAddresses: []string{"192.0.2.1"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetRootHints() = %+v, want %+v", got, want)
+	}
+
+	if !strings.Contains(executor.scripts[0], "Get-DnsServerRootHint @params") {
+		t.Errorf("command does not run Get-DnsServerRootHint: %s", executor.scripts[0])
+	}
+	if params := executor.params(t, 0); !reflect.DeepEqual(params, map[string]any{"ComputerName": "dc01.example.com"}) {
+		t.Errorf("params = %v, want ComputerName dc01.example.com", params)
+	}
+}
+
+func TestGetRootHint(t *testing.T) {
+	tests := []struct {
+		name        string
+		nameServer  string
+		output      string
+		want        *RootHint
+		wantMissing bool
+	}{
+		{
+			name:       "test-fqdn",
+			nameServer: "root.example.test.",
+			output:     testRootHints,
+			want:       &RootHint{NameServer: "root.example.test.", IPAddresses: []string{"192.0.2.1"}},
+		},
+		{
+			name:       "test-without-dot-casemix",
+			nameServer: "A.Root-Servers.net",
+			output:     testRootHints,
+			want:       &RootHint{NameServer: "a.root-servers.net.", IPAddresses: []string{"198.41.0.4", "2001:503:ba3e::2:30"}},
+		},
+		{
+			name:        "test-missing",
+			nameServer:  "b.root-servers.net",
+			output:      testRootHints,
+			wantMissing: true,
+		},
+		{
+			name:        "test-no-root-hints",
+			nameServer:  "a.root-servers.net",
+			output:      `[]`,
+			wantMissing: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf, _ := newFakeConf(&config.CommandOutput{Stdout: tt.output})
+			got, err := GetRootHint(context.Background(), conf, tt.nameServer)
+			if tt.wantMissing {
+				if !IsNotFound(err) {
+					t.Errorf("GetRootHint() error = %v, want a not found error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetRootHint() error = %s", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetRootHint() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddRemoveRootHint(t *testing.T) {
+	conf, executor := newFakeConf()
+
+	if err := AddRootHint(context.Background(), conf, "root.example.test.", []string{"192.0.2.1", "2001:db8::1"}); err != nil {
+		t.Fatalf("AddRootHint() error = %s", err)
+	}
+	if err := RemoveRootHint(context.Background(), conf, "root.example.test.", []string{"192.0.2.2"}); err != nil {
+		t.Fatalf("RemoveRootHint() error = %s", err)
+	}
+	if err := RemoveRootHint(context.Background(), conf, "root.example.test.", nil); err != nil {
+		t.Fatalf("RemoveRootHint() error = %s", err)
+	}
+
+	wantParams := []map[string]any{
+		{"NameServer": "root.example.test.", "IPAddress": []any{"192.0.2.1", "2001:db8::1"}, "ComputerName": "dc01.example.com"},
+		{"NameServer": "root.example.test.", "IPAddress": []any{"192.0.2.2"}, "Force": true, "ComputerName": "dc01.example.com"},
+		{"NameServer": "root.example.test.", "Force": true, "ComputerName": "dc01.example.com"},
+	}
+	for i, want := range wantParams {
+		if params := executor.params(t, i); !reflect.DeepEqual(params, want) {
+			t.Errorf("params of command %d = %v, want %v", i, params, want)
+		}
+	}
+
+	if err := AddRootHint(context.Background(), conf, "root.example.test.", nil); err != nil || len(executor.scripts) != 3 {
+		t.Errorf("AddRootHint() without addresses ran %d commands, error = %v, want no command", len(executor.scripts)-3, err)
+	}
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// ServerScavenging holds the server wide scavenging configuration of a DNS server. The intervals are in hours.
+type ServerScavenging struct {
+	ScavengingState    bool
+	ScavengingInterval int64
+	RefreshInterval    int64
+	NoRefreshInterval  int64
+	// LastScavengeTime is the time the server last scavenged stale records, or the zero time if it never has.
+	LastScavengeTime time.Time
+}
+
+// serverScavenging holds the fields we use from the object returned by Get-DnsServerScavenging.
+type serverScavenging struct {
+	ScavengingState    bool      `json:"ScavengingState"`
+	ScavengingInterval TTL       `json:"ScavengingInterval"`
+	RefreshInterval    TTL       `json:"RefreshInterval"`
+	NoRefreshInterval  TTL       `json:"NoRefreshInterval"`
+	LastScavengeTime   Timestamp `json:"LastScavengeTime"`
+}
+
+// setServerScavengingScript runs Set-DnsServerScavenging with the parameters in $params, converting the intervals from
+// hours.
+const setServerScavengingScript = `$ErrorActionPreference = 'Stop'; ` +
+	`foreach ($name in 'ScavengingInterval', 'RefreshInterval', 'NoRefreshInterval') { ` +
+	`if ($params.ContainsKey($name)) { $params[$name] = [TimeSpan]::FromHours($params[$name]) } }; ` +
+	`Set-DnsServerScavenging @params`
+
+// GetServerScavenging returns the scavenging configuration of the DNS server.
+func GetServerScavenging(ctx context.Context, conf *config.ProviderConf) (*ServerScavenging, error) {
+	psOpts := CreatePSCommandOpts{
+		JSONOutput: true,
+		JSONDepth:  2,
+		Username:   conf.Settings.SshUsername,
+		Password:   conf.Settings.SshPassword,
+		Server:     conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSCommand("Get-DnsServerScavenging", nil, psOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return nil, fmt.Errorf("ssh execution failure in GetServerScavenging: %s", err)
+	}
+
+	if result.ExitCode != 0 {
+		return nil, newPSCommandError("Get-DnsServerScavenging", result)
+	}
+
+	scavenging, err := unmarshallServerScavenging(ctx, []byte(result.Stdout))
+	if err != nil {
+		return nil, fmt.Errorf("GetServerScavenging: %s", err)
+	}
+	return scavenging, nil
+}
+
+// Update changes the scavenging configuration of the DNS server. The keys of changes are the Set-DnsServerScavenging
+// parameters to change: ScavengingState, ScavengingInterval, RefreshInterval and NoRefreshInterval.
+func (s *ServerScavenging) Update(ctx context.Context, conf *config.ProviderConf, changes map[string]any) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	params := make(map[string]any, len(changes))
+	for k, v := range changes {
+		params[k] = v
+	}
+
+	psOpts := CreatePSCommandOpts{
+		Username: conf.Settings.SshUsername,
+		Password: conf.Settings.SshPassword,
+		Server:   conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSScript("Set-DnsServerScavenging", setServerScavengingScript, params, psOpts)
+	if err != nil {
+		return err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("ssh execution failure while updating server scavenging: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return newPSCommandError("Set-DnsServerScavenging", result)
+	}
+	return nil
+}
+
+func unmarshallServerScavenging(ctx context.Context, input []byte) (*ServerScavenging, error) {
+	var scavenging serverScavenging
+
+	doc, err := jsonDocument(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(doc, &scavenging)
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Failed to unmarshall a server scavenging json document with error %q, document was %s", err, string(input)))
+		return nil, fmt.Errorf("failed while unmarshalling server scavenging json document: %s", err)
+	}
+
+	return &ServerScavenging{
+		ScavengingState:    scavenging.ScavengingState,
+		ScavengingInterval: scavenging.ScavengingInterval.TotalSeconds / 3600,
+		RefreshInterval:    scavenging.RefreshInterval.TotalSeconds / 3600,
+		NoRefreshInterval:  scavenging.NoRefreshInterval.TotalSeconds / 3600,
+		LastScavengeTime:   scavenging.LastScavengeTime.Time,
+	}, nil
+}
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+func TestGetServerScavenging(t *testing.T) {
+	conf, executor := newFakeConf(&config.CommandOutput{Stdout: `{
+  "NoRefreshInterval": { "Ticks": 6048000000000, "TotalHours": 168, "TotalSeconds": 604800 },
+  "RefreshInterval": { "Ticks": 6048000000000, "TotalHours": 168, "TotalSeconds": 604800 },
+  "ScavengingInterval": { "Ticks": 6048000000000, "TotalHours": 168, "TotalSeconds": 604800 },
+  "ScavengingState": true,
+  "LastScavengeTime": "/Date(1700000000000)/"
+}`})
+
+	got, err := GetServerScavenging(context.Background(), conf)
+	if err != nil {
+		t.Fatalf("GetServerScavenging() error = %s", err)
+	}
+	want := &ServerScavenging{
+		ScavengingState:    true,
+		ScavengingInterval: 168,
+		RefreshInterval:    168,
+		NoRefreshInterval:  168,
+		LastScavengeTime:   time.UnixMilli(1700000000000).UTC(),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetServerScavenging() = %+v, want %+v", got, want)
+	}
+	if params := executor.params(t, 0); params["ComputerName"] != "dc01.example.com" {
+		t.Errorf("params = %v, want ComputerName dc01.example.com", params)
+	}
+}
+
+func TestGetServerScavenging_NeverScavenged(t *testing.T) {
+	conf, _ := newFakeConf(&config.CommandOutput{Stdout: `{
+  "NoRefreshInterval": { "TotalSeconds": 604800 },
+  "RefreshInterval": { "TotalSeconds": 604800 },
+  "ScavengingInterval": { "TotalSeconds": 0 },
+  "ScavengingState": false,
+  "LastScavengeTime": null
+}`})
+
+	got, err := GetServerScavenging(context.Background(), conf)
+	if err != nil {
+		t.Fatalf("GetServerScavenging() error = %s", err)
+	}
+	if got.ScavengingInterval != 0 || !got.LastScavengeTime.IsZero() {
+		t.Errorf("GetServerScavenging() = %+v, want no scavenging interval and no last scavenge time", got)
+	}
+}
+
+func TestServerScavengingUpdate(t *testing.T) {
+	conf, executor := newFakeConf()
+	scavenging := ServerScavenging{}
+
+	changes := map[string]any{"ScavengingState": true, "ScavengingInterval": 24}
+	if err := scavenging.Update(context.Background(), conf, changes); err != nil {
+		t.Fatalf("Update() error = %s", err)
+	}
+	want := map[string]any{
+		"ScavengingState":    true,
+		"ScavengingInterval": float64(24),
+		"ComputerName":       "dc01.example.com",
+	}
+	if params := executor.params(t, 0); !reflect.DeepEqual(params, want) {
+		t.Errorf("params = %v, want %v", params, want)
+	}
+}
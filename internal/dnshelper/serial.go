@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// The provider's verify_serial setting, choosing how a zone serial number that did not advance after changing
+// records of the zone is reported. It is not verified when the setting is empty.
+const (
+	VerifySerialWarn  = "warn"
+	VerifySerialError = "error"
+)
+
+// SerialCheck holds the serial number of a zone before changing its records, to verify that the DNS server
+// incremented it along with the changes.
+type SerialCheck struct {
+	ZoneName string
+	Serial   int64
+}
+
+// StartSerialCheck reads the current serial number of zoneName.
+func StartSerialCheck(ctx context.Context, conf *config.ProviderConf, zoneName string) (*SerialCheck, error) {
+	soa, err := GetSOA(ctx, conf, zoneName)
+	if err != nil {
+		return nil, err
+	}
+	return &SerialCheck{ZoneName: zoneName, Serial: soa.SerialNumber}, nil
+}
+
+// Verify reads the serial number of the zone again, returning an error if it did not advance since the check
+// started. This catches changes the DNS server reported as successful without applying them.
+func (c *SerialCheck) Verify(ctx context.Context, conf *config.ProviderConf) error {
+	soa, err := GetSOA(ctx, conf, c.ZoneName)
+	if err != nil {
+		return err
+	}
+	if !serialAdvanced(c.Serial, soa.SerialNumber) {
+		return fmt.Errorf("the serial number of zone %q is still %d after changing its records, so the DNS server may not have applied the changes", c.ZoneName, soa.SerialNumber)
+	}
+	return nil
+}
+
+// serialAdvanced reports whether serial number next is greater than previous in serial number arithmetic (RFC 1982),
+// where serial numbers wrap around after 2^32-1.
+func serialAdvanced(previous, next int64) bool {
+	d := uint32(next) - uint32(previous)
+	return d != 0 && d < 1<<31
+}
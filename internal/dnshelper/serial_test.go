@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+func TestSerialAdvanced(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous int64
+		next     int64
+		want     bool
+	}{
+		{"test-incremented", 2024010101, 2024010102, true},
+		{"test-unchanged", 2024010101, 2024010101, false},
+		{"test-decremented", 2024010102, 2024010101, false},
+		{"test-wrapped", 4294967295, 1, true},
+		{"test-too-far", 1, 1 + 1<<31, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serialAdvanced(tt.previous, tt.next); got != tt.want {
+				t.Errorf("serialAdvanced() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSerialCheck_Executor(t *testing.T) {
+	soa := func(serial string) *config.CommandOutput {
+		return &config.CommandOutput{Stdout: `[{ "RecordData": { "PrimaryServer": "dc01.example.com.", "SerialNumber": ` + serial + ` } }]`}
+	}
+	conf, executor := newFakeConf(soa("41"), soa("41"), soa("42"))
+
+	check, err := StartSerialCheck(context.Background(), conf, "example.com")
+	if err != nil {
+		t.Fatalf("StartSerialCheck() error = %s", err)
+	}
+	if err := check.Verify(context.Background(), conf); err == nil {
+		t.Errorf("Verify() of an unchanged serial number returned no error")
+	}
+	if err := check.Verify(context.Background(), conf); err != nil {
+		t.Errorf("Verify() of an incremented serial number error = %s", err)
+	}
+	if params := executor.params(t, 0); params["ZoneName"] != "example.com" || params["RRType"] != "SOA" {
+		t.Errorf("params = %v, want the SOA record of example.com", params)
+	}
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// ServerStatus holds the version information of the DNS server and of the DnsServer module used to manage it.
+type ServerStatus struct {
+	ComputerName  string `json:"ComputerName"`
+	MajorVersion  int    `json:"MajorVersion"`
+	MinorVersion  int    `json:"MinorVersion"`
+	BuildNumber   int    `json:"BuildNumber"`
+	ModuleVersion string `json:"ModuleVersion"`
+}
+
+// Version returns the version of the DNS server, which is the version of Windows it runs on.
+func (s *ServerStatus) Version() string {
+	return fmt.Sprintf("%d.%d.%d", s.MajorVersion, s.MinorVersion, s.BuildNumber)
+}
+
+// serverStatusScript reads the settings of the DNS server, which fails unless the server can be managed,
+// and the version of the DnsServer module on the host running the commands.
+const serverStatusScript = `$ErrorActionPreference = 'Stop'; ` +
+	`$setting = Get-DnsServerSetting @params; ` +
+	`$module = Get-Module -ListAvailable -Name DnsServer | Sort-Object -Property Version -Descending | Select-Object -First 1; ` +
+	`[pscustomobject]@{ ComputerName = $setting.ComputerName; MajorVersion = $setting.MajorVersion; ` +
+	`MinorVersion = $setting.MinorVersion; BuildNumber = $setting.BuildNumber; ModuleVersion = "$($module.Version)" } | ConvertTo-Json -Depth 1 -Compress`
+
+// GetServerStatus runs a trivial command against the DNS server to check that it can be managed, and returns its version.
+func GetServerStatus(ctx context.Context, conf *config.ProviderConf) (*ServerStatus, error) {
+	psOpts := CreatePSCommandOpts{
+		Username: conf.Settings.SshUsername,
+		Password: conf.Settings.SshPassword,
+		Server:   conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSScript("Get-DnsServerSetting", serverStatusScript, nil, psOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return nil, fmt.Errorf("ssh execution failure in GetServerStatus: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, newPSCommandError("Get-DnsServerSetting", result)
+	}
+
+	return unmarshallServerStatus(ctx, []byte(result.Stdout))
+}
+
+func unmarshallServerStatus(ctx context.Context, input []byte) (*ServerStatus, error) {
+	var status ServerStatus
+
+	doc, err := jsonDocument(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(doc, &status)
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Failed to unmarshall a server status json document with error %q, document was %s", err, string(input)))
+		return nil, fmt.Errorf("failed while unmarshalling server status json document: %s", err)
+	}
+	return &status, nil
+}
@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUnmarshallServerStatus(t *testing.T) {
+	input := `{
+  "ComputerName": "dc01.example.com",
+  "MajorVersion": 10,
+  "MinorVersion": 0,
+  "BuildNumber": 20348,
+  "ModuleVersion": "2.0.0.0"
+}`
+
+	got, err := unmarshallServerStatus(context.Background(), []byte(input))
+	if err != nil {
+		t.Fatalf("unmarshallServerStatus() error = %v", err)
+	}
+	if got.ComputerName != "dc01.example.com" {
+		t.Errorf("ComputerName = %q, want %q", got.ComputerName, "dc01.example.com")
+	}
+	if got.Version() != "10.0.20348" {
+		t.Errorf("Version() = %q, want %q", got.Version(), "10.0.20348")
+	}
+	if got.ModuleVersion != "2.0.0.0" {
+		t.Errorf("ModuleVersion = %q, want %q", got.ModuleVersion, "2.0.0.0")
+	}
+}
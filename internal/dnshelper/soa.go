@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// SOA holds the start of authority parameters of a zone. The intervals are in seconds.
+type SOA struct {
+	ZoneName          string
+	PrimaryServer     string
+	ResponsiblePerson string
+	SerialNumber      int64
+	RefreshInterval   int64
+	RetryDelay        int64
+	ExpireLimit       int64
+	MinimumTimeToLive int64
+}
+
+// soaRecord holds the fields we use from the SOA record returned by Get-DnsServerResourceRecord.
+type soaRecord struct {
+	RecordData struct {
+		PrimaryServer     string `json:"PrimaryServer"`
+		ResponsiblePerson string `json:"ResponsiblePerson"`
+		SerialNumber      int64  `json:"SerialNumber"`
+		RefreshInterval   TTL    `json:"RefreshInterval"`
+		RetryDelay        TTL    `json:"RetryDelay"`
+		ExpireLimit       TTL    `json:"ExpireLimit"`
+		MinimumTimeToLive TTL    `json:"MinimumTimeToLive"`
+	} `json:"RecordData"`
+}
+
+// setSOAScript replaces the SOA record of a zone with a copy where the properties given in $params are changed.
+// The intervals are given in seconds.
+const setSOAScript = `$ErrorActionPreference = 'Stop'; ` +
+	`$scope = @{ ZoneName = $params.ZoneName }; ` +
+	`if ($params.ContainsKey('ComputerName')) { $scope.ComputerName = $params.ComputerName }; ` +
+	`$old = Get-DnsServerResourceRecord @scope -RRType SOA; ` +
+	`$new = $old.Clone(); ` +
+	`foreach ($name in 'PrimaryServer', 'ResponsiblePerson') { if ($params.ContainsKey($name)) { $new.RecordData.$name = $params[$name] } }; ` +
+	`if ($params.ContainsKey('SerialNumber')) { $new.RecordData.SerialNumber = [uint32]$params.SerialNumber }; ` +
+	`foreach ($name in 'RefreshInterval', 'RetryDelay', 'ExpireLimit', 'MinimumTimeToLive') { ` +
+	`if ($params.ContainsKey($name)) { $new.RecordData.$name = [TimeSpan]::FromSeconds($params[$name]) } }; ` +
+	`Set-DnsServerResourceRecord @scope -OldInputObject $old -NewInputObject $new`
+
+// GetSOA returns the SOA parameters of zoneName.
+func GetSOA(ctx context.Context, conf *config.ProviderConf, zoneName string) (*SOA, error) {
+	params := map[string]any{
+		"ZoneName": zoneName,
+		"RRType":   "SOA",
+	}
+
+	psOpts := CreatePSCommandOpts{
+		JSONOutput: true,
+		JSONDepth:  4,
+		ForceArray: true,
+		Username:   conf.Settings.SshUsername,
+		Password:   conf.Settings.SshPassword,
+		Server:     conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSCommand("Get-DnsServerResourceRecord", params, psOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return nil, fmt.Errorf("ssh execution failure in GetSOA: %s", err)
+	}
+
+	if result.ExitCode != 0 {
+		return nil, newPSCommandError("Get-DnsServerResourceRecord", result)
+	}
+
+	soa, err := unmarshallSOA(ctx, []byte(result.Stdout))
+	if err != nil {
+		return nil, fmt.Errorf("GetSOA: %s", err)
+	}
+	soa.ZoneName = zoneName
+	return soa, nil
+}
+
+// Update changes the SOA parameters of the zone. The keys of changes are the names of the SOA fields to change.
+func (s *SOA) Update(ctx context.Context, conf *config.ProviderConf, changes map[string]any) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	params := map[string]any{"ZoneName": s.ZoneName}
+	for k, v := range changes {
+		if str, ok := v.(string); ok {
+			sanitized, err := SanitizeInputString("SOA", str)
+			if err != nil {
+				return err
+			}
+			v = sanitized
+		}
+		params[k] = v
+	}
+
+	psOpts := CreatePSCommandOpts{
+		Username: conf.Settings.SshUsername,
+		Password: conf.Settings.SshPassword,
+		Server:   conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSScript("Set-DnsServerResourceRecord", setSOAScript, params, psOpts)
+	if err != nil {
+		return err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("ssh execution failure while updating SOA record: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return newPSCommandError("Set-DnsServerResourceRecord", result)
+	}
+	return nil
+}
+
+func unmarshallSOA(ctx context.Context, input []byte) (*SOA, error) {
+	var records []soaRecord
+
+	doc, err := jsonDocument(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(doc, &records)
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Failed to unmarshall a SOA json document with error %q, document was %s", err, string(input)))
+		return nil, fmt.Errorf("failed while unmarshalling SOA json document: %s", err)
+	}
+
+	if len(records) != 1 {
+		return nil, fmt.Errorf("expected a single SOA record, got %d", len(records))
+	}
+
+	data := records[0].RecordData
+	return &SOA{
+		PrimaryServer:     data.PrimaryServer,
+		ResponsiblePerson: data.ResponsiblePerson,
+		SerialNumber:      data.SerialNumber,
+		RefreshInterval:   data.RefreshInterval.TotalSeconds,
+		RetryDelay:        data.RetryDelay.TotalSeconds,
+		ExpireLimit:       data.ExpireLimit.TotalSeconds,
+		MinimumTimeToLive: data.MinimumTimeToLive.TotalSeconds,
+	}, nil
+}
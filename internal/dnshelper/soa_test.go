@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshallSOA(t *testing.T) {
+	input := `[
+  {
+    "DistinguishedName": "DC=@,DC=example.com,cn=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com",
+    "HostName": "@",
+    "RecordType": "SOA",
+    "RecordData": {
+      "ExpireLimit": { "Ticks": 864000000000, "TotalSeconds": 86400 },
+      "MinimumTimeToLive": { "Ticks": 36000000000, "TotalSeconds": 3600 },
+      "PrimaryServer": "dc01.example.com.",
+      "RefreshInterval": { "Ticks": 9000000000, "TotalSeconds": 900 },
+      "ResponsiblePerson": "hostmaster.example.com.",
+      "RetryDelay": { "Ticks": 6000000000, "TotalSeconds": 600 },
+      "SerialNumber": 2024010101
+    },
+    "TimeToLive": { "Ticks": 36000000000, "TotalSeconds": 3600 }
+  }
+]`
+
+	got, err := unmarshallSOA(context.Background(), []byte(input))
+	if err != nil {
+		t.Fatalf("unmarshallSOA() error = %v", err)
+	}
+
+	want := &SOA{
+		PrimaryServer:     "dc01.example.com.",
+		ResponsiblePerson: "hostmaster.example.com.",
+		SerialNumber:      2024010101,
+		RefreshInterval:   900,
+		RetryDelay:        600,
+		ExpireLimit:       86400,
+		MinimumTimeToLive: 3600,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unmarshallSOA() = %+v, want %+v", got, want)
+	}
+
+	if _, err := unmarshallSOA(context.Background(), []byte("[]")); err == nil {
+		t.Errorf("unmarshallSOA() of no records did not return an error")
+	}
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// The servers a primary zone may be transferred to, as named by Set-DnsServerPrimaryZone -SecureSecondaries.
+const (
+	TransferAnyServer        = "TransferAnyServer"
+	TransferToZoneNameServer = "TransferToZoneNameServer"
+	TransferToSecureServers  = "TransferToSecureServers"
+	NoTransfer               = "NoTransfer"
+)
+
+// The servers notified of changes to a primary zone, as named by Set-DnsServerPrimaryZone -Notify.
+const (
+	NoNotify      = "NoNotify"
+	Notify        = "Notify"
+	NotifyServers = "NotifyServers"
+)
+
+// ZoneTransfer holds the zone transfer and notify settings of a primary zone.
+type ZoneTransfer struct {
+	ZoneName string
+	// SecureSecondaries selects the servers the zone may be transferred to. SecondaryServers lists them for
+	// TransferToSecureServers.
+	SecureSecondaries string
+	SecondaryServers  []string
+	// Notify selects the servers notified of changes to the zone. NotifyServers lists them for NotifyServers.
+	Notify        string
+	NotifyServers []string
+}
+
+// GetZoneTransfer returns the zone transfer and notify settings of the primary zone zoneName.
+func GetZoneTransfer(ctx context.Context, conf *config.ProviderConf, zoneName string) (*ZoneTransfer, error) {
+	zone, err := GetDNSZone(ctx, conf, zoneName)
+	if err != nil {
+		return nil, err
+	}
+	if zone.ZoneType != "Primary" {
+		return nil, fmt.Errorf("zone %q is a %s zone, only the transfers of primary zones can be managed", zoneName, zone.ZoneType)
+	}
+
+	return &ZoneTransfer{
+		ZoneName:          zoneName,
+		SecureSecondaries: zone.SecureSecondaries,
+		SecondaryServers:  ipAddressStrings(zone.SecondaryServers),
+		Notify:            zone.Notify,
+		NotifyServers:     ipAddressStrings(zone.NotifyServers),
+	}, nil
+}
+
+// Update changes the zone transfer and notify settings of the zone. The keys of changes are the
+// Set-DnsServerPrimaryZone parameters to change: SecureSecondaries, SecondaryServers, Notify and NotifyServers.
+func (t *ZoneTransfer) Update(ctx context.Context, conf *config.ProviderConf, changes map[string]any) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	params := map[string]any{"Name": t.ZoneName}
+	for k, v := range changes {
+		params[k] = v
+	}
+	return runZoneCommand(ctx, conf, "Set-DnsServerPrimaryZone", params)
+}
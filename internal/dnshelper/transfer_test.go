@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+func TestGetZoneTransfer(t *testing.T) {
+	conf, _ := newFakeConf(&config.CommandOutput{Stdout: `[
+  {
+    "ZoneName": "example.com",
+    "ZoneType": "Primary",
+    "SecureSecondaries": "TransferToSecureServers",
+    "SecondaryServers": [
+      { "AddressFamily": 2, "IPAddressToString": "203.0.113.21" },
+      { "AddressFamily": 23, "IPAddressToString": "2001:db8::21" }
+    ],
+    "Notify": "NotifyServers",
+    "NotifyServers": [ { "AddressFamily": 2, "IPAddressToString": "203.0.113.21" } ]
+  }
+]`})
+
+	got, err := GetZoneTransfer(context.Background(), conf, "example.com")
+	if err != nil {
+		t.Fatalf("GetZoneTransfer() error = %s", err)
+	}
+	want := &ZoneTransfer{
+		ZoneName:          "example.com",
+		SecureSecondaries: TransferToSecureServers,
+		SecondaryServers:  []string{"203.0.113.21", "2001:db8::21"},
+		Notify:            NotifyServers,
+		NotifyServers:     []string{"203.0.113.21"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetZoneTransfer() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetZoneTransfer_SecondaryZone(t *testing.T) {
+	conf, _ := newFakeConf(&config.CommandOutput{Stdout: `[ { "ZoneName": "partner.example", "ZoneType": "Secondary" } ]`})
+
+	if _, err := GetZoneTransfer(context.Background(), conf, "partner.example"); err == nil {
+		t.Errorf("GetZoneTransfer() of a secondary zone did not fail")
+	}
+}
+
+func TestZoneTransferUpdate(t *testing.T) {
+	conf, executor := newFakeConf()
+	transfer := ZoneTransfer{ZoneName: "example.com"}
+
+	changes := map[string]any{"SecureSecondaries": TransferToSecureServers, "SecondaryServers": []string{"203.0.113.21"}}
+	if err := transfer.Update(context.Background(), conf, changes); err != nil {
+		t.Fatalf("Update() error = %s", err)
+	}
+	want := map[string]any{
+		"Name":              "example.com",
+		"SecureSecondaries": TransferToSecureServers,
+		"SecondaryServers":  []any{"203.0.113.21"},
+		"ComputerName":      "dc01.example.com",
+	}
+	if params := executor.params(t, 0); !reflect.DeepEqual(params, want) {
+		t.Errorf("params = %v, want %v", params, want)
+	}
+}
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxTXTStringLength is the length in bytes of the longest character-string of a TXT record.
+const maxTXTStringLength = 255
+
+// txtStringSeparator separates the character-strings of a TXT record in the DescriptiveText of the DnsServer module,
+// as in the DNS console, where each line of the text is a character-string.
+const txtStringSeparator = "\n"
+
+// SanitizeTXTRecordData validates the data of a TXT record, written as in the records attribute. Unless explicit, the
+// data is a single logical string of any length, which is split into character-strings when added. Otherwise the data
+// lists the character-strings of the record, each in double quotes, e.g. `"v=DKIM1; k=rsa; " "p=MIIBIjANBg..."`.
+func SanitizeTXTRecordData(input string, explicit bool) (string, error) {
+	_, err := txtDescriptiveText(input, explicit)
+	if err != nil {
+		return "", err
+	}
+	return input, nil
+}
+
+// txtDescriptiveText returns the DescriptiveText of the TXT record data recordData.
+func txtDescriptiveText(recordData string, explicit bool) (string, error) {
+	var values []string
+	if explicit {
+		var err error
+		values, err = ParseTXTStrings(recordData)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		values = splitTXTString(recordData)
+	}
+
+	for _, v := range values {
+		if len(v) > maxTXTStringLength {
+			return "", fmt.Errorf("TXT record strings can only be %d characters long, got %q", maxTXTStringLength, v)
+		}
+		if strings.ContainsAny(v, "\r\n") {
+			return "", fmt.Errorf("TXT record data cannot contain line breaks, got %q", recordData)
+		}
+	}
+	return strings.Join(values, txtStringSeparator), nil
+}
+
+// TXTStrings returns the character-strings of a TXT record from its DescriptiveText, as returned by the server.
+func TXTStrings(descriptiveText string) []string {
+	values := strings.Split(descriptiveText, txtStringSeparator)
+	for i, v := range values {
+		values[i] = strings.TrimSuffix(v, "\r")
+	}
+	return values
+}
+
+// TXTRecordValue returns the DescriptiveText of a TXT record in the form of the records attribute: the logical string
+// joining its character-strings or, when explicit, the quoted character-strings.
+func TXTRecordValue(descriptiveText string, explicit bool) string {
+	if explicit {
+		return FormatTXTStrings(TXTStrings(descriptiveText))
+	}
+	return strings.Join(TXTStrings(descriptiveText), "")
+}
+
+// RecordValues returns records read from the server in the form of the records attribute. Only the data of TXT
+// records is written differently, see TXTRecordValue.
+func RecordValues(recordType string, records []string, explicitTXTSegments bool) []string {
+	if !strings.EqualFold(recordType, RecordTypeTXT) {
+		return records
+	}
+	values := make([]string, 0, len(records))
+	for _, v := range records {
+		values = append(values, TXTRecordValue(v, explicitTXTSegments))
+	}
+	return values
+}
+
+// serverRecordData returns recordData in the form the DnsServer module takes it in, which differs from the records
+// attribute only for TXT, CERT and DHCID records.
+func (r *Record) serverRecordData(recordData string) (string, error) {
+	if r.RecordType == RecordTypeCERT {
+		return certRecordData(recordData)
+	}
+	if r.RecordType == RecordTypeDHCID {
+		return dhcidRecordData(recordData)
+	}
+	if !strings.EqualFold(r.RecordType, RecordTypeTXT) {
+		return recordData, nil
+	}
+	return txtDescriptiveText(recordData, r.ExplicitTXTSegments)
+}
+
+// splitTXTString splits s into character-strings of at most maxTXTStringLength bytes, without splitting characters.
+func splitTXTString(s string) []string {
+	var values []string
+	for len(s) > maxTXTStringLength {
+		end := maxTXTStringLength
+		for end > 0 && !utf8.RuneStart(s[end]) {
+			end--
+		}
+		values = append(values, s[:end])
+		s = s[end:]
+	}
+	return append(values, s)
+}
+
+// ParseTXTStrings parses character-strings written in double quotes and separated by whitespace, as in a zone file.
+// Double quotes and backslashes within a string are escaped with a backslash.
+func ParseTXTStrings(s string) ([]string, error) {
+	var values []string
+	rest := strings.TrimSpace(s)
+	for rest != "" {
+		if rest[0] != '"' {
+			return nil, fmt.Errorf("expected TXT record strings in double quotes, got %q", s)
+		}
+
+		var b strings.Builder
+		i, closed := 1, false
+		for i < len(rest) {
+			c := rest[i]
+			i++
+			if c == '"' {
+				closed = true
+				break
+			}
+			if c == '\\' && i < len(rest) {
+				c = rest[i]
+				i++
+			}
+			b.WriteByte(c)
+		}
+		if !closed {
+			return nil, fmt.Errorf("unterminated TXT record string in %q", s)
+		}
+		values = append(values, b.String())
+
+		rest = rest[i:]
+		trimmed := strings.TrimLeft(rest, " \t")
+		if trimmed != "" && len(trimmed) == len(rest) {
+			return nil, fmt.Errorf("expected TXT record strings to be separated by whitespace, got %q", s)
+		}
+		rest = trimmed
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("expected at least one TXT record string, got %q", s)
+	}
+	return values, nil
+}
+
+// FormatTXTStrings writes character-strings in double quotes, separated by a space, as parsed by ParseTXTStrings.
+func FormatTXTStrings(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.ReplaceAll(v, `\`, `\\`)
+		quoted = append(quoted, `"`+strings.ReplaceAll(v, `"`, `\"`)+`"`)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// NormalizeTXTStrings rewrites quoted character-strings in the form returned by FormatTXTStrings, so the spacing and
+// escaping of the strings is not a difference. Data that cannot be parsed is returned unchanged.
+func NormalizeTXTStrings(s string) string {
+	values, err := ParseTXTStrings(s)
+	if err != nil {
+		return s
+	}
+	return FormatTXTStrings(values)
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTXTDescriptiveText(t *testing.T) {
+	long := strings.Repeat("0123456789", 30)
+	tests := []struct {
+		name       string
+		recordData string
+		explicit   bool
+		want       string
+		wantErr    bool
+	}{
+		{"test-short", "v=spf1 -all", false, "v=spf1 -all", false},
+		{"test-long", long, false, long[:255] + "\n" + long[255:], false},
+		{"test-long-multibyte", strings.Repeat("a", 254) + "æøå", false, strings.Repeat("a", 254) + "\næøå", false},
+		{"test-quotes-kept", `"v=spf1 -all"`, false, `"v=spf1 -all"`, false},
+		{"test-line-break", "v=spf1\n-all", false, "", true},
+		{"test-explicit", `"v=DKIM1; k=rsa; " "p=MIGf"`, true, "v=DKIM1; k=rsa; \np=MIGf", false},
+		{"test-explicit-escaped", `"say \"hi\"" "C:\\"`, true, "say \"hi\"\nC:\\", false},
+		{"test-explicit-too-long", `"` + long + `"`, true, "", true},
+		{"test-explicit-unquoted", "v=DKIM1", true, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := txtDescriptiveText(tt.recordData, tt.explicit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("txtDescriptiveText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("txtDescriptiveText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTXTStrings(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{"test-single", `"v=spf1 -all"`, []string{"v=spf1 -all"}, false},
+		{"test-multiple", ` "a"  "b"	"c" `, []string{"a", "b", "c"}, false},
+		{"test-empty-string", `""`, []string{""}, false},
+		{"test-escaped", `"a \"b\" \\c"`, []string{`a "b" \c`}, false},
+		{"test-empty", "", nil, true},
+		{"test-unquoted", `"a" b`, nil, true},
+		{"test-unterminated", `"a" "b`, nil, true},
+		{"test-not-separated", `"a""b"`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTXTStrings(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTXTStrings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTXTStrings() = %q, want %q", got, tt.want)
+			}
+			if err == nil && FormatTXTStrings(got) != NormalizeTXTStrings(tt.input) {
+				t.Errorf("FormatTXTStrings() = %q, want %q", FormatTXTStrings(got), NormalizeTXTStrings(tt.input))
+			}
+		})
+	}
+}
+
+func TestRecordValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordType string
+		records    []string
+		explicit   bool
+		want       []string
+	}{
+		{"test-txt", RecordTypeTXT, []string{"v=DKIM1; \r\np=MIGf", "v=spf1 -all"}, false, []string{"v=DKIM1; p=MIGf", "v=spf1 -all"}},
+		{"test-txt-explicit", RecordTypeTXT, []string{"v=DKIM1; \np=MIGf", `say "hi"`}, true, []string{`"v=DKIM1; " "p=MIGf"`, `"say \"hi\""`}},
+		{"test-other-type", RecordTypeA, []string{"203.0.113.11"}, true, []string{"203.0.113.11"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RecordValues(tt.recordType, tt.records, tt.explicit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RecordValues() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// Zone holds the fields we use from the objects returned by Get-DnsServerZone.
+type Zone struct {
+	ZoneName            string `json:"ZoneName"`
+	ZoneType            string `json:"ZoneType"`
+	IsReverseLookupZone bool   `json:"IsReverseLookupZone"`
+	IsDsIntegrated      bool   `json:"IsDsIntegrated"`
+	ReplicationScope    string `json:"ReplicationScope"`
+	IsSigned            bool   `json:"IsSigned"`
+	// ZoneFile and MasterServers are only set for file-backed and secondary zones respectively.
+	ZoneFile      string      `json:"ZoneFile"`
+	MasterServers []IPAddress `json:"MasterServers"`
+	// The zone transfer and notify settings are only set for primary zones.
+	SecureSecondaries string      `json:"SecureSecondaries"`
+	SecondaryServers  []IPAddress `json:"SecondaryServers"`
+	Notify            string      `json:"Notify"`
+	NotifyServers     []IPAddress `json:"NotifyServers"`
+}
+
+// IPAddress holds the field we use from the IPAddress objects returned by the DnsServer module.
+type IPAddress struct {
+	IPAddressToString string `json:"IPAddressToString"`
+}
+
+// MasterServerAddresses returns the addresses of the master servers of a secondary zone.
+func (z *Zone) MasterServerAddresses() []string {
+	return ipAddressStrings(z.MasterServers)
+}
+
+func ipAddressStrings(ips []IPAddress) []string {
+	addresses := make([]string, 0, len(ips))
+	for _, v := range ips {
+		addresses = append(addresses, v.IPAddressToString)
+	}
+	return addresses
+}
+
+// GetDNSZones returns all zones hosted on the DNS server.
+func GetDNSZones(ctx context.Context, conf *config.ProviderConf) ([]Zone, error) {
+	psOpts := CreatePSCommandOpts{
+		JSONOutput: true,
+		JSONDepth:  2,
+		ForceArray: true,
+		Username:   conf.Settings.SshUsername,
+		Password:   conf.Settings.SshPassword,
+		Server:     conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSCommand("Get-DnsServerZone", nil, psOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return nil, fmt.Errorf("ssh execution failure in GetDNSZones: %s", err)
+	}
+
+	if result.ExitCode != 0 {
+		return nil, newPSCommandError("Get-DnsServerZone", result)
+	}
+
+	return unmarshallZones(ctx, []byte(result.Stdout))
+}
+
+// GetDNSZone returns the zone zoneName.
+func GetDNSZone(ctx context.Context, conf *config.ProviderConf, zoneName string) (*Zone, error) {
+	psOpts := CreatePSCommandOpts{
+		JSONOutput: true,
+		JSONDepth:  3,
+		ForceArray: true,
+		Username:   conf.Settings.SshUsername,
+		Password:   conf.Settings.SshPassword,
+		Server:     conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSCommand("Get-DnsServerZone", map[string]any{"Name": zoneName}, psOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return nil, fmt.Errorf("ssh execution failure in GetDNSZone: %s", err)
+	}
+
+	if result.ExitCode != 0 {
+		return nil, newPSCommandError("Get-DnsServerZone", result)
+	}
+
+	zones, err := unmarshallZones(ctx, []byte(result.Stdout))
+	if err != nil {
+		return nil, err
+	}
+	if len(zones) != 1 {
+		return nil, fmt.Errorf("expected a single zone named %q, got %d", zoneName, len(zones))
+	}
+	return &zones[0], nil
+}
+
+// AddPrimaryZone creates the primary zone zoneName. The zone is stored in Active Directory and replicated to
+// replicationScope when set, and in zoneFile on the DNS server otherwise.
+func AddPrimaryZone(ctx context.Context, conf *config.ProviderConf, zoneName, replicationScope, zoneFile string) error {
+	params := map[string]any{
+		"Name": zoneName,
+	}
+	if replicationScope != "" {
+		params["ReplicationScope"] = replicationScope
+	} else {
+		params["ZoneFile"] = zoneFile
+	}
+	return runZoneListCommand(ctx, conf, "Add-DnsServerPrimaryZone", params)
+}
+
+// AddSecondaryZone creates the secondary zone zoneName, transferred from masterServers and stored in zoneFile.
+func AddSecondaryZone(ctx context.Context, conf *config.ProviderConf, zoneName, zoneFile string, masterServers []string) error {
+	params := map[string]any{
+		"Name":          zoneName,
+		"ZoneFile":      zoneFile,
+		"MasterServers": masterServers,
+	}
+	return runZoneListCommand(ctx, conf, "Add-DnsServerSecondaryZone", params)
+}
+
+// SetSecondaryZoneMasterServers replaces the master servers of the secondary zone zoneName.
+func SetSecondaryZoneMasterServers(ctx context.Context, conf *config.ProviderConf, zoneName string, masterServers []string) error {
+	params := map[string]any{
+		"Name":          zoneName,
+		"MasterServers": masterServers,
+	}
+	return runZoneCommand(ctx, conf, "Set-DnsServerSecondaryZone", params)
+}
+
+// RemoveDNSZone removes the zone zoneName and all its records from the DNS server.
+func RemoveDNSZone(ctx context.Context, conf *config.ProviderConf, zoneName string) error {
+	params := map[string]any{
+		"Name":  zoneName,
+		"Force": true,
+	}
+	return runZoneListCommand(ctx, conf, "Remove-DnsServerZone", params)
+}
+
+// ZoneExists reports whether the DNS server hosts zoneName. The zones are only listed once per provider instance.
+func ZoneExists(ctx context.Context, conf *config.ProviderConf, zoneName string) (bool, error) {
+	names, err := zoneNames(ctx, conf)
+	if err != nil {
+		return false, err
+	}
+	return names[strings.ToLower(strings.TrimSuffix(zoneName, "."))], nil
+}
+
+// zoneNames returns the lower cased names of the zones on the DNS server, listing them once per provider instance.
+func zoneNames(ctx context.Context, conf *config.ProviderConf) (map[string]bool, error) {
+	return conf.ZoneNames(func() ([]string, error) {
+		zones, err := GetDNSZones(ctx, conf)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(zones))
+		for _, z := range zones {
+			names = append(names, z.ZoneName)
+		}
+		return names, nil
+	})
+}
+
+func unmarshallZones(ctx context.Context, input []byte) ([]Zone, error) {
+	var zones []Zone
+
+	doc, err := jsonDocument(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(doc, &zones)
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Failed to unmarshall a Zone json document with error %q, document was %s", err, string(input)))
+		return nil, fmt.Errorf("failed while unmarshalling Zone json document: %s", err)
+	}
+	return zones, nil
+}
+
+// runZoneListCommand runs a cmdlet adding or removing a zone, and drops the zone names cached for the DNS server, as
+// they no longer match the zones on the server.
+func runZoneListCommand(ctx context.Context, conf *config.ProviderConf, cmdlet string, params map[string]any) error {
+	err := runZoneCommand(ctx, conf, cmdlet, params)
+	conf.ForgetZoneNames()
+	return err
+}
+
+func runZoneCommand(ctx context.Context, conf *config.ProviderConf, cmdlet string, params map[string]any) error {
+	psOpts := CreatePSCommandOpts{
+		Username: conf.Settings.SshUsername,
+		Password: conf.Settings.SshPassword,
+		Server:   conf.Settings.DnsServer,
+	}
+	psCmd, err := NewPSCommand(cmdlet, params, psOpts)
+	if err != nil {
+		return err
+	}
+
+	result, err := psCmd.Run(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("ssh execution failure in %s: %s", cmdlet, err)
+	}
+	if result.ExitCode != 0 {
+		return newPSCommandError(cmdlet, result)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+const (
+	recordTypeMX  = "MX"
+	recordTypeSRV = "SRV"
+)
+
+// exportRecordFields lists the record data fields of the record types that are exported, but not managed as records,
+// in the order they are written in a zone file. The SOA record is written from GetSOA, as its intervals are time spans
+// nested too deep in the record data to be read from the records of the zone.
+var exportRecordFields = map[string][]recordField{
+	recordTypeMX:  {{Name: "Preference"}, {Name: "MailExchange"}},
+	recordTypeSRV: {{Name: "Priority"}, {Name: "Weight"}, {Name: "Port"}, {Name: "DomainName"}},
+}
+
+// quotedRecordTypes are the record types whose record data fields are character-strings, which are written in double
+// quotes in a zone file.
+var quotedRecordTypes = map[string]bool{
+	RecordTypeX25:  true,
+	RecordTypeISDN: true,
+}
+
+// ExportZone returns the records of zoneName as a zone file in the master file format of RFC 1035. All records are read
+// with a single command, and the SOA parameters with another. Records whose data cannot be written, e.g. DNSSEC
+// signatures, are written as comments.
+func ExportZone(ctx context.Context, conf *config.ProviderConf, zoneName string) (string, error) {
+	records, err := getZoneRecords(ctx, conf, zoneName, "ExportZone")
+	if err != nil {
+		return "", err
+	}
+	soa, err := GetSOA(ctx, conf, zoneName)
+	if err != nil {
+		return "", err
+	}
+	return formatZoneFile(zoneName, soa, records), nil
+}
+
+// formatZoneFile writes records as a zone file for zoneName, with the SOA record first and the other records in the
+// order the server returned them. Names are written relative to the zone, and every record has its own TTL.
+func formatZoneFile(zoneName string, soa *SOA, records []DNSRecord) string {
+	records = append([]DNSRecord(nil), records...)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].recordType() == RecordTypeSOA && records[j].recordType() != RecordTypeSOA
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ORIGIN %s.\n", strings.TrimSuffix(zoneName, "."))
+	for _, r := range records {
+		recordType := r.recordType()
+		recordData, ok := zoneFileRecordData(recordType, r.RecordData.CimInstanceProperties)
+		if recordType == RecordTypeSOA {
+			recordData = fmt.Sprintf("%s %s %d %d %d %d %d", soa.PrimaryServer, soa.ResponsiblePerson, soa.SerialNumber,
+				soa.RefreshInterval, soa.RetryDelay, soa.ExpireLimit, soa.MinimumTimeToLive)
+			ok = true
+		}
+		line := fmt.Sprintf("%s\t%d\tIN\t%s\t%s", r.HostName, r.TimeToLive.TotalSeconds, recordType, recordData)
+		if !ok {
+			line = fmt.Sprintf("; %s\t%d\tIN\t%s\t(record data not exported)", r.HostName, r.TimeToLive.TotalSeconds, recordType)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// zoneFileRecordData writes the record data properties returned by the server as in a zone file. It returns false for
+// record types whose data cannot be written.
+func zoneFileRecordData(recordType string, properties []CimInstanceProperties) (string, bool) {
+	if recordType == RecordTypeTXT {
+		if len(properties) == 0 {
+			return "", false
+		}
+		return FormatTXTStrings(TXTStrings(formatCimValue(properties[0].Value))), true
+	}
+
+	fields, ok := exportRecordFields[recordType]
+	if !ok {
+		if _, ok := recordTypeFields[recordType]; !ok {
+			return "", false
+		}
+		recordData := formatRecordData(recordType, properties)
+		if quotedRecordTypes[recordType] {
+			recordData = FormatTXTStrings(strings.Fields(recordData))
+		}
+		return recordData, recordData != ""
+	}
+
+	values := make([]string, 0, len(fields))
+	for _, f := range fields {
+		for _, p := range properties {
+			if strings.EqualFold(p.Name, f.Name) {
+				values = append(values, formatCimValue(p.Value))
+				break
+			}
+		}
+	}
+	if len(values) != len(fields) {
+		return "", false
+	}
+	return strings.Join(values, " "), true
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+func TestExportZone(t *testing.T) {
+	conf, executor := newFakeConf(
+		&config.CommandOutput{Stdout: `[
+  {
+    "HostName": "@",
+    "RecordType": "NS",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "NameServer", "value": "dc01.example.com." } ] },
+    "TimeToLive": { "TotalSeconds": 3600 }
+  },
+  {
+    "HostName": "@",
+    "RecordType": "SOA",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "PrimaryServer", "value": "dc01.example.com." } ] },
+    "TimeToLive": { "TotalSeconds": 3600 }
+  },
+  {
+    "HostName": "@",
+    "RecordType": "MX",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "MailExchange", "value": "mail.example.com." }, { "Name": "Preference", "value": 10 } ] },
+    "TimeToLive": { "TotalSeconds": 3600 }
+  },
+  {
+    "HostName": "_sip._tcp",
+    "RecordType": "SRV",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "DomainName", "value": "sip.example.com." }, { "Name": "Port", "value": 5060 }, { "Name": "Priority", "value": 0 }, { "Name": "Weight", "value": 5 } ] },
+    "TimeToLive": { "TotalSeconds": 600 }
+  },
+  {
+    "HostName": "www",
+    "RecordType": "A",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "IPv4Address", "value": "203.0.113.11" } ] },
+    "TimeToLive": { "TotalSeconds": 300 }
+  },
+  {
+    "HostName": "www",
+    "RecordType": "TXT",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "DescriptiveText", "value": "say \"hi\"\nsecond" } ] },
+    "TimeToLive": { "TotalSeconds": 300 }
+  },
+  {
+    "HostName": "relay",
+    "RecordType": "X25",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "PsdnAddress", "value": "311061700956" } ] },
+    "TimeToLive": { "TotalSeconds": 3600 }
+  },
+  {
+    "HostName": "www",
+    "RecordType": "RRSIG",
+    "RecordData": { "CimInstanceProperties": [ { "Name": "Signature", "value": "AAAA" } ] },
+    "TimeToLive": { "TotalSeconds": 300 }
+  }
+]`},
+		&config.CommandOutput{Stdout: `[
+  {
+    "HostName": "@",
+    "RecordType": "SOA",
+    "RecordData": {
+      "ExpireLimit": { "TotalSeconds": 86400 },
+      "MinimumTimeToLive": { "TotalSeconds": 3600 },
+      "PrimaryServer": "dc01.example.com.",
+      "RefreshInterval": { "TotalSeconds": 900 },
+      "ResponsiblePerson": "hostmaster.example.com.",
+      "RetryDelay": { "TotalSeconds": 600 },
+      "SerialNumber": 2024010101
+    }
+  }
+]`},
+	)
+
+	got, err := ExportZone(context.Background(), conf, "example.com")
+	if err != nil {
+		t.Fatalf("ExportZone() error = %s", err)
+	}
+	want := "$ORIGIN example.com.\n" +
+		"@\t3600\tIN\tSOA\tdc01.example.com. hostmaster.example.com. 2024010101 900 600 86400 3600\n" +
+		"@\t3600\tIN\tNS\tdc01.example.com.\n" +
+		"@\t3600\tIN\tMX\t10 mail.example.com.\n" +
+		"_sip._tcp\t600\tIN\tSRV\t0 5 5060 sip.example.com.\n" +
+		"www\t300\tIN\tA\t203.0.113.11\n" +
+		"www\t300\tIN\tTXT\t\"say \\\"hi\\\"\" \"second\"\n" +
+		"relay\t3600\tIN\tX25\t\"311061700956\"\n" +
+		"; www\t300\tIN\tRRSIG\t(record data not exported)\n"
+	if got != want {
+		t.Errorf("ExportZone() =\n%s\nwant\n%s", got, want)
+	}
+
+	wantParams := map[string]any{"ZoneName": "example.com", "ComputerName": "dc01.example.com"}
+	if params := executor.params(t, 0); !reflect.DeepEqual(params, wantParams) {
+		t.Errorf("params = %v, want %v", params, wantParams)
+	}
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: MIT
+
+package dnshelper
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+func TestUnmarshallZones_SecondaryZone(t *testing.T) {
+	input := `[
+  {
+    "ZoneName": "partner.example",
+    "ZoneType": "Secondary",
+    "IsReverseLookupZone": false,
+    "IsDsIntegrated": false,
+    "ZoneFile": "partner.example.dns",
+    "MasterServers": [
+      { "Address": 191037643, "AddressFamily": 2, "IPAddressToString": "203.0.113.11" },
+      { "AddressFamily": 23, "IPAddressToString": "2001:db8::11" }
+    ]
+  }
+]`
+
+	zones, err := unmarshallZones(context.Background(), []byte(input))
+	if err != nil {
+		t.Fatalf("unmarshallZones() error = %v", err)
+	}
+	if len(zones) != 1 {
+		t.Fatalf("unmarshallZones() returned %d zones, want 1", len(zones))
+	}
+
+	if zones[0].ZoneFile != "partner.example.dns" {
+		t.Errorf("ZoneFile = %q, want %q", zones[0].ZoneFile, "partner.example.dns")
+	}
+	want := []string{"203.0.113.11", "2001:db8::11"}
+	if got := zones[0].MasterServerAddresses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("MasterServerAddresses() = %v, want %v", got, want)
+	}
+}
+
+func TestAddPrimaryZone(t *testing.T) {
+	tests := []struct {
+		name             string
+		replicationScope string
+		zoneFile         string
+		wantParams       map[string]any
+	}{
+		{
+			"test-file-backed", "", "example.com.dns",
+			map[string]any{"Name": "example.com", "ZoneFile": "example.com.dns", "ComputerName": "dc01.example.com"},
+		},
+		{
+			"test-ad-integrated", "Domain", "",
+			map[string]any{"Name": "example.com", "ReplicationScope": "Domain", "ComputerName": "dc01.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf, executor := newFakeConf()
+			if err := AddPrimaryZone(context.Background(), conf, "example.com", tt.replicationScope, tt.zoneFile); err != nil {
+				t.Fatalf("AddPrimaryZone() error = %v", err)
+			}
+			if !strings.Contains(executor.scripts[0], "Add-DnsServerPrimaryZone") {
+				t.Errorf("command does not run Add-DnsServerPrimaryZone: %s", executor.scripts[0])
+			}
+			if params := executor.params(t, 0); !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("params = %v, want %v", params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestZoneExists_AfterZoneChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(ctx context.Context, conf *config.ProviderConf) error
+		before string
+		after  string
+		want   bool
+	}{
+		{
+			"test-add-primary-zone",
+			func(ctx context.Context, conf *config.ProviderConf) error {
+				return AddPrimaryZone(ctx, conf, "new.example.com", "", "new.example.com.dns")
+			},
+			`[]`, `[{"ZoneName":"new.example.com"}]`, true,
+		},
+		{
+			"test-add-secondary-zone",
+			func(ctx context.Context, conf *config.ProviderConf) error {
+				return AddSecondaryZone(ctx, conf, "new.example.com", "new.example.com.dns", []string{"203.0.113.11"})
+			},
+			`[]`, `[{"ZoneName":"new.example.com"}]`, true,
+		},
+		{
+			"test-remove-zone",
+			func(ctx context.Context, conf *config.ProviderConf) error {
+				return RemoveDNSZone(ctx, conf, "new.example.com")
+			},
+			`[{"ZoneName":"new.example.com"}]`, `[]`, false,
+		},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf, executor := newFakeConf(
+				&config.CommandOutput{Stdout: tt.before},
+				&config.CommandOutput{},
+				&config.CommandOutput{Stdout: tt.after},
+			)
+			if exists, err := ZoneExists(ctx, conf, "new.example.com"); err != nil || exists == tt.want {
+				t.Fatalf("ZoneExists() before the change = %v, %v, want %v", exists, err, !tt.want)
+			}
+			if err := tt.change(ctx, conf); err != nil {
+				t.Fatalf("change error = %v", err)
+			}
+			exists, err := ZoneExists(ctx, conf, "new.example.com")
+			if err != nil || exists != tt.want {
+				t.Errorf("ZoneExists() after the change = %v, %v, want %v", exists, err, tt.want)
+			}
+			if len(executor.scripts) != 3 {
+				t.Errorf("ran %d commands, want the zones listed again after the change", len(executor.scripts))
+			}
+		})
+	}
+}
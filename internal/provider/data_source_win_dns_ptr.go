@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+)
+
+func dataSourceDNSPtr() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windns_ptr` reads the PTR records of an IP address from the reverse zones of a Windows DNS Server.",
+		ReadContext: dataSourceDNSPtrRead,
+		Schema: map[string]*schema.Schema{
+			"ip_address": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsIPAddress,
+				Description:  "The IPv4 or IPv6 address to look up.",
+			},
+			"zone_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The reverse zone to read the PTR records from. Defaults to the most specific reverse zone on the server covering the address, and is empty when there is none.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the PTR records relative to `zone_name`.",
+			},
+			"reverse_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The reverse lookup name of the address, e.g. `11.113.0.203.in-addr.arpa` for `203.0.113.11`.",
+			},
+			"records": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The domain names the PTR records point to. Empty when the address has no PTR records.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceDNSPtrRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	address := d.Get("ip_address").(string)
+	reverseName, err := dnshelper.ReverseName(address)
+	if err != nil {
+		return diag.Errorf("error when mapping input data: %s", err)
+	}
+
+	ptr, err := dnshelper.GetPtrRecord(ctx, meta.(*config.ProviderConf), address, d.Get("zone_name").(string))
+	if err != nil {
+		return diag.Errorf("error while reading PTR records of %s: %s", address, err)
+	}
+
+	_ = d.Set("zone_name", ptr.ZoneName)
+	_ = d.Set("name", ptr.HostName)
+	_ = d.Set("reverse_name", reverseName)
+	_ = d.Set("records", ptr.Records)
+	d.SetId(reverseName)
+
+	return nil
+}
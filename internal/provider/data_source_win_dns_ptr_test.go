@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccDataSourceDNSPtrConfig = `
+resource "windns_record" "ptr" {
+  name      = "100.113"
+  zone_name = "10.10.in-addr.arpa"
+  type      = "PTR"
+  records   = ["example-host.example.com."]
+}
+
+data "windns_ptr" "found" {
+  ip_address = "10.10.113.100"
+
+  depends_on = [windns_record.ptr]
+}
+
+data "windns_ptr" "missing" {
+  ip_address = "10.10.113.101"
+}
+
+data "windns_ptr" "uncovered" {
+  ip_address = "198.51.100.11"
+}
+`
+
+func TestAccDataSourceDNSPtr(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceDNSPtrConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.windns_ptr.found", "zone_name", "10.10.in-addr.arpa"),
+					resource.TestCheckResourceAttr("data.windns_ptr.found", "name", "100.113"),
+					resource.TestCheckResourceAttr("data.windns_ptr.found", "reverse_name", "100.113.10.10.in-addr.arpa"),
+					resource.TestCheckResourceAttr("data.windns_ptr.found", "records.#", "1"),
+					resource.TestCheckResourceAttr("data.windns_ptr.found", "records.0", "example-host.example.com."),
+					resource.TestCheckResourceAttr("data.windns_ptr.missing", "zone_name", "10.10.in-addr.arpa"),
+					resource.TestCheckResourceAttr("data.windns_ptr.missing", "records.#", "0"),
+					resource.TestCheckResourceAttr("data.windns_ptr.uncovered", "zone_name", ""),
+					resource.TestCheckResourceAttr("data.windns_ptr.uncovered", "records.#", "0"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+)
+
+func dataSourceDNSServerStatus() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windns_server_status` checks that the provider can reach and manage the Windows DNS Server, and returns its version.",
+		ReadContext: dataSourceDNSServerStatusRead,
+		Schema: map[string]*schema.Schema{
+			"computer_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the DNS server.",
+			},
+			"server_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the DNS server, which is the version of Windows it runs on, e.g. `10.0.20348`.",
+			},
+			"module_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the DnsServer PowerShell module on the host running the commands.",
+			},
+		},
+	}
+}
+
+func dataSourceDNSServerStatusRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.ProviderConf)
+	status, err := dnshelper.GetServerStatus(ctx, conf)
+	if err != nil {
+		server := conf.Settings.DnsServer
+		if server == "" {
+			server = conf.Settings.SshHostname
+		}
+		return diag.Errorf("unable to manage DNS server %q through SSH host %q: %s", server, conf.Settings.SshHostname, err)
+	}
+
+	_ = d.Set("computer_name", status.ComputerName)
+	_ = d.Set("server_version", status.Version())
+	_ = d.Set("module_version", status.ModuleVersion)
+	d.SetId(conf.Settings.SshHostname + dnshelper.IDSeparator + conf.Settings.DnsServer)
+
+	return nil
+}
@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccDataSourceDNSServerStatusConfig = `
+data "windns_server_status" "s" {}
+`
+
+func TestAccDataSourceDNSServerStatus(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceDNSServerStatusConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.windns_server_status.s", "computer_name"),
+					resource.TestCheckResourceAttrSet("data.windns_server_status.s", "server_version"),
+					resource.TestCheckResourceAttrSet("data.windns_server_status.s", "module_version"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+)
+
+func dataSourceDNSZoneExport() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windns_zone_export` reads all records of a zone of a Windows DNS Server as a zone file.",
+		ReadContext: dataSourceDNSZoneExportRead,
+		Schema: map[string]*schema.Schema{
+			"zone_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateDomainName,
+				Description:  "The name of the zone to export.",
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The records of the zone in the master file format of RFC 1035, starting with an `$ORIGIN` line and the SOA record. Names are relative to the zone and every record has its own TTL. Records whose data cannot be exported, e.g. DNSSEC signatures, are written as comments.",
+			},
+		},
+	}
+}
+
+func dataSourceDNSZoneExportRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zoneName := asciiName(d.Get("zone_name").(string))
+	content, err := dnshelper.ExportZone(ctx, meta.(*config.ProviderConf), zoneName)
+	if err != nil {
+		return diag.Errorf("error while exporting zone %q: %s", zoneName, err)
+	}
+
+	_ = d.Set("content", content)
+	d.SetId(zoneName)
+
+	return nil
+}
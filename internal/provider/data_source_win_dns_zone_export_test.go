@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccDataSourceDNSZoneExportConfig = `
+data "windns_zone_export" "z" {
+  zone_name = "example.com"
+}
+`
+
+func TestAccDataSourceDNSZoneExport(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceDNSZoneExportConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.windns_zone_export.z", "id", "example.com"),
+					resource.TestMatchResourceAttr("data.windns_zone_export.z", "content", regexp.MustCompile(`^\$ORIGIN example\.com\.\n@\t\d+\tIN\tSOA\t`)),
+					resource.TestMatchResourceAttr("data.windns_zone_export.z", "content", regexp.MustCompile(`\n@\t\d+\tIN\tNS\t\S+\.\n`)),
+				),
+			},
+		},
+	})
+}
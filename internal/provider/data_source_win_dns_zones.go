@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+)
+
+func dataSourceDNSZones() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windns_zones` lists the zones hosted on a Windows DNS Server.",
+		ReadContext: dataSourceDNSZonesRead,
+		Schema: map[string]*schema.Schema{
+			"zones": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The zones hosted on the DNS server.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the zone.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the zone, e.g. `Primary`, `Secondary`, `Stub` or `Forwarder`.",
+						},
+						"is_reverse": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the zone is a reverse lookup zone.",
+						},
+						"is_ds_integrated": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the zone is stored in Active Directory.",
+						},
+						"is_signed": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the zone is signed with DNSSEC.",
+						},
+						"replication_scope": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The replication scope of AD-integrated zones, e.g. `Domain`, `Forest` or `Custom`.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDNSZonesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.ProviderConf)
+	zones, err := dnshelper.GetDNSZones(ctx, conf)
+	if err != nil {
+		return diag.Errorf("error while reading zones: %s", err)
+	}
+
+	var zoneList []map[string]any
+	for _, z := range zones {
+		zoneList = append(zoneList, map[string]any{
+			"name":              z.ZoneName,
+			"type":              z.ZoneType,
+			"is_reverse":        z.IsReverseLookupZone,
+			"is_ds_integrated":  z.IsDsIntegrated,
+			"is_signed":         z.IsSigned,
+			"replication_scope": z.ReplicationScope,
+		})
+	}
+
+	if err := d.Set("zones", zoneList); err != nil {
+		return diag.Errorf("error while setting zones: %s", err)
+	}
+	d.SetId(conf.Settings.SshHostname + dnshelper.IDSeparator + conf.Settings.DnsServer)
+
+	return nil
+}
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccDataSourceDNSZonesConfig = `
+data "windns_zones" "all" {}
+`
+
+func TestAccDataSourceDNSZones(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceDNSZonesConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.windns_zones.all", "zones.*", map[string]string{
+						"name":       "example.com",
+						"type":       "Primary",
+						"is_reverse": "false",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.windns_zones.all", "zones.*", map[string]string{
+						"name":       "10.10.in-addr.arpa",
+						"is_reverse": "true",
+					}),
+				),
+			},
+		},
+	})
+}
@@ -4,13 +4,22 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// recordNameAffixPattern matches the characters allowed in record names, as name_prefix and name_suffix become part of
+// them.
+var recordNameAffixPattern = regexp.MustCompile(`^[a-zA-Z0-9.\-_]*$`)
+
 // Provider exports the provider schema
 func Provider(version string) func() *schema.Provider {
 	return func() *schema.Provider {
@@ -18,32 +27,192 @@ func Provider(version string) func() *schema.Provider {
 			Schema: map[string]*schema.Schema{
 				"ssh_username": {
 					Type:        schema.TypeString,
-					Required:    true,
+					Optional:    true,
 					DefaultFunc: schema.EnvDefaultFunc("WINDNS_SSH_USERNAME", ""),
-					Description: "The username used to authenticate to the server's SSH service. (Environment variable: WINDNS_SSH_USERNAME)",
+					Description: "The username used to authenticate to the server's SSH service. Must be set here or with the environment variable. (Environment variable: WINDNS_SSH_USERNAME)",
 				},
 				"ssh_password": {
 					Type:        schema.TypeString,
-					Required:    true,
+					Optional:    true,
 					DefaultFunc: schema.EnvDefaultFunc("WINDNS_SSH_PASSWORD", ""),
-					Description: "The password used to authenticate to the server's SSH service. (Environment variable: WINDNS_SSH_PASSWORD)",
+					Description: "The password used to authenticate to the server's SSH service. Must be set here or with the environment variable, unless `ssh_use_agent` is set. (Environment variable: WINDNS_SSH_PASSWORD)",
+				},
+				"ssh_use_agent": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("WINDNS_SSH_USE_AGENT", false),
+					Description: "Authenticate with the keys of the SSH agent listening on the socket in the `SSH_AUTH_SOCK` environment variable, e.g. `ssh-agent`, so no key is given to Terraform. The agent's keys are offered first, then `ssh_password` when set. Defaults to `false`. (Environment variable: WINDNS_SSH_USE_AGENT)",
 				},
 				"ssh_hostname": {
 					Type:        schema.TypeString,
-					Required:    true,
+					Optional:    true,
 					DefaultFunc: schema.EnvDefaultFunc("WINDNS_SSH_HOSTNAME", ""),
-					Description: "The hostname of the server we will use to run powershell scripts over SSH. (Environment variable: WINDNS_SSH_HOSTNAME)",
+					Description: "The hostname of the server we will use to run powershell scripts over SSH. Must be set here or with the environment variable. (Environment variable: WINDNS_SSH_HOSTNAME)",
+				},
+				"ssh_failover_hostnames": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("WINDNS_SSH_FAILOVER_HOSTNAMES", ""),
+					Description: "A comma separated list of hosts to connect to in order when no SSH connection can be established to `ssh_hostname`, e.g. other domain controllers when the DNS data is replicated in Active Directory. They are reached through `ssh_proxy_jump` on `ssh_port` with the same credentials, and their host keys are verified against `ssh_known_hosts_file`. The host connected to is logged. (Environment variable: WINDNS_SSH_FAILOVER_HOSTNAMES)",
+				},
+				"ssh_host_key": {
+					Type:          schema.TypeString,
+					Optional:      true,
+					DefaultFunc:   schema.EnvDefaultFunc("WINDNS_SSH_HOST_KEY", ""),
+					ConflictsWith: []string{"ssh_insecure"},
+					Description:   "The expected host key of `ssh_hostname`, in authorized_keys format (e.g. `ssh-ed25519 AAAA...`). When not set the host key is verified against `ssh_known_hosts_file`. (Environment variable: WINDNS_SSH_HOST_KEY)",
+				},
+				"ssh_known_hosts_file": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("WINDNS_SSH_KNOWN_HOSTS_FILE", ""),
+					Description: "The known_hosts file used to verify the host keys of `ssh_hostname` and any jump hosts. Defaults to `~/.ssh/known_hosts`. (Environment variable: WINDNS_SSH_KNOWN_HOSTS_FILE)",
+				},
+				"ssh_insecure": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Skip verifying the host keys of `ssh_hostname` and any jump hosts. Not recommended, as it allows man-in-the-middle attacks.",
+				},
+				"ssh_port": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("WINDNS_SSH_PORT", 22),
+					ValidateFunc: validation.IsPortNumber,
+					Description:  "The port of the SSH service on `ssh_hostname`. The ports of jump hosts are given in `ssh_proxy_jump`. Defaults to `22`. (Environment variable: WINDNS_SSH_PORT)",
+				},
+				"ssh_proxy_jump": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("WINDNS_SSH_PROXY_JUMP", ""),
+					Description: "A comma separated list of SSH hosts to jump through, in order, to reach `ssh_hostname`, each given as `[user@]host[:port]` like OpenSSH's ProxyJump option. Every hop authenticates the same way, with the keys of the SSH agent when `ssh_use_agent` is set and `ssh_password`, and with `ssh_username` unless a user is given. (Environment variable: WINDNS_SSH_PROXY_JUMP)",
+				},
+				"ssh_connect_timeout": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("WINDNS_SSH_CONNECT_TIMEOUT", 20),
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "The number of seconds to wait for the SSH connection to `ssh_hostname`, and to each jump host, to be established. Defaults to `20`. (Environment variable: WINDNS_SSH_CONNECT_TIMEOUT)",
+				},
+				"ssh_keepalive_interval": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("WINDNS_SSH_KEEPALIVE_INTERVAL", 30),
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "The number of seconds between the keepalive requests sent over each SSH connection, so idle connections are not dropped by firewalls during long applies. `0` disables keepalives. Defaults to `30`. (Environment variable: WINDNS_SSH_KEEPALIVE_INTERVAL)",
+				},
+				"ssh_keepalive_max_missed": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("WINDNS_SSH_KEEPALIVE_MAX_MISSED", 3),
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "The number of keepalive requests in a row that may go unanswered before an SSH connection is considered dead. It is then closed, and replaced by a new connection for the next command. Defaults to `3`. (Environment variable: WINDNS_SSH_KEEPALIVE_MAX_MISSED)",
+				},
+				"default_zone": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("WINDNS_DEFAULT_ZONE", ""),
+					Description: "The zone of `windns_record` resources that do not set `zone_name`. (Environment variable: WINDNS_DEFAULT_ZONE)",
 				},
 				"dns_server": {
 					Type:        schema.TypeString,
 					Optional:    true,
-					DefaultFunc: schema.EnvDefaultFunc("WINDNS_DNS_SERVER_HOSTNAME", ""),
-					Description: "The hostname of the DNS server. (Environment variable: WINDNS_DNS_SERVER_HOSTNAME)",
+					DefaultFunc: schema.MultiEnvDefaultFunc([]string{"WINDNS_DNS_SERVER_HOSTNAME", "WINDNS_DNS_SERVER"}, ""),
+					Description: "The hostname of the DNS server. (Environment variable: WINDNS_DNS_SERVER_HOSTNAME or WINDNS_DNS_SERVER)",
+				},
+				"powershell_path": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("WINDNS_POWERSHELL_PATH", "powershell.exe"),
+					Description: "The PowerShell executable running the commands on `ssh_hostname`, e.g. `pwsh.exe` for PowerShell 7. Defaults to `powershell.exe`. (Environment variable: WINDNS_POWERSHELL_PATH)",
+				},
+				"busy_retries": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("WINDNS_BUSY_RETRIES", 3),
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "The number of times a command is retried when it fails because the DNS server is busy, e.g. when the zone is locked while an administrator edits it in the DNS console. Defaults to `3`. (Environment variable: WINDNS_BUSY_RETRIES)",
+				},
+				"busy_retry_delay": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("WINDNS_BUSY_RETRY_DELAY", 2),
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "The number of seconds to wait before retrying a command that failed because the DNS server is busy. Defaults to `2`. (Environment variable: WINDNS_BUSY_RETRY_DELAY)",
+				},
+				"max_concurrent_operations": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("WINDNS_MAX_CONCURRENT_OPERATIONS", 0),
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "The maximum number of remote commands run at once, regardless of Terraform's `-parallelism`. Commands above the limit wait for others to finish. Defaults to `0`, which means no limit. (Environment variable: WINDNS_MAX_CONCURRENT_OPERATIONS)",
+				},
+				"post_write_delay": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("WINDNS_POST_WRITE_DELAY", 0),
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "The number of seconds to wait after `windns_record` and `windns_records` resources are created or updated, giving AD replication or resolver caches time to pick up the change before checks that run right after the apply. Every resource written waits, in parallel up to Terraform's `-parallelism`. Defaults to `0`, which means no wait. (Environment variable: WINDNS_POST_WRITE_DELAY)",
+				},
+				"name_prefix": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("WINDNS_NAME_PREFIX", ""),
+					ValidateFunc: validation.StringMatch(recordNameAffixPattern, "must only contain letters, digits, `.`, `-` and `_`"),
+					Description:  "A prefix added to the name of every record managed by the provider, e.g. `dev-`. It is stripped from the names read from the server, so `name` attributes are written without it. Not added to records at the zone apex. (Environment variable: WINDNS_NAME_PREFIX)",
+				},
+				"name_suffix": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("WINDNS_NAME_SUFFIX", ""),
+					ValidateFunc: validation.StringMatch(recordNameAffixPattern, "must only contain letters, digits, `.`, `-` and `_`"),
+					Description:  "A suffix added to the name of every record managed by the provider, e.g. `.dev` or `-dev`. It is stripped from the names read from the server, so `name` attributes are written without it. Not added to records at the zone apex. (Environment variable: WINDNS_NAME_SUFFIX)",
+				},
+				"skip_create_precheck": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Skip checking whether records already exist before creating them, saving a remote command for every record created. Conflicts are then only reported by the DNS server, and `force_overwrite` has no effect.",
+				},
+				"verify_serial": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("WINDNS_VERIFY_SERIAL", ""),
+					ValidateFunc: validation.StringInSlice([]string{"", dnshelper.VerifySerialWarn, dnshelper.VerifySerialError}, false),
+					Description:  "Verify that the serial number of the zone advanced after changing the records of a `windns_record` resource, to catch changes the DNS server reported as successful without applying them. `warn` reports a serial number that did not advance as a warning, and `error` fails the apply. Costs two remote commands for every change. Not verified for records in a virtualization instance. By default the serial number is not verified. (Environment variable: WINDNS_VERIFY_SERIAL)",
+				},
+				"destroy_protection_threshold": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("WINDNS_DESTROY_PROTECTION_THRESHOLD", 0),
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "The maximum number of `windns_record` resources destroyed in one run, as a guard against mass deletion by mistake, e.g. by a misconfigured `for_each`. A plan destroying more, including replacements, fails before any of them is deleted, unless `destroy_protection_override` is set. Defaults to `0`, which means no limit. (Environment variable: WINDNS_DESTROY_PROTECTION_THRESHOLD)",
+				},
+				"destroy_protection_override": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("WINDNS_DESTROY_PROTECTION_OVERRIDE", false),
+					Description: "Allow destroying more `windns_record` resources than `destroy_protection_threshold` in one run, e.g. with `WINDNS_DESTROY_PROTECTION_OVERRIDE=true terraform apply` after reviewing the plan that failed. (Environment variable: WINDNS_DESTROY_PROTECTION_OVERRIDE)",
 				},
 			},
-			DataSourcesMap: map[string]*schema.Resource{},
+			DataSourcesMap: map[string]*schema.Resource{
+				"windns_ptr":           dataSourceDNSPtr(),
+				"windns_server_status": dataSourceDNSServerStatus(),
+				"windns_zone_export":   dataSourceDNSZoneExport(),
+				"windns_zones":         dataSourceDNSZones(),
+			},
 			ResourcesMap: map[string]*schema.Resource{
-				"windns_record": resourceDNSRecord(),
+				"windns_forwarder":         resourceDNSForwarder(),
+				"windns_record":            resourceDNSRecord(),
+				"windns_records":           resourceDNSRecords(),
+				"windns_root_hint":         resourceDNSRootHint(),
+				"windns_secondary_zone":    resourceDNSSecondaryZone(),
+				"windns_server_scavenging": resourceDNSServerScavenging(),
+				"windns_zone":              resourceDNSZone(),
+				"windns_zone_soa":          resourceDNSZoneSOA(),
+				"windns_zone_aging":        resourceDNSZoneAging(),
+				"windns_zone_signing":      resourceDNSZoneSigning(),
+				"windns_zone_transfer":     resourceDNSZoneTransfer(),
 			},
 			ConfigureContextFunc: providerConfigure,
 		}
@@ -51,11 +220,31 @@ func Provider(version string) func() *schema.Provider {
 	}
 }
 
+// providerConfigure connects to ssh_hostname before returning the provider's configuration, so that wrong
+// credentials or an unreachable host fail the plan up front rather than the first resource operation.
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (any, diag.Diagnostics) {
+	pcfg, diags := newProviderConf(d)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	// The connection is kept for the first remote command.
+	client, err := pcfg.AcquireSshClient(ctx)
+	if err != nil {
+		return nil, diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("failed to connect to DNS host: %s", config.SSHErrorReason(err)),
+			Detail:   fmt.Sprintf("Could not establish an SSH connection to %s as %s, check the ssh_* provider settings: %s", strings.Join(append([]string{pcfg.Settings.SshHostname}, pcfg.Settings.SshFailoverHostnames...), ", "), pcfg.Settings.SshUsername, err),
+		}}
+	}
+	pcfg.ReleaseSshClient(client)
+	return pcfg, nil
+}
+
+func newProviderConf(d *schema.ResourceData) (*config.ProviderConf, diag.Diagnostics) {
 	cfg, err := config.NewConfig(d)
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
-	pcfg := config.NewProviderConf(cfg)
-	return pcfg, nil
+	return config.NewProviderConf(cfg), nil
 }
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+// ProviderServer returns the gRPC server of the provider, serving Provider. The plugin SDK does not run CustomizeDiff
+// for resources planned to be destroyed, so the server counts the windns_record resources planned to be destroyed or
+// replaced towards destroy_protection_threshold itself, failing the plan before any of them is deleted.
+func ProviderServer(version string) func() tfprotov5.ProviderServer {
+	return func() tfprotov5.ProviderServer {
+		p := Provider(version)()
+		return &providerServer{ProviderServer: schema.NewGRPCProviderServer(p), provider: p}
+	}
+}
+
+type providerServer struct {
+	tfprotov5.ProviderServer
+	provider *schema.Provider
+
+	// recordType is the type of the state of windns_record, read from the provider schema on first use.
+	recordTypeOnce sync.Once
+	recordType     tftypes.Type
+	recordTypeErr  error
+}
+
+func (s *providerServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	resp, err := s.ProviderServer.PlanResourceChange(ctx, req)
+	if err != nil || req.TypeName != "windns_record" || hasErrorDiagnostic(resp.Diagnostics) {
+		return resp, err
+	}
+	conf, ok := s.provider.Meta().(*config.ProviderConf)
+	if !ok {
+		return resp, nil
+	}
+
+	id, key, err := s.plannedDestroy(ctx, req, resp)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "failed to count the records planned to be destroyed",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	if key == "" {
+		return resp, nil
+	}
+	if err := conf.CountDestroy(key); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  fmt.Sprintf("refusing to plan the deletion of records with id %q", id),
+			Detail:   err.Error(),
+		})
+	}
+	return resp, nil
+}
+
+// plannedDestroy returns the ID of the windns_record resource planned by req and resp, and the key it is counted by
+// in CountDestroy, when the plan deletes its records from the server. The key is empty otherwise.
+func (s *providerServer) plannedDestroy(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest, resp *tfprotov5.PlanResourceChangeResponse) (string, string, error) {
+	typ, err := s.recordValueType(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	prior, err := req.PriorState.Unmarshal(typ)
+	if err != nil || prior.IsNull() {
+		return "", "", err
+	}
+	proposed, err := req.ProposedNewState.Unmarshal(typ)
+	if err != nil {
+		return "", "", err
+	}
+	if !proposed.IsNull() && len(resp.RequiresReplace) == 0 {
+		return "", "", nil
+	}
+
+	var attrs map[string]tftypes.Value
+	if err := prior.As(&attrs); err != nil {
+		return "", "", err
+	}
+	// Records created with create_only are left on the server, and disabled records are not on the server.
+	if boolAttr(attrs, "create_only", false) || !boolAttr(attrs, "enabled", true) {
+		return "", "", nil
+	}
+	id := stringAttr(attrs, "id")
+	return id, stringAttr(attrs, "dns_server") + " " + id, nil
+}
+
+// recordValueType returns the type of the state of windns_record.
+func (s *providerServer) recordValueType(ctx context.Context) (tftypes.Type, error) {
+	s.recordTypeOnce.Do(func() {
+		resp, err := s.ProviderServer.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+		if err != nil {
+			s.recordTypeErr = err
+			return
+		}
+		resourceSchema, ok := resp.ResourceSchemas["windns_record"]
+		if !ok {
+			s.recordTypeErr = fmt.Errorf("no schema found for windns_record")
+			return
+		}
+		s.recordType = resourceSchema.ValueType()
+	})
+	return s.recordType, s.recordTypeErr
+}
+
+func hasErrorDiagnostic(diags []*tfprotov5.Diagnostic) bool {
+	for _, v := range diags {
+		if v.Severity == tfprotov5.DiagnosticSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// boolAttr returns the boolean attribute name of attrs, or def when it is null or unknown.
+func boolAttr(attrs map[string]tftypes.Value, name string, def bool) bool {
+	var v *bool
+	if !attrs[name].IsKnown() || attrs[name].As(&v) != nil || v == nil {
+		return def
+	}
+	return *v
+}
+
+// stringAttr returns the string attribute name of attrs, or an empty string when it is null or unknown.
+func stringAttr(attrs map[string]tftypes.Value, name string) string {
+	var v string
+	if !attrs[name].IsKnown() || attrs[name].As(&v) != nil {
+		return ""
+	}
+	return v
+}
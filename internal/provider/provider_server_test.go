@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+)
+
+func TestProviderServer_PlanResourceChange(t *testing.T) {
+	type plan struct {
+		id         string
+		createOnly bool
+		kept       bool
+	}
+
+	tests := []struct {
+		name       string
+		settings   config.Settings
+		plans      []plan
+		wantErrors []bool
+	}{
+		{
+			"test-within-threshold", config.Settings{DestroyProtectionThreshold: 2},
+			[]plan{{id: "a"}, {id: "b"}},
+			[]bool{false, false},
+		},
+		{
+			"test-above-threshold", config.Settings{DestroyProtectionThreshold: 2},
+			[]plan{{id: "a"}, {id: "b"}, {id: "c"}},
+			[]bool{false, false, true},
+		},
+		{
+			"test-planned-twice", config.Settings{DestroyProtectionThreshold: 2},
+			[]plan{{id: "a"}, {id: "b"}, {id: "a"}},
+			[]bool{false, false, false},
+		},
+		{
+			"test-create-only", config.Settings{DestroyProtectionThreshold: 1},
+			[]plan{{id: "a", createOnly: true}, {id: "b"}},
+			[]bool{false, false},
+		},
+		{
+			"test-override", config.Settings{DestroyProtectionThreshold: 1, DestroyProtectionOverride: true},
+			[]plan{{id: "a"}, {id: "b"}},
+			[]bool{false, false},
+		},
+		{
+			"test-no-limit", config.Settings{},
+			[]plan{{id: "a"}, {id: "b"}},
+			[]bool{false, false},
+		},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := ProviderServer("dev")().(*providerServer)
+			settings := tt.settings
+			s.provider.SetMeta(config.NewProviderConf(&settings))
+			typ, err := s.recordValueType(ctx)
+			if err != nil {
+				t.Fatalf("recordValueType() error = %v", err)
+			}
+
+			for i, p := range tt.plans {
+				prior := testRecordState(t, typ, map[string]tftypes.Value{
+					"id":          tftypes.NewValue(tftypes.String, p.id),
+					"enabled":     tftypes.NewValue(tftypes.Bool, true),
+					"create_only": tftypes.NewValue(tftypes.Bool, p.createOnly),
+				})
+				resp, err := s.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+					TypeName:         "windns_record",
+					PriorState:       prior,
+					ProposedNewState: testRecordState(t, typ, nil),
+					Config:           testRecordState(t, typ, nil),
+				})
+				if err != nil {
+					t.Fatalf("PlanResourceChange() error = %v", err)
+				}
+				if got := hasErrorDiagnostic(resp.Diagnostics); got != tt.wantErrors[i] {
+					t.Errorf("PlanResourceChange() of %q failed = %v, want %v: %v", p.id, got, tt.wantErrors[i], resp.Diagnostics)
+				}
+			}
+		})
+	}
+}
+
+// testRecordState returns a windns_record state of type typ with the attributes in attrs, and the others null. The
+// state is null when attrs is nil.
+func testRecordState(t *testing.T, typ tftypes.Type, attrs map[string]tftypes.Value) *tfprotov5.DynamicValue {
+	t.Helper()
+
+	value := tftypes.NewValue(typ, nil)
+	if attrs != nil {
+		values := make(map[string]tftypes.Value)
+		for name, attrType := range typ.(tftypes.Object).AttributeTypes {
+			values[name] = tftypes.NewValue(attrType, nil)
+			if v, ok := attrs[name]; ok {
+				values[name] = v
+			}
+		}
+		value = tftypes.NewValue(typ, values)
+	}
+
+	dv, err := tfprotov5.NewDynamicValue(typ, value)
+	if err != nil {
+		t.Fatalf("NewDynamicValue() error = %v", err)
+	}
+	return &dv
+}
@@ -3,7 +3,9 @@
 package provider
 
 import (
+	"context"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -29,6 +31,90 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+func TestProviderConfigure_Settings(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		raw         map[string]interface{}
+		wantErr     string
+		wantHost    string
+		wantUser    string
+		wantDNSHost string
+	}{
+		{
+			name:    "test-missing",
+			wantErr: "ssh_username (environment variable WINDNS_SSH_USERNAME), ssh_password (environment variable WINDNS_SSH_PASSWORD), ssh_hostname (environment variable WINDNS_SSH_HOSTNAME)",
+		},
+		{
+			name:    "test-missing-hostname",
+			env:     map[string]string{"WINDNS_SSH_USERNAME": "user", "WINDNS_SSH_PASSWORD": "password"},
+			wantErr: "missing provider configuration: ssh_hostname (environment variable WINDNS_SSH_HOSTNAME) must be set",
+		},
+		{
+			name:        "test-env",
+			env:         map[string]string{"WINDNS_SSH_USERNAME": "user", "WINDNS_SSH_PASSWORD": "password", "WINDNS_SSH_HOSTNAME": "jump.example.com", "WINDNS_DNS_SERVER": "dc01.example.com"},
+			wantHost:    "jump.example.com",
+			wantUser:    "user",
+			wantDNSHost: "dc01.example.com",
+		},
+		{
+			name:     "test-agent-without-password",
+			env:      map[string]string{"WINDNS_SSH_USERNAME": "user", "WINDNS_SSH_HOSTNAME": "jump.example.com", "WINDNS_SSH_USE_AGENT": "true"},
+			wantHost: "jump.example.com",
+			wantUser: "user",
+		},
+		{
+			name:        "test-config-over-env",
+			env:         map[string]string{"WINDNS_SSH_USERNAME": "user", "WINDNS_SSH_PASSWORD": "password", "WINDNS_SSH_HOSTNAME": "jump.example.com", "WINDNS_DNS_SERVER_HOSTNAME": "dc01.example.com"},
+			raw:         map[string]interface{}{"ssh_username": "admin", "ssh_hostname": "other.example.com", "dns_server": "dc02.example.com"},
+			wantHost:    "other.example.com",
+			wantUser:    "admin",
+			wantDNSHost: "dc02.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, k := range []string{"WINDNS_SSH_USERNAME", "WINDNS_SSH_PASSWORD", "WINDNS_SSH_HOSTNAME", "WINDNS_DNS_SERVER_HOSTNAME", "WINDNS_DNS_SERVER", "WINDNS_SSH_USE_AGENT"} {
+				t.Setenv(k, tt.env[k])
+			}
+
+			d := schema.TestResourceDataRaw(t, Provider("dev")().Schema, tt.raw)
+			pcfg, diags := newProviderConf(d)
+			if tt.wantErr != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tt.wantErr) {
+					t.Fatalf("newProviderConf() diags = %v, want error containing %q", diags, tt.wantErr)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("newProviderConf() diags = %v", diags)
+			}
+
+			settings := pcfg.Settings
+			if settings.SshHostname != tt.wantHost || settings.SshUsername != tt.wantUser || settings.DnsServer != tt.wantDNSHost {
+				t.Errorf("newProviderConf() settings = %+v, want ssh_hostname %q, ssh_username %q and dns_server %q", settings, tt.wantHost, tt.wantUser, tt.wantDNSHost)
+			}
+		})
+	}
+}
+
+func TestProviderConfigure_ConnectionFailure(t *testing.T) {
+	raw := map[string]interface{}{
+		"ssh_username": "user",
+		"ssh_password": "password",
+		"ssh_hostname": "127.0.0.1",
+		"ssh_port":     1,
+		"ssh_insecure": true,
+	}
+	d := schema.TestResourceDataRaw(t, Provider("dev")().Schema, raw)
+
+	_, diags := providerConfigure(context.Background(), d)
+	if !diags.HasError() || diags[0].Summary != "failed to connect to DNS host: connection failed" {
+		t.Fatalf("providerConfigure() diags = %v, want a connection failure", diags)
+	}
+}
+
 func testAccPreCheck(t *testing.T, envVars []string) {
 	for _, envVar := range envVars {
 		if val := os.Getenv(envVar); val == "" {
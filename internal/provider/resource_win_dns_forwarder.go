@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+)
+
+// forwarderParams maps the attributes of windns_forwarder to the Set-DnsServerForwarder parameters they manage.
+var forwarderParams = map[string]string{
+	"ip_addresses":  "IPAddress",
+	"use_root_hint": "UseRootHint",
+}
+
+func resourceDNSForwarder() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windns_forwarder` manages the server level forwarders of a Windows DNS Server.",
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDNSForwarderImport,
+		},
+		ReadContext:   resourceDNSForwarderRead,
+		CreateContext: resourceDNSForwarderCreate,
+		UpdateContext: resourceDNSForwarderUpdate,
+		DeleteContext: resourceDNSForwarderDelete,
+		Schema: map[string]*schema.Schema{
+			"ip_addresses": {
+				Type:        schema.TypeList,
+				Required:    true,
+				Description: "The IP addresses of the forwarders, in the order the server queries them. An empty list removes the forwarders of the server.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsIPAddress,
+				},
+			},
+			"use_root_hint": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether the server queries the root hints when none of the forwarders answer. Read from the server when unset.",
+			},
+		},
+	}
+}
+
+// forwarderParamValue returns the value of attr in the form Set-DnsServerForwarder takes it.
+func forwarderParamValue(d *schema.ResourceData, attr string) any {
+	if list, ok := d.Get(attr).([]any); ok {
+		return listToStringSlice(list)
+	}
+	return d.Get(attr)
+}
+
+func resourceDNSForwarderCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.ProviderConf)
+
+	// Every server has forwarders, if only an empty list, so creating the resource adopts them and applies the configured
+	// fields. false is a meaningful value, so the configuration tells which fields are set.
+	changes := make(map[string]any)
+	raw := d.GetRawConfig()
+	for attr, param := range forwarderParams {
+		if !raw.IsNull() && !raw.GetAttr(attr).IsNull() {
+			changes[param] = forwarderParamValue(d, attr)
+		}
+	}
+
+	fwd := dnshelper.Forwarders{}
+	err := fwd.Update(ctx, conf, changes)
+	if err != nil {
+		return diag.Errorf("error while updating forwarders of the DNS server: %s", err)
+	}
+
+	d.SetId(serverID(conf))
+	return resourceDNSForwarderRead(ctx, d, meta)
+}
+
+func resourceDNSForwarderRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Id() == "" {
+		return nil
+	}
+
+	fwd, err := dnshelper.GetForwarders(ctx, meta.(*config.ProviderConf))
+	if err != nil {
+		return diag.Errorf("error while reading forwarders of the DNS server: %s", err)
+	}
+
+	_ = d.Set("ip_addresses", fwd.IPAddresses)
+	_ = d.Set("use_root_hint", fwd.UseRootHint)
+
+	return nil
+}
+
+func resourceDNSForwarderUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	changes := make(map[string]any)
+	for attr, param := range forwarderParams {
+		if d.HasChange(attr) {
+			changes[param] = forwarderParamValue(d, attr)
+		}
+	}
+
+	fwd := dnshelper.Forwarders{}
+	err := fwd.Update(ctx, meta.(*config.ProviderConf), changes)
+	if err != nil {
+		return diag.Errorf("error while updating forwarders of the DNS server: %s", err)
+	}
+	return resourceDNSForwarderRead(ctx, d, meta)
+}
+
+// A server always has forwarders, if only an empty list, so deleting the resource only removes them from the state.
+func resourceDNSForwarderDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+// resourceDNSForwarderImport imports the forwarders of the DNS server the provider manages, whatever ID is given.
+func resourceDNSForwarderImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.SetId(serverID(meta.(*config.ProviderConf)))
+	return []*schema.ResourceData{d}, nil
+}
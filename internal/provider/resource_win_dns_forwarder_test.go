@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccResourceDNSForwarderConfigBasic = `
+resource "windns_forwarder" "forwarder" {
+  ip_addresses  = ["198.51.100.53", "192.0.2.53"]
+  use_root_hint = false
+}
+`
+
+const testAccResourceDNSForwarderConfigReordered = `
+resource "windns_forwarder" "forwarder" {
+  ip_addresses  = ["192.0.2.53", "198.51.100.53"]
+  use_root_hint = true
+}
+`
+
+const testAccResourceDNSForwarderConfigEmpty = `
+resource "windns_forwarder" "forwarder" {
+  ip_addresses = []
+}
+`
+
+func TestAccResourceDNSForwarder_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSForwarderConfigBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_forwarder.forwarder", "ip_addresses.#", "2"),
+					resource.TestCheckResourceAttr("windns_forwarder.forwarder", "ip_addresses.0", "198.51.100.53"),
+					resource.TestCheckResourceAttr("windns_forwarder.forwarder", "ip_addresses.1", "192.0.2.53"),
+					resource.TestCheckResourceAttr("windns_forwarder.forwarder", "use_root_hint", "false"),
+				),
+			},
+			{
+				Config: testAccResourceDNSForwarderConfigReordered,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_forwarder.forwarder", "ip_addresses.0", "192.0.2.53"),
+					resource.TestCheckResourceAttr("windns_forwarder.forwarder", "ip_addresses.1", "198.51.100.53"),
+					resource.TestCheckResourceAttr("windns_forwarder.forwarder", "use_root_hint", "true"),
+				),
+			},
+			{
+				ResourceName:      "windns_forwarder.forwarder",
+				ImportState:       true,
+				ImportStateId:     "dc01",
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccResourceDNSForwarderConfigEmpty,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_forwarder.forwarder", "ip_addresses.#", "0"),
+					resource.TestCheckResourceAttr("windns_forwarder.forwarder", "use_root_hint", "true"),
+				),
+			},
+		},
+	})
+}
@@ -4,20 +4,34 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/nrkno/terraform-provider-windns/internal/config"
 	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+	"golang.org/x/exp/slices"
 )
 
+// defaultRecordTimeout is used for each operation unless overridden in the resource's timeouts block.
+const defaultRecordTimeout = 20 * time.Minute
+
 func resourceDNSRecord() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Description: "`windns_record` manages DNS Records in a Windows DNS Server.",
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceDNSRecordImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultRecordTimeout),
+			Read:   schema.DefaultTimeout(defaultRecordTimeout),
+			Update: schema.DefaultTimeout(defaultRecordTimeout),
+			Delete: schema.DefaultTimeout(defaultRecordTimeout),
 		},
 		ReadContext:   resourceDNSRecordRead,
 		CreateContext: resourceDNSRecordCreate,
@@ -26,30 +40,78 @@ func resourceDNSRecord() *schema.Resource {
 		Schema: map[string]*schema.Schema{
 			"zone_name": {
 				Type:             schema.TypeString,
-				Required:         true,
-				DiffSuppressFunc: suppressCaseDiff,
-				Description:      "The zone name for the dns records.",
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateDomainName,
+				DiffSuppressFunc: suppressDomainNameDiff,
+				Description:      "The zone name for the dns records. Defaults to the provider's `default_zone`, and must be set when the provider has none. The zone must exist on the server, which is verified before new records are added. Like `name`, the zone name is case-insensitive and kept as configured, and may be internationalized.",
 			},
 			"name": {
 				Type:             schema.TypeString,
 				Required:         true,
-				DiffSuppressFunc: suppressCaseDiff,
-				Description:      "The name of the dns records.",
+				ValidateFunc:     validateDomainName,
+				DiffSuppressFunc: suppressNameDiff,
+				Description:      "The name of the dns records. Use `@` or an empty string for the zone apex, and `*` or a name starting with `*.` for wildcard records. Names are case-insensitive and kept as configured, so changing only their casing is not a change. Internationalized names may be written in Unicode, e.g. `bücher`, or as A-labels, e.g. `xn--bcher-kva`, and are stored on the server as A-labels.",
 			},
 			"type": {
 				Type:             schema.TypeString,
 				Required:         true,
+				ValidateFunc:     validateRecordType,
 				DiffSuppressFunc: suppressCaseDiff,
-				Description:      "The type of the dns records.",
+				Description:      "The type of the dns records. `SOA` records are managed with `windns_zone_soa`.",
 			},
 			"records": {
-				Type:             schema.TypeSet,
+				Type:             schema.TypeList,
 				Required:         true,
-				Description:      "A list of records.",
+				Description:      "A list of records. Record data made up of several fields is written space separated, as in a zone file: `<subtype> <hostname>` for `AFSDB`, `<mailbox> <txt-domain>` for `RP`, `<isdn-address> [<subaddress>]` for `ISDN`, `<address> <protocol> <service>...` for `WKS`, `<key-tag> <algorithm> <digest-type> <digest>` for `DS`, `<responsible-mailbox> <error-mailbox>` for `MINFO`, `<address-format> <address>` for `ATMA`, `<type> <key-tag> <algorithm> <certificate>` for `CERT` and `<preference> <intermediate-host>` for `RT`, where the algorithm, digest type, address format (`E164` or `NSAP`) and certificate type (e.g. `PKIX`) may be given as numbers or by name. The base64 certificate of `CERT` records may be split by spaces, and is stored in the state in one piece, with a numeric certificate type. `DHCID` records are written as their base64 record data, which may be split by spaces too. Domain names, e.g. in `PTR` and `CNAME` records, may be written with or without the trailing `.`, and are stored in the state with it, as returned by the server. Record data is compared case-insensitively, except for `TXT` records, which are stored and compared exactly as written, and `CERT` and `DHCID` records.",
 				DiffSuppressFunc: suppressRecordDiff,
-				Set:              schema.HashString,
 				Elem:             &schema.Schema{Type: schema.TypeString},
-				MinItems:         1,
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the records are on the server. Setting it to `false` removes the records from the server while keeping the resource and its configuration, e.g. to take a name out of service during maintenance, and setting it back to `true` adds them again. Records found on the server while disabled are planned to be removed. Defaults to `true`.",
+			},
+			"allow_empty": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Allow `records` to be empty, which removes all records of the name and type from the server while keeping the resource. By default an empty `records` list is an error when planning, so a variable evaluating to an empty list by mistake cannot remove the records.",
+			},
+			"ttl": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateTTL,
+				StateFunc:    canonicalTTL,
+				Description:  "The TTL of the records, either in seconds, e.g. `3600`, or with the units `s`, `m`, `h`, `d` and `w`, e.g. `1h`, `30m`, `1d` or `1h30m`. Stored in the state in seconds, so `3600` and `1h` are the same TTL. All records of the name and type share the TTL. By default records are created with the default TTL of the zone. Read as `0` when the records on the server have different TTLs, which must then be resolved by setting `ttl`.",
+			},
+			"ordered_records": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Keep the records on the server in the order of `records`, re-adding records that are out of place. Each value may then only be listed once. By default the order of `records` is ignored, and a value listed more than once is added once.",
+			},
+			"explicit_txt_segments": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Write each of the `records` of a `TXT` record as the list of its character-strings, each in double quotes and at most 255 characters long, e.g. `\"v=DKIM1; k=rsa; \" \"p=MIIBIjANBg...\"`, to control exactly how the record is split. By default each of the `records` is a single string, which is split into character-strings of 255 characters when longer, and whose splitting on the server is ignored.",
+			},
+			"append_only": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"ordered_records"},
+				Description:   "Only manage the values in `records`, leaving other records of the name and type on the server in place, e.g. when several teams add records to the same name. Values removed from `records` are still removed from the server, and existing records are added to when creating, without `force_overwrite`. By default the records on the server are made to match `records` exactly, removing any others.",
+			},
+			"create_only": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"force_overwrite"},
+				Description:   "Only create the records when none of the name and type exist on the server, e.g. to seed records in a zone shared with other owners. Existing records are adopted as they are, changes to `records` and `ttl` are not applied, and destroying the resource leaves the records in place. Refreshing only checks that the records still exist, and plans to create them again when they are gone.",
 			},
 			"create_ptr": {
 				Type:        schema.TypeBool,
@@ -57,37 +119,280 @@ func resourceDNSRecord() *schema.Resource {
 				Optional:    true,
 				Description: "Create PTR records for requested (A or AAAA) records.",
 			},
+			"manage_ptr_lifecycle": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Remove the PTR records created by `create_ptr` along with their addresses, when destroying the resource or removing addresses from `records`. By default PTR records are left in place, e.g. for reverse zones managed by another tool.",
+			},
+			"missing_ptr_zone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      dnshelper.MissingPtrZoneError,
+				ValidateFunc: validation.StringInSlice([]string{dnshelper.MissingPtrZoneError, dnshelper.MissingPtrZoneWarn}, false),
+				Description:  "How `create_ptr` handles addresses whose reverse zone, `ptr_zone_name` or otherwise the zone the DNS server would pick, does not exist. `error` fails when applying, before any record is added. `warn` adds the addresses without PTR records and reports them in a warning.",
+			},
+			"ptr_zone_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The reverse zone to create PTR records in when `create_ptr` is set. By default the DNS server picks the reverse zone.",
+			},
+			"force_overwrite": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Adopt records of the same type that already exist on the server when creating, replacing their data with `records`. Without it, creating a record that already exists fails. Has no effect when the provider's `skip_create_precheck` is set.",
+			},
+			"trim_whitespace": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Remove leading and trailing whitespace from each of the `records` before it is added, so a stray space from a generated value neither fails validation nor is planned as a change. Whitespace within a value, e.g. between the words of a `TXT` record, is kept. Disable to add `TXT` records whose leading or trailing whitespace is significant, which is then compared exactly. Whitespace around the data of other types is never significant, and ignored when comparing either way.",
+			},
+			"owner_tag": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[^\r\n]{1,200}$`), "must be a single line of at most 200 characters"),
+				Description:  "Tag the records as managed by Terraform with this owner, e.g. a team name, so admins can identify them in the DNS console. Windows DNS has no notes field on records, so the tag is a `TXT` record with the text `managed-by=terraform; owner=<owner_tag>` at a sibling name, `tf-owner-<type>.<name>`, e.g. `tf-owner-a.www` for the `A` records of `www`. The tag is removed along with the records. Cannot be set on wildcard records.",
+			},
+			"access_rule": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Grant a principal rights on the node of the records in Active Directory, e.g. so a delegated team may update them. Only the rules set on the node for the principals listed are managed, and the rules of other principals are left in place. Requires an AD-integrated zone.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"principal": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotWhiteSpace,
+							Description:  "The account or group granted the rights, e.g. `EXAMPLE\\dns-admins`, or its SID.",
+						},
+						"rights": {
+							Type:        schema.TypeSet,
+							Required:    true,
+							MinItems:    1,
+							Description: "The rights granted, named as in `System.DirectoryServices.ActiveDirectoryRights`, e.g. `GenericRead` and `WriteProperty`, or `GenericAll` for full control.",
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice(dnshelper.ActiveDirectoryRightNames(), false),
+							},
+						},
+					},
+				},
+			},
+			"require_static": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Refuse to manage records registered by dynamic update, e.g. by a DHCP server. Creating the resource fails when `force_overwrite` would adopt dynamic records, and updating it fails once the records have been registered dynamically.",
+			},
+			"virtualization_instance": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[a-zA-Z0-9.\-]+$`), "must only contain letters, digits, `.` and `-`"),
+				Description:  "The DNS server virtualization instance holding the zone, for servers partitioned into several instances. By default the records are managed in the default instance.",
+			},
+			"zone_scope": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"create_ptr"},
+				ValidateFunc:  validation.StringMatch(regexp.MustCompile(`^[a-zA-Z0-9.\-]+$`), "must only contain letters, digits, `.` and `-`"),
+				Description:   "The zone scope holding the records, for split-horizon DNS where DNS policies answer queries from some clients, e.g. by subnet, from another scope of the zone. The scope must exist, e.g. created with `Add-DnsServerZoneScope`. By default the records are managed in the default scope of the zone.",
+			},
+			"dns_server": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The hostname of the DNS server managing the records, overriding the provider's `dns_server`, e.g. for zones only hosted on some of the domain controllers. The commands still run on the provider's `ssh_hostname`. By default the records are managed on the provider's `dns_server`.",
+			},
+			"dn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The distinguished name of the record's node. For AD-integrated zones this includes the directory partition the zone is replicated in.",
+			},
+			"txt_segments": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "For `TXT` records, the records as split into character-strings on the server, each written as the list of its character-strings in double quotes, as in `records` with `explicit_txt_segments`. Empty for other types.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"dynamic": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the records were registered by dynamic update, e.g. by a DHCP server, rather than added statically.",
+			},
+			"timestamp": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time the server last refreshed the records, in RFC 3339 format. Only dynamically updated records have a timestamp, it is empty for static records.",
+			},
+			"last_commands": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The PowerShell commands that changed the records on the server in the last create or update, rendered with their parameters, for audit trails. Queries are left out, and credentials are never part of the commands. Empty when the last update changed nothing on the server.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"fqdn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The fully qualified domain name of the dns records, without the trailing dot. Internationalized labels are written as A-labels, as used in DNS queries and record data.",
+			},
 		},
 		CustomizeDiff: customdiff.All(
-			customdiff.ForceNewIfChange("zone_name", func(ctx context.Context, old, new, meta any) bool {
-				return new.(string) != old.(string)
-			}),
-			customdiff.ForceNewIfChange("name", func(ctx context.Context, old, new, meta any) bool {
-				return new.(string) != old.(string)
-			}),
-			customdiff.ForceNewIfChange("type", func(ctx context.Context, old, new, meta any) bool {
-				return new.(string) != old.(string)
-			}),
-			customdiff.ForceNewIfChange("type", func(ctx context.Context, old, new, meta any) bool {
-				return new.(string) != old.(string)
-			}),
+			customizeDiffDefaultZone,
+			customizeDiffEmptyRecords,
+			customizeDiffEnabled,
+			customdiff.ForceNewIfChange("zone_name", domainNameChanged),
+			customdiff.ForceNewIfChange("name", nameChanged),
+			customdiff.ForceNewIfChange("type", changedIgnoringCase),
+			customizeDiffFQDN,
+			customizeDiffTTL,
+			customizeDiffPtrZone,
+			customizeDiffRecordZone,
+			customizeDiffCNAME,
+			customizeDiffOwnerTag,
+			customizeDiffCNAMEConflict,
+			customizeDiffLastCommands,
 		),
 	}
+	// Version 1 stores ttl as a string, to accept TTLs written with units.
+	r.SchemaVersion = 1
+	r.StateUpgraders = []schema.StateUpgrader{{
+		Version: 0,
+		Type:    recordSchemaV0(r.Schema).CoreConfigSchema().ImpliedType(),
+		Upgrade: upgradeRecordStateV0,
+	}}
+	return r
+}
+
+// recordSchemaV0 returns the version 0 schema of windns_record, in which ttl was a number, given the current schema.
+func recordSchemaV0(current map[string]*schema.Schema) *schema.Resource {
+	v0 := make(map[string]*schema.Schema, len(current))
+	for k, v := range current {
+		v0[k] = v
+	}
+	v0["ttl"] = &schema.Schema{Type: schema.TypeInt, Optional: true, Computed: true}
+	return &schema.Resource{Schema: v0}
+}
+
+// upgradeRecordStateV0 converts the numeric ttl of version 0 states to the string of seconds stored since version 1.
+func upgradeRecordStateV0(ctx context.Context, rawState map[string]any, meta any) (map[string]any, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+	switch ttl := rawState["ttl"].(type) {
+	case float64:
+		rawState["ttl"] = dnshelper.FormatTTL(int64(ttl))
+	case nil:
+		rawState["ttl"] = ""
+	}
+	return rawState, nil
 }
 
 func resourceDNSRecordCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	record, err := dnshelper.NewDNSRecordFromResource(d)
+	if err := checkEmptyRecords(d); err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, commands := dnshelper.WithCommandLog(ctx)
+	conf := recordConf(d, meta)
+	record, err := dnshelper.NewDNSRecordFromResource(conf, d)
 	if err != nil {
 		return diag.Errorf("error when mapping input data: %s", err)
 	}
+	if !d.Get("enabled").(bool) {
+		// Disabled records are kept off the server, and added once enabled.
+		record.Records = []string{}
+	}
+	if err := checkZoneExists(ctx, conf, record); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var existing *dnshelper.Record
+	if !conf.Settings.SkipCreatePrecheck {
+		existing, err = dnshelper.GetDNSRecordFromId(ctx, conf, record.Id())
+		if err != nil && !dnshelper.IsNotFound(err) {
+			return diag.Errorf("error while checking for existing record with id %q: %s", record.Id(), err)
+		}
+	}
+
+	if existing != nil && d.Get("create_only").(bool) {
+		// The records are left as they are, whether they match records or not.
+		d.SetId(record.Id())
+		_ = d.Set("last_commands", commands.Commands())
+		return resourceDNSRecordRead(ctx, d, meta)
+	}
+
+	check, diags := startSerialCheck(ctx, conf, record)
+	if diags.HasError() {
+		return diags
+	}
+
+	if existing != nil {
+		if !d.Get("force_overwrite").(bool) && !d.Get("append_only").(bool) {
+			return diag.Errorf("%s records already exist for %q in zone %q. Import them or set force_overwrite to adopt them", record.RecordType, record.HostName, record.ZoneName)
+		}
+
+		if d.Get("require_static").(bool) && existing.Dynamic() {
+			return diag.Errorf("%s records for %q in zone %q were registered by dynamic update and require_static is set", record.RecordType, record.HostName, record.ZoneName)
+		}
+
+		// Only records of the same type are read above, so records of other types at the name are left untouched. With
+		// append_only the existing records are only added to.
+		changes := map[string]interface{}{"records": enabledRecords(d)}
+		if v, ok := d.GetOk("ttl"); ok {
+			changes["ttl"] = v
+		}
+		err = record.Update(ctx, conf, changes)
+		if err != nil {
+			return diag.Errorf("error while overwriting existing record object: %s", err)
+		}
+		// The records are in the state once written, so a failure below does not leave them unmanaged on the server.
+		d.SetId(record.Id())
+		if d.Get("owner_tag").(string) != "" {
+			if diags := setOwnerTag(ctx, d, conf, record); diags != nil {
+				return diags
+			}
+		}
+		if diags := setAccessRules(ctx, d, conf); diags != nil {
+			return diags
+		}
+		_ = d.Set("last_commands", commands.Commands())
+		diags = append(ptrRecordsSkipped(record), waitPostWrite(ctx, conf, len(commands.Commands()) > 0)...)
+		diags = append(diags, verifySerial(ctx, conf, check, commands)...)
+		return append(diags, resourceDNSRecordRead(ctx, d, meta)...)
+	}
 
-	id, err := record.Create(meta.(*config.ProviderConf))
+	if len(record.Records) == 0 {
+		// With allow_empty or enabled unset there is nothing to add, and the records are added once records has values
+		// and they are enabled.
+		d.SetId(record.Id())
+		_ = d.Set("last_commands", commands.Commands())
+		return resourceDNSRecordRead(ctx, d, meta)
+	}
+
+	id, err := record.Create(ctx, conf)
 	if err != nil {
 		return diag.Errorf("error while creating new record object: %s", err)
 	}
 	d.SetId(id)
+	if d.Get("owner_tag").(string) != "" {
+		if diags := setOwnerTag(ctx, d, conf, record); diags != nil {
+			return diags
+		}
+	}
+	if diags := setAccessRules(ctx, d, conf); diags != nil {
+		return diags
+	}
+	_ = d.Set("last_commands", commands.Commands())
 
-	return resourceDNSRecordRead(ctx, d, meta)
+	diags = append(ptrRecordsSkipped(record), waitPostWrite(ctx, conf, len(commands.Commands()) > 0)...)
+	diags = append(diags, verifySerial(ctx, conf, check, commands)...)
+	return append(diags, resourceDNSRecordRead(ctx, d, meta)...)
 }
 
 func resourceDNSRecordRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -95,58 +400,677 @@ func resourceDNSRecordRead(ctx context.Context, d *schema.ResourceData, meta int
 		return nil
 	}
 
-	record, err := dnshelper.GetDNSRecordFromId(ctx, meta.(*config.ProviderConf), d.Id())
+	conf := recordConf(d, meta)
+	record, err := dnshelper.GetDNSRecordFromId(ctx, conf, d.Id())
+	if !d.Get("enabled").(bool) && (dnshelper.IsNotFound(err) || (err == nil && !hasManagedRecords(d, record))) {
+		// Disabled records are expected to be missing, and the configured records are kept in the state to be added
+		// again once enabled.
+		_ = d.Set("dn", "")
+		_ = d.Set("dynamic", false)
+		_ = d.Set("timestamp", "")
+		return nil
+	}
 	if err != nil {
-		if strings.Contains(err.Error(), "ObjectNotFound") {
-			// Resource no longer exists
+		if dnshelper.IsNotFound(err) && d.Get("allow_empty").(bool) {
+			// Having no records is a valid state with allow_empty, so the resource is kept, planning to add any
+			// configured records.
+			_ = d.Set("records", []string{})
+			_ = d.Set("txt_segments", []string{})
+			_ = d.Set("dn", "")
+			_ = d.Set("dynamic", false)
+			_ = d.Set("timestamp", "")
+			return nil
+		}
+		if dnshelper.IsNotFound(err) {
+			// The records were deleted outside of Terraform, remove them from state to plan their recreation
 			d.SetId("")
 			return nil
 		}
 		return diag.Errorf("error while reading record with id %q: %s", d.Id(), err)
 	}
 
-	_ = d.Set("zone_name", record.ZoneName)
-	_ = d.Set("name", record.HostName)
-	_ = d.Set("type", record.RecordType)
-	_ = d.Set("records", record.Records)
+	_ = d.Set("zone_name", preserveName(d.Get("zone_name").(string), record.ZoneName))
+	_ = d.Set("name", preserveName(d.Get("name").(string), dnshelper.RecordName(conf, record.HostName)))
+	_ = d.Set("type", preserveCase(d.Get("type").(string), record.RecordType))
+	records := dnshelper.RecordValues(record.RecordType, record.Records, d.Get("explicit_txt_segments").(bool))
+	if d.Get("append_only").(bool) {
+		// Records added by others are not managed, so they are left out of the state.
+		records = dnshelper.FilterRecordData(record.RecordType, records, configuredRecords(d))
+	}
+	// With create_only the records are never updated, so only their existence is read, keeping the configured records
+	// and TTL in the state rather than planning changes that would not be applied.
+	createOnly := d.Get("create_only").(bool)
+	if !createOnly || len(d.Get("records").([]interface{})) == 0 {
+		_ = d.Set("records", records)
+	}
+	if !createOnly || !hasTTL(d) {
+		_ = d.Set("ttl", dnshelper.FormatTTL(record.TTL))
+	}
+	// Records found on the server are enabled, which plans to remove them again when the configuration disables them.
+	_ = d.Set("enabled", true)
+	_ = d.Set("txt_segments", txtSegments(record))
 	_ = d.Set("create_ptr", record.CreatePtr)
+	_ = d.Set("virtualization_instance", record.VirtualizationInstance)
+	_ = d.Set("zone_scope", record.ZoneScope)
+	_ = d.Set("dn", record.DN)
+	_ = d.Set("dynamic", record.Dynamic())
+	_ = d.Set("timestamp", "")
+	if !record.Timestamp.IsZero() {
+		_ = d.Set("timestamp", record.Timestamp.Format(time.RFC3339))
+	}
+	_ = d.Set("fqdn", recordFQDN(record.HostName, asciiName(d.Get("zone_name").(string))))
+
+	// The tag is only read when configured, which saves a query for the records of most resources.
+	if d.Get("owner_tag").(string) != "" {
+		owner, err := record.GetOwnerTag(ctx, conf)
+		if err != nil {
+			return diag.Errorf("error while reading owner tag of record with id %q: %s", d.Id(), err)
+		}
+		_ = d.Set("owner_tag", owner)
+	}
+
+	// Only the rules of the configured principals are read, as the rules of other principals are not managed.
+	if configured := d.Get("access_rule").(*schema.Set).List(); len(configured) > 0 && record.DN != "" {
+		principals := make([]string, 0, len(configured))
+		for _, v := range configured {
+			principals = append(principals, v.(map[string]interface{})["principal"].(string))
+		}
+		rules, err := dnshelper.GetRecordAccessRules(ctx, conf, record.DN, principals)
+		if err != nil {
+			return diag.Errorf("error while reading the access rules of %q: %s", record.DN, err)
+		}
+		_ = d.Set("access_rule", readAccessRules(configured, rules))
+	}
 
 	return nil
 }
 
 func resourceDNSRecordUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	record, err := dnshelper.NewDNSRecordFromResource(d)
+	// dynamic is computed, so it holds the value read when refreshing rather than a planned value.
+	if d.Get("require_static").(bool) && d.Get("dynamic").(bool) {
+		return diag.Errorf("records with id %q were registered by dynamic update and require_static is set", d.Id())
+	}
+	if err := checkEmptyRecords(d); err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, commands := dnshelper.WithCommandLog(ctx)
+	if d.Get("create_only").(bool) {
+		_ = d.Set("last_commands", commands.Commands())
+		return resourceDNSRecordRead(ctx, d, meta)
+	}
+	conf := recordConf(d, meta)
+	record, err := dnshelper.NewDNSRecordFromResource(conf, d)
 	if err != nil {
 		return diag.Errorf("error when mapping input data: %s", err)
 	}
-	keys := []string{"records"}
+	keys := []string{"records", "ttl"}
 	changes := make(map[string]interface{})
 	for _, key := range keys {
 		if d.HasChange(key) {
 			changes[key] = d.Get(key)
 		}
 	}
+	// Enabling ordered_records may require reordering records that are otherwise unchanged, and enabling the records
+	// adds them again.
+	if d.HasChange("ordered_records") || d.HasChange("enabled") {
+		changes["records"] = d.Get("records")
+	}
+	if !d.Get("enabled").(bool) {
+		// Disabled records are only removed from the server, and changes to them are applied once enabled.
+		changes = make(map[string]interface{})
+		if d.HasChange("enabled") {
+			changes["records"] = enabledRecords(d)
+		}
+	}
+	if d.Get("append_only").(bool) {
+		previous, _ := d.GetChange("records")
+		changes["previous_records"] = previous
+	}
 
-	err = record.Update(ctx, meta.(*config.ProviderConf), changes)
+	check, diags := startSerialCheck(ctx, conf, record)
+	if diags.HasError() {
+		return diags
+	}
+	err = record.Update(ctx, conf, changes)
 	if err != nil {
 		return diag.Errorf("error while updating record with id %q: %s", d.Id(), err)
 	}
-	return resourceDNSRecordRead(ctx, d, meta)
+	if d.HasChange("owner_tag") {
+		if diags := setOwnerTag(ctx, d, conf, record); diags != nil {
+			return diags
+		}
+	}
+	if diags := setAccessRules(ctx, d, conf); diags != nil {
+		return diags
+	}
+	_ = d.Set("last_commands", commands.Commands())
+	diags = append(ptrRecordsSkipped(record), waitPostWrite(ctx, conf, len(commands.Commands()) > 0)...)
+	diags = append(diags, verifySerial(ctx, conf, check, commands)...)
+	return append(diags, resourceDNSRecordRead(ctx, d, meta)...)
 }
 
 func resourceDNSRecordDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	if d.Id() == "" {
 		return nil
 	}
-	record, err := dnshelper.NewDNSRecordFromResource(d)
+	// Records created with create_only are left on the server, and only removed from the state.
+	if d.Get("create_only").(bool) {
+		return nil
+	}
+	// The NS records at the zone apex are left on the server, as the zone cannot be delegated to without them.
+	if dnshelper.IsProtectedRecord(d.Get("name").(string), d.Get("type").(string)) {
+		return protectedRecordsLeftInPlace(d.Get("type").(string), d.Get("zone_name").(string))
+	}
+	// Disabled records were not found on the server when last refreshed, so there is nothing to remove.
+	if !d.Get("enabled").(bool) {
+		return nil
+	}
+	conf := recordConf(d, meta)
+	record, err := dnshelper.NewDNSRecordFromResource(conf, d)
 	if err != nil {
 		return diag.Errorf("error when mapping input data: %s", err)
 	}
 
-	err = record.Delete(meta.(*config.ProviderConf))
+	err = record.Delete(ctx, conf)
 	if err != nil {
 		return diag.Errorf("error while deleting a record object with id %q: %s", d.Id(), err)
 	}
 
+	if d.Get("owner_tag").(string) != "" {
+		err = record.SetOwnerTag(ctx, conf, "")
+		if err != nil {
+			return diag.Errorf("error while removing owner tag of record with id %q: %s", d.Id(), err)
+		}
+	}
+
+	return nil
+}
+
+// protectedRecordsLeftInPlace warns that the protected records of zoneName were removed from the state only.
+func protectedRecordsLeftInPlace(recordType, zoneName string) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("%s records at the apex of zone %q left in place", recordType, zoneName),
+		Detail:   "The zone does not work without them, so they were only removed from the Terraform state. Remove them from the zone by other means if that was intended.",
+	}}
+}
+
+// ptrRecordsSkipped returns the warning for the addresses of record added without PTR records, as their reverse zone
+// does not exist and missing_ptr_zone is warn.
+func ptrRecordsSkipped(record *dnshelper.Record) diag.Diagnostics {
+	if len(record.SkippedPtrAddresses) == 0 {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("PTR records of %s not created", strings.Join(record.SkippedPtrAddresses, ", ")),
+		Detail:   "No reverse zone on the DNS server covers the addresses, so they were added without PTR records. The PTR records are not added when the reverse zone is created later. Remove the addresses from records and add them again once it exists, or add the PTR records by other means.",
+	}}
+}
+
+// recordConf returns the provider configuration managing the records of a windns_record resource, on its dns_server
+// when set.
+func recordConf(d interface{ Get(string) any }, meta any) *config.ProviderConf {
+	return meta.(*config.ProviderConf).ForDnsServer(d.Get("dns_server").(string))
+}
+
+// startSerialCheck reads the serial number of the zone of record before changing its records, when the provider's
+// verify_serial is set. It returns nil otherwise, or for records in a virtualization instance, whose SOA record
+// cannot be read, or in a zone scope, which has an SOA record of its own.
+func startSerialCheck(ctx context.Context, conf *config.ProviderConf, record *dnshelper.Record) (*dnshelper.SerialCheck, diag.Diagnostics) {
+	if conf.Settings.VerifySerial == "" || record.VirtualizationInstance != "" || record.ZoneScope != "" {
+		return nil, nil
+	}
+	check, err := dnshelper.StartSerialCheck(ctx, conf, record.ZoneName)
+	if err != nil {
+		return nil, diag.Errorf("error while reading the serial number of zone %q: %s", record.ZoneName, err)
+	}
+	return check, nil
+}
+
+// verifySerial verifies that the serial number of the zone advanced when commands changed its records, reporting a
+// serial number that did not as a warning or an error, as chosen by the provider's verify_serial.
+func verifySerial(ctx context.Context, conf *config.ProviderConf, check *dnshelper.SerialCheck, commands *dnshelper.CommandLog) diag.Diagnostics {
+	if check == nil || len(commands.Commands()) == 0 {
+		return nil
+	}
+	err := check.Verify(ctx, conf)
+	if err == nil {
+		return nil
+	}
+	severity := diag.Warning
+	if conf.Settings.VerifySerial == dnshelper.VerifySerialError {
+		severity = diag.Error
+	}
+	return diag.Diagnostics{{
+		Severity: severity,
+		Summary:  "zone serial number did not advance",
+		Detail:   err.Error(),
+	}}
+}
+
+// waitPostWrite waits for the provider's post_write_delay when records were written, so checks run right after the
+// apply find the change propagated. A wait cut short by the timeout of the operation is reported as a warning, as the
+// records were written.
+func waitPostWrite(ctx context.Context, conf *config.ProviderConf, written bool) diag.Diagnostics {
+	delay := conf.Settings.PostWriteDelay
+	if !written || delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "post_write_delay cut short",
+			Detail:   fmt.Sprintf("The wait of %s after writing the records was interrupted: %s", delay, ctx.Err()),
+		}}
+	}
+}
+
+// setOwnerTag tags record with the configured owner_tag, removing the tag when it is unset.
+func setOwnerTag(ctx context.Context, d *schema.ResourceData, conf *config.ProviderConf, record *dnshelper.Record) diag.Diagnostics {
+	err := record.SetOwnerTag(ctx, conf, d.Get("owner_tag").(string))
+	if err != nil {
+		return diag.Errorf("error while tagging %s records of %q in zone %q with their owner: %s", record.RecordType, record.HostName, record.ZoneName, err)
+	}
+	return nil
+}
+
+// accessRules returns the rules of the access_rule blocks in rules.
+func accessRules(rules *schema.Set) ([]dnshelper.RecordAccessRule, error) {
+	result := make([]dnshelper.RecordAccessRule, 0, rules.Len())
+	for _, v := range rules.List() {
+		block := v.(map[string]interface{})
+		mask, err := dnshelper.ActiveDirectoryRightsMask(listToStringSlice(block["rights"].(*schema.Set).List()))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, dnshelper.RecordAccessRule{Principal: block["principal"].(string), Rights: mask})
+	}
+	return result, nil
+}
+
+// setAccessRules grants the rights of access_rule on the node of the records, and removes the rules of principals no
+// longer configured. Records not on the server have no node, so the rules are set once the records are added.
+func setAccessRules(ctx context.Context, d *schema.ResourceData, conf *config.ProviderConf) diag.Diagnostics {
+	old, new := d.GetChange("access_rule")
+	if old.(*schema.Set).Len() == 0 && new.(*schema.Set).Len() == 0 {
+		return nil
+	}
+	rules, err := accessRules(new.(*schema.Set))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var removed []string
+	for _, v := range old.(*schema.Set).List() {
+		principal := v.(map[string]interface{})["principal"].(string)
+		if !slices.ContainsFunc(rules, func(r dnshelper.RecordAccessRule) bool { return strings.EqualFold(r.Principal, principal) }) {
+			removed = append(removed, principal)
+		}
+	}
+
+	record, err := dnshelper.GetDNSRecordFromId(ctx, conf, d.Id())
+	if dnshelper.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("error while reading record with id %q: %s", d.Id(), err)
+	}
+	if record.DN == "" {
+		return diag.Errorf("the records with id %q have no node in Active Directory, access_rule requires an AD-integrated zone", d.Id())
+	}
+	err = dnshelper.SetRecordAccessRules(ctx, conf, record.DN, rules, removed)
+	if err != nil {
+		return diag.Errorf("error while setting the access rules of %q: %s", record.DN, err)
+	}
+	return nil
+}
+
+// readAccessRules returns the access_rule blocks of configured as granted on the server, given the rules read for
+// their principals. Blocks of principals granted no rights are left out, which plans to grant them again, and blocks
+// granting other rights than configured get the rights read.
+func readAccessRules(configured []interface{}, read []dnshelper.RecordAccessRule) []interface{} {
+	result := make([]interface{}, 0, len(configured))
+	for _, v := range configured {
+		block := v.(map[string]interface{})
+		principal := block["principal"].(string)
+		i := slices.IndexFunc(read, func(r dnshelper.RecordAccessRule) bool { return strings.EqualFold(r.Principal, principal) })
+		if i == -1 || read[i].Rights == 0 {
+			continue
+		}
+		rights := listToStringSlice(block["rights"].(*schema.Set).List())
+		if mask, err := dnshelper.ActiveDirectoryRightsMask(rights); err != nil || mask != read[i].Rights {
+			rights = dnshelper.ActiveDirectoryRightsNames(read[i].Rights)
+		}
+		result = append(result, map[string]interface{}{"principal": principal, "rights": rights})
+	}
+	return result
+}
+
+// customizeDiffFQDN computes the fqdn attribute at plan time so it can be referenced before apply.
+func customizeDiffFQDN(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if !d.NewValueKnown("name") || !d.NewValueKnown("zone_name") {
+		return d.SetNewComputed("fqdn")
+	}
+
+	// meta is nil when the provider is not configured, and OwnerName then leaves the name as is.
+	conf, _ := meta.(*config.ProviderConf)
+	fqdn := recordFQDN(dnshelper.OwnerName(conf, asciiName(d.Get("name").(string))), asciiName(d.Get("zone_name").(string)))
+	if strings.EqualFold(d.Get("fqdn").(string), fqdn) {
+		return nil
+	}
+	return d.SetNew("fqdn", fqdn)
+}
+
+// customizeDiffDefaultZone plans the provider's default_zone as the zone of records not setting zone_name. Records
+// following the default zone are replaced when it changes.
+func customizeDiffDefaultZone(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if raw := d.GetRawConfig(); raw.IsNull() || !raw.GetAttr("zone_name").IsNull() || meta == nil {
+		return nil
+	}
+	defaultZone := meta.(*config.ProviderConf).Settings.DefaultZone
+	if defaultZone == "" {
+		return fmt.Errorf("zone_name must be set, as the provider has no default_zone")
+	}
+	if strings.EqualFold(d.Get("zone_name").(string), defaultZone) {
+		return nil
+	}
+	return d.SetNew("zone_name", defaultZone)
+}
+
+// checkEmptyRecords rejects an empty records list unless allow_empty is set. The NS records at the zone apex can never
+// be emptied.
+func checkEmptyRecords(d interface{ Get(string) any }) error {
+	if len(d.Get("records").([]interface{})) > 0 {
+		return nil
+	}
+	if dnshelper.IsProtectedRecord(d.Get("name").(string), d.Get("type").(string)) {
+		return fmt.Errorf("records is empty, which would remove all %s records at the apex of zone %q. The zone does not work without them, so they cannot be removed, even with allow_empty", d.Get("type"), d.Get("zone_name"))
+	}
+	if d.Get("allow_empty").(bool) {
+		return nil
+	}
+	return fmt.Errorf("records is empty, which would remove all %s records of %q in zone %q. Set allow_empty to allow removing them", d.Get("type"), d.Get("name"), d.Get("zone_name"))
+}
+
+// customizeDiffEmptyRecords rejects an empty records list at plan time unless allow_empty is set. Lists whose values
+// are unknown when planning are checked again when applying.
+func customizeDiffEmptyRecords(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if !d.NewValueKnown("records") {
+		return nil
+	}
+	return checkEmptyRecords(d)
+}
+
+// enabledRecords returns the records to keep on the server, which are none while the records are disabled.
+func enabledRecords(d *schema.ResourceData) []interface{} {
+	if !d.Get("enabled").(bool) {
+		return []interface{}{}
+	}
+	return d.Get("records").([]interface{})
+}
+
+// hasManagedRecords reports whether record holds any of the records managed by the resource, which with append_only are
+// only the configured records.
+func hasManagedRecords(d *schema.ResourceData, record *dnshelper.Record) bool {
+	if !d.Get("append_only").(bool) {
+		return len(record.Records) > 0
+	}
+	records := dnshelper.RecordValues(record.RecordType, record.Records, d.Get("explicit_txt_segments").(bool))
+	return len(dnshelper.FilterRecordData(record.RecordType, records, configuredRecords(d))) > 0
+}
+
+// customizeDiffEnabled rejects disabling records that are never removed from the server.
+func customizeDiffEnabled(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if d.Get("enabled").(bool) {
+		return nil
+	}
+	if d.Get("create_only").(bool) {
+		return fmt.Errorf("enabled cannot be false with create_only, as records created with create_only are never removed from the server")
+	}
+	if dnshelper.IsProtectedRecord(d.Get("name").(string), d.Get("type").(string)) {
+		return fmt.Errorf("the %s records at the apex of zone %q cannot be disabled, as the zone does not work without them", d.Get("type"), d.Get("zone_name"))
+	}
+	return nil
+}
+
+// hasTTL reports whether d holds a TTL, rather than no TTL or the 0 read for records with different TTLs.
+func hasTTL(d interface{ Get(string) any }) bool {
+	ttl, err := dnshelper.ParseTTL(d.Get("ttl").(string))
+	return err == nil && ttl != 0
+}
+
+// customizeDiffTTL rejects plans for records whose TTLs differ on the server, as read into a ttl of 0, unless ttl is
+// configured to give them all the same TTL. Leaving ttl unset would otherwise keep the TTLs mixed without a diff.
+func customizeDiffTTL(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if d.Id() == "" || hasTTL(d) {
+		return nil
+	}
+	if raw := d.GetRawConfig(); raw.IsNull() || !raw.GetAttr("ttl").IsNull() {
+		return nil
+	}
+	return fmt.Errorf("the %s records of %q in zone %q have different TTLs on the server, while all records of a resource share one TTL. Set ttl to give them the same TTL", d.Get("type"), d.Get("name"), d.Get("zone_name"))
+}
+
+// customizeDiffLastCommands plans last_commands to change along with the attributes updated by running commands.
+func customizeDiffLastCommands(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if d.Id() != "" && !d.Get("create_only").(bool) && d.HasChanges("records", "ttl", "ordered_records", "owner_tag", "enabled") {
+		return d.SetNewComputed("last_commands")
+	}
+	return nil
+}
+
+// customizeDiffPtrZone verifies at plan time that ptr_zone_name covers every address in records.
+func customizeDiffPtrZone(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	ptrZoneName := d.Get("ptr_zone_name").(string)
+	if ptrZoneName == "" || !d.NewValueKnown("ptr_zone_name") {
+		return nil
+	}
+
+	recordType := strings.ToUpper(d.Get("type").(string))
+	if !d.Get("create_ptr").(bool) || (recordType != dnshelper.RecordTypeA && recordType != dnshelper.RecordTypeAAAA) {
+		return fmt.Errorf("ptr_zone_name can only be set on A or AAAA records with create_ptr enabled")
+	}
+
+	if !d.NewValueKnown("records") {
+		return nil
+	}
+	for _, v := range d.Get("records").([]interface{}) {
+		if _, err := dnshelper.ReverseNameInZone(v.(string), ptrZoneName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// customizeDiffRecordZone verifies at plan time that the type and name of the records fit the kind of zone, catching
+// e.g. A records in a reverse lookup zone, which the server would only reject with an obscure error when applying.
+func customizeDiffRecordZone(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if !d.NewValueKnown("zone_name") || !d.NewValueKnown("name") || !d.NewValueKnown("type") {
+		return nil
+	}
+	return dnshelper.CheckRecordZone(asciiName(d.Get("zone_name").(string)), asciiName(d.Get("name").(string)), d.Get("type").(string))
+}
+
+// customizeDiffCNAME rejects CNAME records that are invalid regardless of the other records in the zone.
+func customizeDiffCNAME(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if !strings.EqualFold(d.Get("type").(string), dnshelper.RecordTypeCNAME) {
+		return nil
+	}
+
+	if d.NewValueKnown("name") && dnshelper.IsApexName(d.Get("name").(string)) {
+		return fmt.Errorf("a CNAME record cannot be created at the zone apex, as the apex always has SOA and NS records and a CNAME cannot coexist with other records. Use A or AAAA records at the apex instead")
+	}
+
+	if d.NewValueKnown("records") && len(d.Get("records").([]interface{})) > 1 {
+		return fmt.Errorf("a CNAME record can only have a single value, as a name can only be an alias of one other name. Use A or AAAA records to give a name several addresses")
+	}
+	return nil
+}
+
+// customizeDiffOwnerTag rejects owner_tag on wildcard records, as the wildcard label must be the leading label of the
+// name of the tag too.
+func customizeDiffOwnerTag(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if d.Get("owner_tag").(string) == "" || !d.NewValueKnown("name") {
+		return nil
+	}
+	name := d.Get("name").(string)
+	if name == dnshelper.WildcardLabel || strings.HasPrefix(name, dnshelper.WildcardLabel+".") {
+		return fmt.Errorf("owner_tag cannot be set on wildcard records, as the name of the tag would not be valid")
+	}
+	return nil
+}
+
+// dnssecRecordTypes are the record types the server adds to every name in a signed zone.
+var dnssecRecordTypes = []string{"RRSIG", "NSEC", "NSEC3"}
+
+// customizeDiffCNAMEConflict verifies at plan time that new records do not share their name with a CNAME record,
+// which is not valid DNS. The check is skipped with skip_create_precheck, like the check for existing records.
+func customizeDiffCNAMEConflict(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if d.Id() != "" || meta == nil || !d.NewValueKnown("zone_name") || !d.NewValueKnown("name") || !d.NewValueKnown("type") {
+		return nil
+	}
+	conf := recordConf(d, meta)
+	if conf.Settings.SkipCreatePrecheck || d.Get("virtualization_instance").(string) != "" || d.Get("zone_scope").(string) != "" {
+		return nil
+	}
+
+	zoneName, err := dnshelper.SanitizeZoneName(d.Get("zone_name").(string))
+	if err != nil {
+		return err
+	}
+	hostName := dnshelper.ApexName
+	if !dnshelper.IsApexName(d.Get("name").(string)) {
+		hostName, err = dnshelper.SanitizeHostName(d.Get("name").(string))
+		if err != nil {
+			return err
+		}
+		hostName = dnshelper.OwnerName(conf, hostName)
+	}
+
+	types, err := dnshelper.GetDNSRecordTypes(ctx, conf, zoneName, hostName)
+	if err != nil {
+		if dnshelper.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error while checking the records of %q in zone %q: %s", hostName, zoneName, err)
+	}
+
+	recordType := strings.ToUpper(d.Get("type").(string))
+	for _, existing := range types {
+		// Signed zones have DNSSEC records alongside every other record.
+		if existing == recordType || slices.Contains(dnssecRecordTypes, existing) {
+			continue
+		}
+		if recordType == dnshelper.RecordTypeCNAME || existing == dnshelper.RecordTypeCNAME {
+			return fmt.Errorf("%q in zone %q already has %s records, and a CNAME record cannot coexist with records of other types. Remove the conflicting records or use another name", hostName, zoneName, existing)
+		}
+	}
+	return nil
+}
+
+// checkZoneExists verifies that the zone of record exists on the server before its records are added, failing with a
+// clearer error than the server's. It is not checked when planning, so that a zone and its records can be created in
+// the same run.
+func checkZoneExists(ctx context.Context, conf *config.ProviderConf, record *dnshelper.Record) error {
+	if record.VirtualizationInstance != "" {
+		return nil
+	}
+	exists, err := dnshelper.ZoneExists(ctx, conf, record.ZoneName)
+	if err != nil {
+		return fmt.Errorf("error while checking that zone %q exists: %s", record.ZoneName, err)
+	}
+	if !exists {
+		server := conf.Settings.DnsServer
+		if server == "" {
+			server = conf.Settings.SshHostname
+		}
+		return fmt.Errorf("zone %q not found on server %q", record.ZoneName, server)
+	}
 	return nil
 }
+
+// importDNSServerPattern matches an import ID followed by `@` and the hostname of the DNS server holding the records.
+// The `@` of the zone apex is never followed by a hostname ending the ID, so it is not taken for the separator.
+var importDNSServerPattern = regexp.MustCompile(`^(.+)@([a-zA-Z0-9][a-zA-Z0-9.\-]*)$`)
+
+// splitImportDNSServer splits an import ID written as `<id>@<dns_server>` into the ID and the DNS server. The server
+// is empty for IDs without one.
+func splitImportDNSServer(id string) (string, string) {
+	if m := importDNSServerPattern.FindStringSubmatch(id); m != nil {
+		return m[1], m[2]
+	}
+	return id, ""
+}
+
+// resourceDNSRecordImport accepts either the full resource ID, a short <name>_<zone_name> form or the
+// distinguished name of the record's node. For the latter two the record type is discovered from the server. Each may
+// end with @<dns_server>, see splitImportDNSServer.
+func resourceDNSRecordImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	_ = d.Set("force_overwrite", false)
+	_ = d.Set("create_only", false)
+	_ = d.Set("allow_empty", false)
+	_ = d.Set("trim_whitespace", true)
+	_ = d.Set("missing_ptr_zone", dnshelper.MissingPtrZoneError)
+
+	// Records on another DNS server than the provider's are imported with the server appended to the ID.
+	if id, dnsServer := splitImportDNSServer(d.Id()); dnsServer != "" {
+		d.SetId(id)
+		_ = d.Set("dns_server", dnsServer)
+	}
+
+	var hostName, zoneName string
+	if dnshelper.IsRecordDN(d.Id()) {
+		var err error
+		hostName, zoneName, err = dnshelper.ParseRecordDN(d.Id())
+		if err != nil {
+			return nil, err
+		}
+	} else if r, err := dnshelper.ParseRecordID(d.Id()); err == nil {
+		if strings.EqualFold(r.RecordType, dnshelper.RecordTypeSOA) {
+			return nil, fmt.Errorf("cannot import %q: SOA records are managed with windns_zone_soa", d.Id())
+		}
+		return []*schema.ResourceData{d}, nil
+	} else {
+		hostName, zoneName, err = dnshelper.SplitNameID(d.Id())
+		if err != nil {
+			return nil, fmt.Errorf("cannot import %q: expected a resource ID, <name>_<zone_name> or the distinguished name of a record", d.Id())
+		}
+	}
+
+	types, err := dnshelper.GetDNSRecordTypes(ctx, recordConf(d, meta), zoneName, hostName)
+	if err != nil {
+		return nil, fmt.Errorf("error while discovering record types for %q: %s", d.Id(), err)
+	}
+
+	if len(types) == 0 {
+		return nil, fmt.Errorf("no records found for %q", d.Id())
+	}
+
+	// The SOA record of the apex is managed with windns_zone_soa.
+	types = slices.DeleteFunc(types, func(recordType string) bool { return recordType == dnshelper.RecordTypeSOA })
+	if len(types) == 0 {
+		return nil, fmt.Errorf("%q only has an SOA record, which is managed with windns_zone_soa", d.Id())
+	}
+
+	if len(types) > 1 {
+		if slices.Contains(types, dnshelper.RecordTypeCNAME) {
+			return nil, fmt.Errorf("%q has a CNAME record alongside other record types (%s), which is not valid DNS. Resolve the conflict before importing", d.Id(), strings.Join(types, ", "))
+		}
+
+		var ids []string
+		for _, recordType := range types {
+			r := dnshelper.Record{HostName: hostName, ZoneName: zoneName, RecordType: recordType}
+			ids = append(ids, r.Id())
+		}
+		return nil, fmt.Errorf("%q has records of multiple types. Terraform can only import one resource per ID, import each type separately using one of these IDs: %s", d.Id(), strings.Join(ids, ", "))
+	}
+
+	r := dnshelper.Record{HostName: hostName, ZoneName: zoneName, RecordType: types[0]}
+	d.SetId(r.Id())
+	return []*schema.ResourceData{d}, nil
+}
@@ -5,11 +5,16 @@ package provider
 import (
 	"context"
 	"fmt"
+	"os"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/nrkno/terraform-provider-windns/internal/config"
 	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
@@ -22,6 +27,7 @@ Prerequisites for acceptance tests
 	- example.com
 	- 10.10.in-addr.arpa
 	- 8.b.d.0.1.0.0.2.ip6.arpa
+	- 0/26.113.10.10.in-addr.arpa
 - A Windows server with SSH enabled and the Powershell DnsServer module installed.
 	- This could be the same as running the DNS server, or another to jump through.
 */
@@ -44,6 +50,49 @@ resource "windns_record" "r1" {
 }
 `
 
+const testAccResourceDNSRecordConfigClasslessPTR = `
+resource "windns_record" "r1" {
+  name      = "12"
+  zone_name = "0/26.113.10.10.in-addr.arpa"
+  type      = "PTR"
+  records   = ["example-host.example.com."]
+}
+
+resource "windns_record" "r2" {
+  name      = "12.113"
+  zone_name = "10.10.in-addr.arpa"
+  type      = "CNAME"
+  records   = ["12.0/26.113.10.10.in-addr.arpa."]
+}
+`
+
+const testAccResourceDNSRecordConfigMultiplePTR = `
+resource "windns_record" "r1" {
+  name      = "13.113"
+  zone_name = "10.10.in-addr.arpa"
+  type      = "PTR"
+  records   = ["example-host.example.com.", "example-alias.example.com."]
+}
+`
+
+const testAccResourceDNSRecordConfigMultiplePTRReordered = `
+resource "windns_record" "r1" {
+  name      = "13.113"
+  zone_name = "10.10.in-addr.arpa"
+  type      = "PTR"
+  records   = ["Example-Alias.example.com", "example-host.example.com"]
+}
+`
+
+const testAccResourceDNSRecordConfigMultiplePTRRemoved = `
+resource "windns_record" "r1" {
+  name      = "13.113"
+  zone_name = "10.10.in-addr.arpa"
+  type      = "PTR"
+  records   = ["example-host.example.com."]
+}
+`
+
 const testAccResourceDNSRecordConfigBasicA = `
 variable "windns_record_name" {}
 
@@ -90,6 +139,127 @@ resource "windns_record" "r1" {
 }
 `
 
+const testAccResourceDNSRecordConfigLongTXT = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "TXT"
+  records   = [join("", ["v=DKIM1; p=", join("", [for i in range(30) : "0123456789"])])]
+}
+`
+
+const testAccResourceDNSRecordConfigTrimWhitespace = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "TXT"
+  records   = ["v=spf1  include:example.com -all "]
+}
+
+resource "windns_record" "r2" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "A"
+  records   = [" 203.0.113.11"]
+}
+`
+
+const testAccResourceDNSRecordConfigExplicitTXTSegments = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name                  = var.windns_record_name
+  zone_name             = "example.com"
+  type                  = "TXT"
+  records               = ["\"v=DKIM1; k=rsa; \"  \"p=MIGfMA0GCSqGSIb3DQEB\""]
+  explicit_txt_segments = true
+}
+`
+
+const testAccResourceDNSRecordConfigAppendOnly = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name        = var.windns_record_name
+  zone_name   = "example.com"
+  type        = "A"
+  records     = ["203.0.113.11"]
+  append_only = true
+}
+
+resource "windns_record" "r2" {
+  name        = var.windns_record_name
+  zone_name   = "example.com"
+  type        = "A"
+  records     = ["203.0.113.12"]
+  append_only = true
+
+  depends_on = [windns_record.r1]
+}
+`
+
+const testAccResourceDNSRecordConfigAppendOnlyUpdated = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name        = var.windns_record_name
+  zone_name   = "example.com"
+  type        = "A"
+  records     = ["203.0.113.13"]
+  append_only = true
+}
+
+resource "windns_record" "r2" {
+  name        = var.windns_record_name
+  zone_name   = "example.com"
+  type        = "A"
+  records     = ["203.0.113.12"]
+  append_only = true
+
+  depends_on = [windns_record.r1]
+}
+`
+
+const testAccResourceDNSRecordConfigCreateOnly = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name        = var.windns_record_name
+  zone_name   = "example.com"
+  type        = "A"
+  records     = ["203.0.113.11"]
+  create_only = true
+}
+`
+
+const testAccResourceDNSRecordConfigCreateOnlyUpdated = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name        = var.windns_record_name
+  zone_name   = "example.com"
+  type        = "A"
+  records     = ["203.0.113.12"]
+  ttl         = 600
+  create_only = true
+}
+`
+
+const testAccResourceDNSRecordConfigCreateOnlyDisabled = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11"]
+}
+`
+
 const testAccResourceDNSRecordConfigMultiple = `
 variable "windns_record_name" {}
 
@@ -151,263 +321,2377 @@ resource "windns_record" "r1" {
 }
 `
 
-const testAccResourceDNSRecordConfigIllegalCharacter = `
+// testAccResourceDNSRecordConfigTypeChanged changes the type of testAccResourceDNSRecordConfigBasicA, keeping its name.
+const testAccResourceDNSRecordConfigTypeChanged = `
 variable "windns_record_name" {}
 
 resource "windns_record" "r1" {
   name      = var.windns_record_name
-  zone_name = "example.com;"
+  zone_name = "example.com"
   type      = "CNAME"
   records   = ["cname.example.com"]
 }
-`
+`
+
+const testAccResourceDNSRecordConfigCNAMEWithDot = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "CNAME"
+  records   = ["cname.example.com."]
+}
+`
+
+const testAccResourceDNSRecordConfigApexA = `
+resource "windns_record" "r1" {
+  name      = "@"
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.10"]
+}
+`
+
+const testAccResourceDNSRecordConfigApexTXT = `
+resource "windns_record" "r1" {
+  name      = ""
+  zone_name = "example.com"
+  type      = "TXT"
+  records   = ["v=spf1 -all"]
+}
+`
+
+const testAccResourceDNSRecordConfigAlreadyExists = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11"]
+}
+
+resource "windns_record" "r2" {
+  name       = var.windns_record_name
+  zone_name  = "example.com"
+  type       = "A"
+  records    = ["203.0.113.12"]
+  depends_on = [windns_record.r1]
+}
+`
+
+const testAccResourceDNSRecordConfigAFSDB = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "AFSDB"
+  records   = ["1 afsdb.example.com"]
+}
+`
+
+const testAccResourceDNSRecordConfigRP = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "RP"
+  records   = ["admin.example.com txt.example.com"]
+}
+`
+
+const testAccResourceDNSRecordConfigRT = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "RT"
+  records   = ["10 relay.example.com"]
+}
+`
+
+const testAccResourceDNSRecordConfigZoneScope = `
+variable "windns_record_name" {}
+variable "windns_zone_scope" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11"]
+}
+
+resource "windns_record" "r2" {
+  name       = var.windns_record_name
+  zone_name  = "example.com"
+  type       = "A"
+  records    = ["198.51.100.11"]
+  zone_scope = var.windns_zone_scope
+}
+`
+
+const testAccResourceDNSRecordConfigVirtualizationInstance = `
+variable "windns_record_name" {}
+variable "windns_virtualization_instance" {}
+
+resource "windns_record" "r1" {
+  name                    = var.windns_record_name
+  zone_name               = "example.com"
+  type                    = "A"
+  records                 = ["203.0.113.11"]
+  virtualization_instance = var.windns_virtualization_instance
+}
+`
+
+const testAccResourceDNSRecordConfigDNSServer = `
+variable "windns_record_name" {}
+variable "windns_dns_server" {}
+
+resource "windns_record" "r1" {
+  name       = var.windns_record_name
+  zone_name  = "example.com"
+  type       = "A"
+  records    = ["203.0.113.11"]
+  dns_server = var.windns_dns_server
+}
+`
+
+const testAccResourceDNSRecordConfigDNSServerUpdated = `
+variable "windns_record_name" {}
+variable "windns_dns_server" {}
+
+resource "windns_record" "r1" {
+  name       = var.windns_record_name
+  zone_name  = "example.com"
+  type       = "A"
+  records    = ["203.0.113.11", "203.0.113.12"]
+  dns_server = var.windns_dns_server
+}
+`
+
+const testAccResourceDNSRecordConfigIDN = `
+resource "windns_record" "r1" {
+  name      = "bücher-tf"
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11"]
+}
+`
+
+const testAccResourceDNSRecordConfigIDNALabel = `
+resource "windns_record" "r1" {
+  name      = "xn--bcher-tf-65a"
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11"]
+}
+`
+
+const testAccResourceDNSRecordConfigOrdered = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name            = var.windns_record_name
+  zone_name       = "example.com"
+  type            = "A"
+  records         = ["203.0.113.12", "203.0.113.11"]
+  ordered_records = true
+}
+`
+
+const testAccResourceDNSRecordConfigOrderedUpdated = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name            = var.windns_record_name
+  zone_name       = "example.com"
+  type            = "A"
+  records         = ["203.0.113.11", "203.0.113.12"]
+  ordered_records = true
+}
+`
+
+const testAccResourceDNSRecordConfigWKS = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "WKS"
+  records   = ["203.0.113.11 tcp smtp ftp"]
+}
+`
+
+const testAccResourceDNSRecordConfigDS = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "DS"
+  records   = ["60485 8 2 d4b7d520e7bb5f0f67674a0cceb1e3e0614b93c4f9e99b8383f6a1e4469da50a"]
+}
+`
+
+const testAccResourceDNSRecordConfigATMA = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "ATMA"
+  records   = ["E164 358400123456"]
+}
+`
+
+// testAccResourceDNSRecordConfigCERT is formatted with the certificate data of the record.
+const testAccResourceDNSRecordConfigCERT = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "CERT"
+  records   = ["%s"]
+}
+`
+
+const testAccResourceDNSRecordConfigDHCID = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "DHCID"
+  records   = ["AAIBY2/AuCccgoJbsaxcQc9TUapptP69lOjxfNuVAA2kjEA="]
+}
+`
+
+// testAccCERTRecordData holds a self-signed X.509 certificate for an S/MIME mailbox, of the size published in CERT records.
+const testAccCERTRecordData = "1 0 8 MIIDYTCCAkmgAwIBAgIUJfE3LBgytCCo/FWZwwCewW4tbvYwDQYJKoZIhvcNAQELBQAwQDEZMBcGA1UEAwwQbWFpbC5leGFtcGxlLmNvbTEjMCEGCSqGSIb3DQEJARYUc2VjdXJpdHlAZXhhbXBsZS5jb20wHhcNMjYxMDE2MTA1NTM0WhcNMzYxMDEzMTA1NTM0WjBAMRkwFwYDVQQDDBBtYWlsLmV4YW1wbGUuY29tMSMwIQYJKoZIhvcNAQkBFhRzZWN1cml0eUBleGFtcGxlLmNvbTCCASIwDQYJKoZIhvcNAQEBBQADggEPADCCAQoCggEBAK5XWeGDsaKY/pGYfCDDPr3ROCkBZL/REsX+7X8k9JlqRq3GxIb2SWJb6B+z4B0p4pQojyQPj+an1TXmQswFzmhnR14VZsjm5u7SzKGAbFf8sQLPPlXnxt+0j9lLJNQjIfv2MPmuBXJqtNAd/Y56NJKPUCPQXhg2kfXNRrsA1QZo8NOW+M5JKGdc1bhJbsbAXdxHLgdoxMaJhR3dpqwDoJfgpN94LAArjv1CzJWgNJCZbzynCfuUFSOAcx8MST5mc2v358uktKeNX+1pYjstII266EGsqKpZmXsbu5ahHR+7RHp5xMASHHW+f8hJhqld4CRcKgMD5l0RDKIcGq3GOsUCAwEAAaNTMFEwHQYDVR0OBBYEFBxU2qFyrbjjEpZAssdGWBmIgGjAMB8GA1UdIwQYMBaAFBxU2qFyrbjjEpZAssdGWBmIgGjAMA8GA1UdEwEB/wQFMAMBAf8wDQYJKoZIhvcNAQELBQADggEBAJiDfBoX0zjeapjvH8tLC3bgwPmwBmTVE5pipFXRN4injT49JkqAAgdtOP+ZRwl1SohVFN4LVuV401J+Akhfpl7vGUOBuYJ17SpCxKKMKxhOJBHNItTFN7MYuFXvaijezp0EM3XsYiPr2dM5EQqT7elxIoxYTUALr9O33wMjA08ucYUwdjIQomEhDWHhU6IdaAzvG0lWwxjccwVVVR3D2GWOLQe/0XS+z4IdwW8pVF3wHOD/EmZuJQ934P2PBKw16GcioOZ+AMoUowXuxfJNT82+TAPIxpGFLMSRcbmxt8tc3ekIdYKlJDXtMTtZpJ4bN+4aFNYVHuOQw1nFB+KgbxY="
+
+const testAccResourceDNSRecordConfigDelegation = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "NS"
+  records   = ["ns1.${var.windns_record_name}.example.com."]
+}
+
+resource "windns_record" "r2" {
+  name      = "ns1.${var.windns_record_name}"
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["%s"]
+}
+`
+
+const testAccResourceDNSRecordConfigAllowEmpty = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name        = var.windns_record_name
+  zone_name   = "example.com"
+  type        = "A"
+  records     = %s
+  allow_empty = true
+}
+`
+
+// testAccResourceDNSRecordConfigEnabled is formatted with the value of enabled.
+const testAccResourceDNSRecordConfigEnabled = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11", "203.0.113.12"]
+  enabled   = %t
+}
+`
+
+const testAccResourceDNSRecordConfigEmpty = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "A"
+  records   = []
+}
+`
+
+const testAccResourceDNSRecordConfigSOA = `
+resource "windns_record" "r1" {
+  name      = "@"
+  zone_name = "example.com"
+  type      = "SOA"
+  records   = ["dc01.example.com. hostmaster.example.com."]
+}
+`
+
+const testAccResourceDNSRecordConfigApexNSEmpty = `
+resource "windns_record" "r1" {
+  name        = "@"
+  zone_name   = "example.com"
+  type        = "NS"
+  records     = []
+  allow_empty = true
+}
+`
+
+const testAccResourceDNSRecordConfigAInReverseZone = `
+resource "windns_record" "r1" {
+  name      = "12.113"
+  zone_name = "10.10.in-addr.arpa"
+  type      = "A"
+  records   = ["10.10.113.12"]
+}
+`
+
+const testAccResourceDNSRecordConfigPTRForwardName = `
+resource "windns_record" "r1" {
+  name      = "example-host"
+  zone_name = "10.10.in-addr.arpa"
+  type      = "PTR"
+  records   = ["example-host.example.com."]
+}
+`
+
+const testAccResourceDNSRecordConfigOwnerTag = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11"]
+  owner_tag = "%s"
+}
+`
+
+const testAccResourceDNSRecordConfigX25 = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "X25"
+  records   = ["311061700956"]
+}
+`
+
+const testAccResourceDNSRecordConfigISDN = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "ISDN"
+  records   = ["150862028003217 004", "150862028003218"]
+}
+`
+
+const testAccResourceDNSRecordConfigMINFO = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "MINFO"
+  records   = ["admin.example.com errors.example.com."]
+}
+`
+
+const testAccResourceDNSRecordConfigPtrZone = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name          = var.windns_record_name
+  zone_name     = "example.com"
+  type          = "A"
+  records       = ["10.10.113.13"]
+  create_ptr    = true
+  ptr_zone_name = "10.10.in-addr.arpa"
+}
+`
+
+const testAccResourceDNSRecordConfigManagePtrLifecycle = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name                 = var.windns_record_name
+  zone_name            = "example.com"
+  type                 = "A"
+  records              = ["10.10.113.14"]
+  create_ptr           = true
+  manage_ptr_lifecycle = true
+}
+`
+
+const testAccResourceDNSRecordConfigPtrZoneUncovered = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name          = var.windns_record_name
+  zone_name     = "example.com"
+  type          = "A"
+  records       = ["203.0.113.11"]
+  create_ptr    = true
+  ptr_zone_name = "10.10.in-addr.arpa"
+}
+`
+
+const testAccResourceDNSRecordConfigMissingPtrZone = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name       = var.windns_record_name
+  zone_name  = "example.com"
+  type       = "A"
+  records    = ["198.51.100.11"]
+  create_ptr = true
+}
+`
+
+const testAccResourceDNSRecordConfigMissingPtrZoneWarn = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name             = var.windns_record_name
+  zone_name        = "example.com"
+  type             = "A"
+  records          = ["198.51.100.11"]
+  create_ptr       = true
+  missing_ptr_zone = "warn"
+}
+`
+
+// S-1-5-20 is the well-known SID of the Network Service account, which exists in every domain.
+const testAccResourceDNSRecordConfigAccessRule = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11"]
+
+  access_rule {
+    principal = "S-1-5-20"
+    rights    = ["GenericRead"]
+  }
+}
+`
+
+const testAccResourceDNSRecordConfigAccessRuleUpdated = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11"]
+
+  access_rule {
+    principal = "S-1-5-20"
+    rights    = ["GenericRead", "WriteProperty"]
+  }
+}
+`
+
+const testAccResourceDNSRecordConfigAccessRuleRemoved = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11"]
+}
+`
+
+const testAccResourceDNSRecordConfigIllegalCharacter = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com;"
+  type      = "CNAME"
+  records   = ["cname.example.com"]
+}
+`
+
+const testAccResourceDNSRecordConfigMissingZone = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "missing.example.com"
+  type      = "A"
+  records   = ["203.0.113.11"]
+}
+`
+
+const testAccResourceDNSRecordConfigMultipleCNAME = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "CNAME"
+  records   = ["a.example.com", "b.example.com"]
+}
+`
+
+const testAccResourceDNSRecordConfigCNAMEConflict = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name       = var.windns_record_name
+  zone_name  = "example.com"
+  type       = "A"
+  records    = ["203.0.113.11", "203.0.113.12"]
+  create_ptr = true
+}
+
+resource "windns_record" "r2" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "CNAME"
+  records   = ["a.example.com"]
+}
+`
+
+const testAccResourceDNSRecordConfigTTL = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11"]
+  ttl       = 300
+}
+`
+
+const testAccResourceDNSRecordConfigTTLUpdated = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11", "203.0.113.12"]
+  ttl       = 3600
+}
+`
+
+const testAccResourceDNSRecordConfigTTLUnits = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = var.windns_record_name
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11", "203.0.113.12"]
+  ttl       = "1h"
+}
+`
+
+const testAccResourceDNSRecordConfigDefaultZone = `
+variable "windns_record_name" {}
+
+provider "windns" {
+  default_zone = "example.com"
+}
+
+resource "windns_record" "r1" {
+  name    = var.windns_record_name
+  type    = "A"
+  records = ["203.0.113.11"]
+}
+`
+
+const testAccResourceDNSRecordConfigWildcard = `
+resource "windns_record" "r1" {
+  name      = "*"
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11"]
+}
+`
+
+const testAccResourceDNSRecordConfigDeepName = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = "a.b.${var.windns_record_name}"
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11"]
+}
+`
+
+const testAccResourceDNSRecordConfigACMEChallenge = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = "_acme-challenge.${var.windns_record_name}"
+  zone_name = "example.com"
+  type      = "TXT"
+  ttl       = "60"
+  records   = ["gfj9Xq-Kvx_8Z1c2d3e4f5g6h7i8j9k0l1m2n3o4p5q"]
+}
+`
+
+const testAccResourceDNSRecordConfigMixedCase = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = upper(var.windns_record_name)
+  zone_name = "Example.COM"
+  type      = "A"
+  records   = ["203.0.113.11"]
+}
+`
+
+const testAccResourceDNSRecordConfigMixedCaseLower = `
+variable "windns_record_name" {}
+
+resource "windns_record" "r1" {
+  name      = lower(var.windns_record_name)
+  zone_name = "example.com"
+  type      = "A"
+  records   = ["203.0.113.11"]
+}
+`
+
+func TestAccResourceDNSRecord_BasicPTR(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"example-host.example.com."}, dnshelper.RecordTypePTR, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigBasicPTR,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"example-host.example.com."}, dnshelper.RecordTypePTR, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "fqdn", "12.113.10.10.in-addr.arpa"),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_ClasslessPTR(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"example-host.example.com."}, dnshelper.RecordTypePTR, false),
+			testAccResourceDNSRecordExists("windns_record.r2", []string{"12.0/26.113.10.10.in-addr.arpa."}, dnshelper.RecordTypeCNAME, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigClasslessPTR,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"example-host.example.com."}, dnshelper.RecordTypePTR, true),
+					testAccResourceDNSRecordExists("windns_record.r2", []string{"12.0/26.113.10.10.in-addr.arpa."}, dnshelper.RecordTypeCNAME, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "id", "12_0/26.113.10.10.in-addr.arpa_PTR_false"),
+					resource.TestCheckResourceAttr("windns_record.r1", "fqdn", "12.0/26.113.10.10.in-addr.arpa"),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_MultiplePTR(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+	records := []string{"example-host.example.com.", "example-alias.example.com."}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", records, dnshelper.RecordTypePTR, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigMultiplePTR,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", records, dnshelper.RecordTypePTR, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.#", "2"),
+					resource.TestCheckTypeSetElemAttr("windns_record.r1", "records.*", "example-host.example.com."),
+					resource.TestCheckTypeSetElemAttr("windns_record.r1", "records.*", "example-alias.example.com."),
+				),
+			},
+			{
+				Config:   testAccResourceDNSRecordConfigMultiplePTRReordered,
+				PlanOnly: true,
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+			{
+				Config: testAccResourceDNSRecordConfigMultiplePTRRemoved,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"example-host.example.com."}, dnshelper.RecordTypePTR, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceDSRRecord_BasicPTRWithoutDot(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"example-host.example.com"}, dnshelper.RecordTypePTR, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDSRRecordConfigPTRWithoutDot,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"example-host.example.com"}, dnshelper.RecordTypePTR, true),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_BasicA(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigBasicA,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttrSet("windns_record.r1", "dn"),
+					resource.TestCheckResourceAttr("windns_record.r1", "dynamic", "false"),
+					resource.TestCheckResourceAttr("windns_record.r1", "last_commands.#", "2"),
+					resource.TestMatchResourceAttr("windns_record.r1", "last_commands.0", regexp.MustCompile(`^Add-DnsServerResourceRecord -A `)),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_TypeChange(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+	var aID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"cname.example.com."}, dnshelper.RecordTypeCNAME, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigBasicA,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, true),
+					testAccResourceDNSRecordID("windns_record.r1", &aID),
+				),
+			},
+			{
+				// The A records are deleted before the CNAME record is created, as a CNAME cannot coexist with them.
+				Config: testAccResourceDNSRecordConfigTypeChanged,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"cname.example.com."}, dnshelper.RecordTypeCNAME, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "type", "CNAME"),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.#", "1"),
+					resource.TestCheckResourceAttr("windns_record.r1", "last_commands.#", "1"),
+					resource.TestMatchResourceAttr("windns_record.r1", "last_commands.0", regexp.MustCompile(`^Add-DnsServerResourceRecord -CNAME `)),
+					testAccDNSRecordIDGone(&aID),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_ImportByName(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"cname.example.com"}, dnshelper.RecordTypeCNAME, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigCNAME,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"cname.example.com"}, dnshelper.RecordTypeCNAME, true),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccResourceDNSRecordNameImportID("windns_record.r1"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_ImportByDN(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"cname.example.com"}, dnshelper.RecordTypeCNAME, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigCNAME,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"cname.example.com"}, dnshelper.RecordTypeCNAME, true),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccResourceDNSRecordDNImportID("windns_record.r1"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_ImportByNameMultipleTypes(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigMultiple,
+			},
+			{
+				ResourceName:      "windns_record.r1",
+				ImportState:       true,
+				ImportStateIdFunc: testAccResourceDNSRecordNameImportID("windns_record.r1"),
+				ExpectError:       regexp.MustCompile(".*has records of multiple types.*"),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_BasicAAAA(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"2001:db8::1", "2001:db8::2"}, dnshelper.RecordTypeAAAA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigBasicAAAA,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"2001:db8::1", "2001:db8::2"}, dnshelper.RecordTypeAAAA, true),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_UpperAAAA(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"2001:db8::1", "2001:db8::2"}, dnshelper.RecordTypeAAAA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigUpperAAAA,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"2001:db8::1", "2001:db8::2"}, dnshelper.RecordTypeAAAA, true),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_TTL(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigTTL,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "ttl", "300"),
+				),
+			},
+			{
+				Config: testAccResourceDNSRecordConfigTTLUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "ttl", "3600"),
+				),
+			},
+			{
+				// 1h is the same TTL as 3600, so nothing changes.
+				Config:   testAccResourceDNSRecordConfigTTLUnits,
+				PlanOnly: true,
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_DefaultZone(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigDefaultZone,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "zone_name", "example.com"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_Wildcard(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigWildcard,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "name", "*"),
+					resource.TestCheckResourceAttr("windns_record.r1", "fqdn", "*.example.com"),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_DeepName(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+	name := "a.b." + os.Getenv("TF_VAR_windns_record_name")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigDeepName,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "id", name+"_example.com_A_false"),
+					resource.TestCheckResourceAttr("windns_record.r1", "name", name),
+					resource.TestCheckResourceAttr("windns_record.r1", "fqdn", name+".example.com"),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccResourceDNSRecordNameImportID("windns_record.r1"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccResourceDNSRecordDNImportID("windns_record.r1"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_ACMEChallenge(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+	name := "_acme-challenge." + os.Getenv("TF_VAR_windns_record_name")
+	records := []string{"gfj9Xq-Kvx_8Z1c2d3e4f5g6h7i8j9k0l1m2n3o4p5q"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", records, dnshelper.RecordTypeTXT, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigACMEChallenge,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", records, dnshelper.RecordTypeTXT, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "id", name+"_example.com_TXT_false"),
+					resource.TestCheckResourceAttr("windns_record.r1", "name", name),
+					resource.TestCheckResourceAttr("windns_record.r1", "fqdn", name+".example.com"),
+				),
+			},
+			{
+				Config:   testAccResourceDNSRecordConfigACMEChallenge,
+				PlanOnly: true,
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccResourceDNSRecordNameImportID("windns_record.r1"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_MixedCase(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigMixedCase,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "zone_name", "Example.COM"),
+					resource.TestCheckResourceAttr("windns_record.r1", "name", strings.ToUpper(os.Getenv("TF_VAR_windns_record_name"))),
+				),
+			},
+			{
+				// Changing only the casing of the name and zone plans no changes.
+				Config:   testAccResourceDNSRecordConfigMixedCaseLower,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_BasicTXT(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"TxTdATa9 &!#$%&'()*+,-./:;<=>?@[]^_{|}~"}, dnshelper.RecordTypeTXT, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigBasicTXT,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"TxTdATa9 &!#$%&'()*+,-./:;<=>?@[]^_{|}~"}, dnshelper.RecordTypeTXT, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.0", "TxTdATa9 &!#$%&'()*+,-./:;<=>?@[]^_{|}~"),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_LongTXT(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+	value := "v=DKIM1; p=" + strings.Repeat("0123456789", 30)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{value}, dnshelper.RecordTypeTXT, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigLongTXT,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_record.r1", "records.0", value),
+					resource.TestCheckResourceAttr("windns_record.r1", "txt_segments.0", dnshelper.FormatTXTStrings([]string{value[:255], value[255:]})),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_TrimWhitespace(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"v=spf1  include:example.com -all"}, dnshelper.RecordTypeTXT, false),
+			testAccResourceDNSRecordExists("windns_record.r2", []string{"203.0.113.11"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				// The trailing space is removed, while the spaces within the TXT record are kept.
+				Config: testAccResourceDNSRecordConfigTrimWhitespace,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"v=spf1  include:example.com -all"}, dnshelper.RecordTypeTXT, true),
+					testAccResourceDNSRecordExists("windns_record.r2", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_ExplicitTXTSegments(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"v=DKIM1; k=rsa; p=MIGfMA0GCSqGSIb3DQEB"}, dnshelper.RecordTypeTXT, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigExplicitTXTSegments,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_record.r1", "records.0", `"v=DKIM1; k=rsa; " "p=MIGfMA0GCSqGSIb3DQEB"`),
+					resource.TestCheckResourceAttr("windns_record.r1", "txt_segments.0", `"v=DKIM1; k=rsa; " "p=MIGfMA0GCSqGSIb3DQEB"`),
+				),
+			},
+			{
+				Config:   testAccResourceDNSRecordConfigExplicitTXTSegments,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_AppendOnly(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", nil, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigAppendOnly,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.#", "1"),
+					resource.TestCheckResourceAttr("windns_record.r2", "records.#", "1"),
+				),
+			},
+			{
+				Config: testAccResourceDNSRecordConfigAppendOnlyUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.12", "203.0.113.13"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.0", "203.0.113.13"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_CreateOnly(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigCreateOnly,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+				),
+			},
+			{
+				// The change is kept in the state without being applied, so the plan is empty afterwards.
+				Config: testAccResourceDNSRecordConfigCreateOnlyUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.0", "203.0.113.12"),
+					resource.TestCheckResourceAttr("windns_record.r1", "last_commands.#", "0"),
+				),
+			},
+			{
+				// Disabling create_only manages the records again, so destroying them removes them.
+				Config: testAccResourceDNSRecordConfigCreateOnlyDisabled,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_Multiple(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, false),
+			testAccResourceDNSRecordExists("windns_record.r2", []string{"2001:db8::1"}, dnshelper.RecordTypeAAAA, false),
+			testAccResourceDNSRecordExists("windns_record.r3", []string{"TXTDATA"}, dnshelper.RecordTypeTXT, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigMultiple,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, true),
+					testAccResourceDNSRecordExists("windns_record.r2", []string{"2001:db8::1"}, dnshelper.RecordTypeAAAA, true),
+					testAccResourceDNSRecordExists("windns_record.r3", []string{"TXTDATA"}, dnshelper.RecordTypeTXT, true),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+			{
+				ResourceName:            "windns_record.r2",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+			{
+				ResourceName:            "windns_record.r3",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_MultipleUpdated(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, false),
+			testAccResourceDNSRecordExists("windns_record.r2", []string{"2001:db8::1"}, dnshelper.RecordTypeAAAA, false),
+			testAccResourceDNSRecordExists("windns_record.r3", []string{"TXTDATA"}, dnshelper.RecordTypeTXT, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigMultiple,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, true),
+					testAccResourceDNSRecordExists("windns_record.r2", []string{"2001:db8::1"}, dnshelper.RecordTypeAAAA, true),
+					testAccResourceDNSRecordExists("windns_record.r3", []string{"TXTDATA"}, dnshelper.RecordTypeTXT, true),
+				),
+			},
+			{
+				Config: testAccResourceDNSRecordConfigMultipleUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.21", "203.0.113.22"}, dnshelper.RecordTypeA, true),
+					testAccResourceDNSRecordExists("windns_record.r2", []string{"2001:db8::2"}, dnshelper.RecordTypeAAAA, true),
+					testAccResourceDNSRecordExists("windns_record.r3", []string{"UPDATED_DATA"}, dnshelper.RecordTypeTXT, true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_CNAME(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"cname.example.com"}, dnshelper.RecordTypeCNAME, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigCNAME,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"cname.example.com"}, dnshelper.RecordTypeCNAME, true),
+					// The server returns the target with the trailing dot, which is the form kept in the state.
+					resource.TestCheckResourceAttr("windns_record.r1", "records.0", "cname.example.com."),
+				),
+			},
+			{
+				// Adding the trailing dot to the configuration is not a change.
+				Config:   testAccResourceDNSRecordConfigCNAMEWithDot,
+				PlanOnly: true,
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_CNAMEWithDot(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"cname.example.com."}, dnshelper.RecordTypeCNAME, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigCNAMEWithDot,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"cname.example.com."}, dnshelper.RecordTypeCNAME, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.0", "cname.example.com."),
+				),
+			},
+			{
+				// Removing the trailing dot from the configuration is not a change either.
+				Config:   testAccResourceDNSRecordConfigCNAME,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_ApexA(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.10"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigApexA,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.10"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "fqdn", "example.com"),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_ApexTXT(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"v=spf1 -all"}, dnshelper.RecordTypeTXT, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigApexTXT,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"v=spf1 -all"}, dnshelper.RecordTypeTXT, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "fqdn", "example.com"),
+				),
+			},
+			{
+				Config:   testAccResourceDNSRecordConfigApexTXT,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_Disappears(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigBasicA,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, true),
+					testAccResourceDNSRecordDisappears("windns_record.r1"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_AlreadyExists(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceDNSRecordConfigAlreadyExists,
+				ExpectError: regexp.MustCompile(".*already exist.*force_overwrite.*"),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_AFSDB(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"1 afsdb.example.com"}, dnshelper.RecordTypeAFSDB, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigAFSDB,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"1 afsdb.example.com"}, dnshelper.RecordTypeAFSDB, true),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_RP(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"admin.example.com txt.example.com"}, dnshelper.RecordTypeRP, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigRP,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"admin.example.com txt.example.com"}, dnshelper.RecordTypeRP, true),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_RT(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"10 relay.example.com"}, dnshelper.RecordTypeRT, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigRT,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"10 relay.example.com"}, dnshelper.RecordTypeRT, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.0", "10 relay.example.com."),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_VirtualizationInstance(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name", "TF_VAR_windns_virtualization_instance"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigVirtualizationInstance,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "virtualization_instance", os.Getenv("TF_VAR_windns_virtualization_instance")),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_ZoneScope(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name", "TF_VAR_windns_zone_scope"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, false),
+			testAccResourceDNSRecordExists("windns_record.r2", []string{"198.51.100.11"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				// The same name has different records in the default scope and in the zone scope.
+				Config: testAccResourceDNSRecordConfigZoneScope,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+					testAccResourceDNSRecordExists("windns_record.r2", []string{"198.51.100.11"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r2", "zone_scope", os.Getenv("TF_VAR_windns_zone_scope")),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r2",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_DNSServer(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name", "TF_VAR_windns_dns_server"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigDNSServer,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "dns_server", os.Getenv("TF_VAR_windns_dns_server")),
+				),
+			},
+			{
+				Config: testAccResourceDNSRecordConfigDNSServerUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_IDN(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigIDN,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "id", "xn--bcher-tf-65a_example.com_A_false"),
+					resource.TestCheckResourceAttr("windns_record.r1", "name", "bücher-tf"),
+					resource.TestCheckResourceAttr("windns_record.r1", "fqdn", "xn--bcher-tf-65a.example.com"),
+				),
+			},
+			{
+				// Writing the name as an A-label is the same name, and plans no changes.
+				Config:   testAccResourceDNSRecordConfigIDNALabel,
+				PlanOnly: true,
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_Ordered(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigOrdered,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_record.r1", "records.0", "203.0.113.12"),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.1", "203.0.113.11"),
+				),
+			},
+			{
+				Config: testAccResourceDNSRecordConfigOrderedUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_record.r1", "records.0", "203.0.113.11"),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.1", "203.0.113.12"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_WKS(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11 tcp smtp ftp"}, dnshelper.RecordTypeWKS, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigWKS,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11 tcp smtp ftp"}, dnshelper.RecordTypeWKS, true),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_Delegation(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+	nameServer := fmt.Sprintf("ns1.%s.example.com.", os.Getenv("TF_VAR_windns_record_name"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{nameServer}, dnshelper.RecordTypeNS, false),
+			testAccResourceDNSRecordExists("windns_record.r2", nil, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourceDNSRecordConfigDelegation, "203.0.113.53"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{nameServer}, dnshelper.RecordTypeNS, true),
+					testAccResourceDNSRecordExists("windns_record.r2", []string{"203.0.113.53"}, dnshelper.RecordTypeA, true),
+				),
+			},
+			{
+				// The glue is updated in place, leaving the delegation as it is.
+				Config: fmt.Sprintf(testAccResourceDNSRecordConfigDelegation, "203.0.113.54"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{nameServer}, dnshelper.RecordTypeNS, true),
+					testAccResourceDNSRecordExists("windns_record.r2", []string{"203.0.113.54"}, dnshelper.RecordTypeA, true),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_AllowEmpty(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", nil, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourceDNSRecordConfigAllowEmpty, `["203.0.113.11"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+				),
+			},
+			{
+				// The records are removed from the server while the resource is kept.
+				Config: fmt.Sprintf(testAccResourceDNSRecordConfigAllowEmpty, "[]"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", nil, dnshelper.RecordTypeA, false),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.#", "0"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccResourceDNSRecordConfigAllowEmpty, `["203.0.113.12"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.12"}, dnshelper.RecordTypeA, true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_Enabled(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+	records := []string{"203.0.113.11", "203.0.113.12"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", records, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourceDNSRecordConfigEnabled, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", records, dnshelper.RecordTypeA, true),
+				),
+			},
+			{
+				// The records are removed from the server while the resource and its records are kept.
+				Config: fmt.Sprintf(testAccResourceDNSRecordConfigEnabled, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", records, dnshelper.RecordTypeA, false),
+					resource.TestCheckResourceAttr("windns_record.r1", "enabled", "false"),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.#", "2"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccResourceDNSRecordConfigEnabled, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", records, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "enabled", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_Empty(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceDNSRecordConfigEmpty,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`records is empty`),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_SOA(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceDNSRecordConfigSOA,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Use windns_zone_soa`),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_ApexNSEmpty(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceDNSRecordConfigApexNSEmpty,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`cannot be removed, even with allow_empty`),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_ReverseZoneMismatch(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceDNSRecordConfigAInReverseZone,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`cannot be created in the reverse lookup zone`),
+			},
+			{
+				Config:      testAccResourceDNSRecordConfigPTRForwardName,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`is not a valid name for a PTR record`),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_OwnerTag(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", nil, dnshelper.RecordTypeA, false),
+			testAccDNSOwnerTagExists("windns_record.r1", ""),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourceDNSRecordConfigOwnerTag, "team-dns"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, true),
+					testAccDNSOwnerTagExists("windns_record.r1", "team-dns"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccResourceDNSRecordConfigOwnerTag, "team-web"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDNSOwnerTagExists("windns_record.r1", "team-web"),
+				),
+			},
+			{
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands", "owner_tag"},
+			},
+		},
+	})
+}
 
-func TestAccResourceDNSRecord_BasicPTR(t *testing.T) {
+func TestAccResourceDNSRecord_ATMA(t *testing.T) {
 	envVars := []string{"TF_VAR_windns_record_name"}
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t, envVars) },
 		ProviderFactories: testAccProviderFactories,
 		CheckDestroy: resource.ComposeTestCheckFunc(
-			testAccResourceDNSRecordExists("windns_record.r1", []string{"example-host.example.com."}, dnshelper.RecordTypePTR, false),
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"E164 358400123456"}, dnshelper.RecordTypeATMA, false),
 		),
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceDNSRecordConfigBasicPTR,
+				Config: testAccResourceDNSRecordConfigATMA,
 				Check: resource.ComposeTestCheckFunc(
-					testAccResourceDNSRecordExists("windns_record.r1", []string{"example-host.example.com."}, dnshelper.RecordTypePTR, true),
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"E164 358400123456"}, dnshelper.RecordTypeATMA, true),
 				),
 			},
 			{
-				ResourceName:      "windns_record.r1",
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
 			},
 		},
 	})
 }
 
-func TestAccResourceDSRRecord_BasicPTRWithoutDot(t *testing.T) {
+func TestAccResourceDNSRecord_CERT(t *testing.T) {
 	envVars := []string{"TF_VAR_windns_record_name"}
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t, envVars) },
 		ProviderFactories: testAccProviderFactories,
 		CheckDestroy: resource.ComposeTestCheckFunc(
-			testAccResourceDNSRecordExists("windns_record.r1", []string{"example-host.example.com"}, dnshelper.RecordTypePTR, false),
+			testAccResourceDNSRecordExists("windns_record.r1", []string{testAccCERTRecordData}, dnshelper.RecordTypeCERT, false),
 		),
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceDSRRecordConfigPTRWithoutDot,
+				Config: fmt.Sprintf(testAccResourceDNSRecordConfigCERT, testAccCERTRecordData),
 				Check: resource.ComposeTestCheckFunc(
-					testAccResourceDNSRecordExists("windns_record.r1", []string{"example-host.example.com"}, dnshelper.RecordTypePTR, true),
+					testAccResourceDNSRecordExists("windns_record.r1", []string{testAccCERTRecordData}, dnshelper.RecordTypeCERT, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.0", testAccCERTRecordData),
 				),
 			},
 			{
-				ResourceName:      "windns_record.r1",
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
 			},
 		},
 	})
 }
 
-func TestAccResourceDNSRecord_BasicA(t *testing.T) {
+func TestAccResourceDNSRecord_DHCID(t *testing.T) {
 	envVars := []string{"TF_VAR_windns_record_name"}
+	recordData := []string{"AAIBY2/AuCccgoJbsaxcQc9TUapptP69lOjxfNuVAA2kjEA="}
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t, envVars) },
 		ProviderFactories: testAccProviderFactories,
 		CheckDestroy: resource.ComposeTestCheckFunc(
-			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, false),
+			testAccResourceDNSRecordExists("windns_record.r1", recordData, dnshelper.RecordTypeDHCID, false),
 		),
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceDNSRecordConfigBasicA,
+				Config: testAccResourceDNSRecordConfigDHCID,
 				Check: resource.ComposeTestCheckFunc(
-					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, true),
+					testAccResourceDNSRecordExists("windns_record.r1", recordData, dnshelper.RecordTypeDHCID, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "records.0", recordData[0]),
 				),
 			},
 			{
-				ResourceName:      "windns_record.r1",
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
 			},
 		},
 	})
 }
 
-func TestAccResourceDNSRecord_BasicAAAA(t *testing.T) {
+func TestAccResourceDNSRecord_DS(t *testing.T) {
 	envVars := []string{"TF_VAR_windns_record_name"}
+	ds := "60485 8 2 d4b7d520e7bb5f0f67674a0cceb1e3e0614b93c4f9e99b8383f6a1e4469da50a"
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t, envVars) },
 		ProviderFactories: testAccProviderFactories,
 		CheckDestroy: resource.ComposeTestCheckFunc(
-			testAccResourceDNSRecordExists("windns_record.r1", []string{"2001:db8::1", "2001:db8::2"}, dnshelper.RecordTypeAAAA, false),
+			testAccResourceDNSRecordExists("windns_record.r1", []string{ds}, dnshelper.RecordTypeDS, false),
 		),
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceDNSRecordConfigBasicAAAA,
+				Config: testAccResourceDNSRecordConfigDS,
 				Check: resource.ComposeTestCheckFunc(
-					testAccResourceDNSRecordExists("windns_record.r1", []string{"2001:db8::1", "2001:db8::2"}, dnshelper.RecordTypeAAAA, true),
+					testAccResourceDNSRecordExists("windns_record.r1", []string{ds}, dnshelper.RecordTypeDS, true),
 				),
 			},
 			{
-				ResourceName:      "windns_record.r1",
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
 			},
 		},
 	})
 }
 
-func TestAccResourceDNSRecord_UpperAAAA(t *testing.T) {
+func TestAccResourceDNSRecord_X25(t *testing.T) {
 	envVars := []string{"TF_VAR_windns_record_name"}
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t, envVars) },
 		ProviderFactories: testAccProviderFactories,
 		CheckDestroy: resource.ComposeTestCheckFunc(
-			testAccResourceDNSRecordExists("windns_record.r1", []string{"2001:db8::1", "2001:db8::2"}, dnshelper.RecordTypeAAAA, false),
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"311061700956"}, dnshelper.RecordTypeX25, false),
 		),
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceDNSRecordConfigUpperAAAA,
+				Config: testAccResourceDNSRecordConfigX25,
 				Check: resource.ComposeTestCheckFunc(
-					testAccResourceDNSRecordExists("windns_record.r1", []string{"2001:db8::1", "2001:db8::2"}, dnshelper.RecordTypeAAAA, true),
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"311061700956"}, dnshelper.RecordTypeX25, true),
 				),
 			},
 			{
-				ResourceName:      "windns_record.r1",
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
 			},
 		},
 	})
 }
 
-func TestAccResourceDNSRecord_BasicTXT(t *testing.T) {
+func TestAccResourceDNSRecord_ISDN(t *testing.T) {
 	envVars := []string{"TF_VAR_windns_record_name"}
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t, envVars) },
 		ProviderFactories: testAccProviderFactories,
 		CheckDestroy: resource.ComposeTestCheckFunc(
-			testAccResourceDNSRecordExists("windns_record.r1", []string{"TxTdATa9 &!#$%&'()*+,-./:;<=>?@[]^_{|}~"}, dnshelper.RecordTypeTXT, false),
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"150862028003217 004", "150862028003218"}, dnshelper.RecordTypeISDN, false),
 		),
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceDNSRecordConfigBasicTXT,
+				Config: testAccResourceDNSRecordConfigISDN,
 				Check: resource.ComposeTestCheckFunc(
-					testAccResourceDNSRecordExists("windns_record.r1", []string{"TxTdATa9 &!#$%&'()*+,-./:;<=>?@[]^_{|}~"}, dnshelper.RecordTypeTXT, true),
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"150862028003217 004", "150862028003218"}, dnshelper.RecordTypeISDN, true),
 				),
 			},
 			{
-				ResourceName:      "windns_record.r1",
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
 			},
 		},
 	})
 }
 
-func TestAccResourceDNSRecord_Multiple(t *testing.T) {
+func TestAccResourceDNSRecord_MINFO(t *testing.T) {
 	envVars := []string{"TF_VAR_windns_record_name"}
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t, envVars) },
 		ProviderFactories: testAccProviderFactories,
 		CheckDestroy: resource.ComposeTestCheckFunc(
-			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, false),
-			testAccResourceDNSRecordExists("windns_record.r2", []string{"2001:db8::1"}, dnshelper.RecordTypeAAAA, false),
-			testAccResourceDNSRecordExists("windns_record.r3", []string{"TXTDATA"}, dnshelper.RecordTypeTXT, false),
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"admin.example.com. errors.example.com."}, dnshelper.RecordTypeMINFO, false),
 		),
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceDNSRecordConfigMultiple,
+				Config: testAccResourceDNSRecordConfigMINFO,
 				Check: resource.ComposeTestCheckFunc(
-					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, true),
-					testAccResourceDNSRecordExists("windns_record.r2", []string{"2001:db8::1"}, dnshelper.RecordTypeAAAA, true),
-					testAccResourceDNSRecordExists("windns_record.r3", []string{"TXTDATA"}, dnshelper.RecordTypeTXT, true),
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"admin.example.com. errors.example.com."}, dnshelper.RecordTypeMINFO, true),
 				),
 			},
 			{
-				ResourceName:      "windns_record.r1",
-				ImportState:       true,
-				ImportStateVerify: true,
-			},
-			{
-				ResourceName:      "windns_record.r2",
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            "windns_record.r1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_commands"},
 			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_ManagePtrLifecycle(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"10.10.113.14"}, dnshelper.RecordTypeA, false),
+			testAccDNSPtrRecordExists("10.10.113.14", "10.10.in-addr.arpa", false),
+		),
+		Steps: []resource.TestStep{
 			{
-				ResourceName:      "windns_record.r3",
-				ImportState:       true,
-				ImportStateVerify: true,
+				Config: testAccResourceDNSRecordConfigManagePtrLifecycle,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"10.10.113.14"}, dnshelper.RecordTypeA, true),
+					testAccDNSPtrRecordExists("10.10.113.14", "10.10.in-addr.arpa", true),
+				),
 			},
 		},
 	})
 }
 
-func TestAccResourceDNSRecord_MultipleUpdated(t *testing.T) {
+func TestAccResourceDNSRecord_PtrZone(t *testing.T) {
 	envVars := []string{"TF_VAR_windns_record_name"}
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t, envVars) },
 		ProviderFactories: testAccProviderFactories,
 		CheckDestroy: resource.ComposeTestCheckFunc(
-			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, false),
-			testAccResourceDNSRecordExists("windns_record.r2", []string{"2001:db8::1"}, dnshelper.RecordTypeAAAA, false),
-			testAccResourceDNSRecordExists("windns_record.r3", []string{"TXTDATA"}, dnshelper.RecordTypeTXT, false),
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"10.10.113.13"}, dnshelper.RecordTypeA, false),
 		),
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceDNSRecordConfigMultiple,
+				Config:      testAccResourceDNSRecordConfigPtrZoneUncovered,
+				ExpectError: regexp.MustCompile(".*does not cover address.*"),
+			},
+			{
+				Config: testAccResourceDNSRecordConfigPtrZone,
 				Check: resource.ComposeTestCheckFunc(
-					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11", "203.0.113.12"}, dnshelper.RecordTypeA, true),
-					testAccResourceDNSRecordExists("windns_record.r2", []string{"2001:db8::1"}, dnshelper.RecordTypeAAAA, true),
-					testAccResourceDNSRecordExists("windns_record.r3", []string{"TXTDATA"}, dnshelper.RecordTypeTXT, true),
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"10.10.113.13"}, dnshelper.RecordTypeA, true),
 				),
 			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_MissingPtrZone(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"198.51.100.11"}, dnshelper.RecordTypeA, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceDNSRecordConfigMissingPtrZone,
+				ExpectError: regexp.MustCompile(".*no reverse zone for 198.51.100.11.*"),
+			},
 			{
-				Config: testAccResourceDNSRecordConfigMultipleUpdated,
+				Config: testAccResourceDNSRecordConfigMissingPtrZoneWarn,
 				Check: resource.ComposeTestCheckFunc(
-					testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.21", "203.0.113.22"}, dnshelper.RecordTypeA, true),
-					testAccResourceDNSRecordExists("windns_record.r2", []string{"2001:db8::2"}, dnshelper.RecordTypeAAAA, true),
-					testAccResourceDNSRecordExists("windns_record.r3", []string{"UPDATED_DATA"}, dnshelper.RecordTypeTXT, true),
+					testAccResourceDNSRecordExists("windns_record.r1", []string{"198.51.100.11"}, dnshelper.RecordTypeA, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "missing_ptr_zone", "warn"),
 				),
 			},
 		},
 	})
 }
 
-func TestAccResourceDNSRecord_CNAME(t *testing.T) {
+func TestAccResourceDNSRecord_AccessRule(t *testing.T) {
 	envVars := []string{"TF_VAR_windns_record_name"}
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t, envVars) },
 		ProviderFactories: testAccProviderFactories,
 		CheckDestroy: resource.ComposeTestCheckFunc(
-			testAccResourceDNSRecordExists("windns_record.r1", []string{"cname.example.com"}, dnshelper.RecordTypeCNAME, false),
+			testAccResourceDNSRecordExists("windns_record.r1", []string{"203.0.113.11"}, dnshelper.RecordTypeA, false),
 		),
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceDNSRecordConfigCNAME,
+				Config: testAccResourceDNSRecordConfigAccessRule,
 				Check: resource.ComposeTestCheckFunc(
-					testAccResourceDNSRecordExists("windns_record.r1", []string{"cname.example.com"}, dnshelper.RecordTypeCNAME, true),
+					resource.TestCheckResourceAttr("windns_record.r1", "access_rule.#", "1"),
+					resource.TestCheckTypeSetElemAttr("windns_record.r1", "access_rule.*.rights.*", "GenericRead"),
 				),
 			},
 			{
-				ResourceName:      "windns_record.r1",
-				ImportState:       true,
-				ImportStateVerify: true,
+				Config: testAccResourceDNSRecordConfigAccessRuleUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr("windns_record.r1", "access_rule.*.rights.*", "WriteProperty"),
+				),
+			},
+			{
+				Config: testAccResourceDNSRecordConfigAccessRuleRemoved,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_record.r1", "access_rule.#", "0"),
+				),
 			},
 		},
 	})
 }
 
+func Test_readAccessRules(t *testing.T) {
+	block := func(principal string, rights ...interface{}) interface{} {
+		return map[string]interface{}{"principal": principal, "rights": schema.NewSet(schema.HashString, rights)}
+	}
+	configured := []interface{}{
+		block(`EXAMPLE\dns-admins`, "GenericRead", "WriteProperty"),
+		block("S-1-5-20", "GenericRead"),
+	}
+
+	tests := []struct {
+		name string
+		read []dnshelper.RecordAccessRule
+		want []interface{}
+	}{
+		{
+			"test-granted",
+			[]dnshelper.RecordAccessRule{{Principal: `example\DNS-admins`, Rights: 0x200B4}, {Principal: "S-1-5-20", Rights: 0x20094}},
+			[]interface{}{
+				map[string]interface{}{"principal": `EXAMPLE\dns-admins`, "rights": []string{"GenericRead", "WriteProperty"}},
+				map[string]interface{}{"principal": "S-1-5-20", "rights": []string{"GenericRead"}},
+			},
+		},
+		{
+			"test-changed",
+			[]dnshelper.RecordAccessRule{{Principal: `EXAMPLE\dns-admins`, Rights: 0xF01FF}, {Principal: "S-1-5-20", Rights: 0x20094}},
+			[]interface{}{
+				map[string]interface{}{"principal": `EXAMPLE\dns-admins`, "rights": []string{"GenericAll"}},
+				map[string]interface{}{"principal": "S-1-5-20", "rights": []string{"GenericRead"}},
+			},
+		},
+		{
+			"test-removed",
+			[]dnshelper.RecordAccessRule{{Principal: `EXAMPLE\dns-admins`}, {Principal: "S-1-5-20", Rights: 0x20094}},
+			[]interface{}{
+				map[string]interface{}{"principal": "S-1-5-20", "rights": []string{"GenericRead"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readAccessRules(configured, tt.read); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readAccessRules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_waitPostWrite(t *testing.T) {
+	delay := 50 * time.Millisecond
+	conf := config.NewProviderConf(&config.Settings{PostWriteDelay: delay})
+
+	start := time.Now()
+	if diags := waitPostWrite(context.Background(), conf, true); diags != nil || time.Since(start) < delay {
+		t.Errorf("waitPostWrite() = %v after %s, want no diagnostics after at least %s", diags, time.Since(start), delay)
+	}
+
+	start = time.Now()
+	if diags := waitPostWrite(context.Background(), conf, false); diags != nil || time.Since(start) >= delay {
+		t.Errorf("waitPostWrite() without writes = %v after %s, want no wait", diags, time.Since(start))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if diags := waitPostWrite(ctx, conf, true); len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Errorf("waitPostWrite() after cancel = %v, want a warning", diags)
+	}
+
+	if diags := waitPostWrite(context.Background(), config.NewProviderConf(&config.Settings{}), true); diags != nil {
+		t.Errorf("waitPostWrite() without post_write_delay = %v, want no diagnostics", diags)
+	}
+}
+
 func TestAccResourceDNSRecord_IllegalCharacter(t *testing.T) {
 	envVars := []string{"TF_VAR_windns_record_name"}
 
@@ -426,6 +2710,56 @@ func TestAccResourceDNSRecord_IllegalCharacter(t *testing.T) {
 	})
 }
 
+func TestAccResourceDNSRecord_MissingZone(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceDNSRecordConfigMissingZone,
+				ExpectError: regexp.MustCompile(`zone "missing.example.com" not found on server`),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_MultipleCNAME(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceDNSRecordConfigMultipleCNAME,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`a CNAME record can only have a single value`),
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSRecord_CNAMEConflict(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordConfigBasicA,
+			},
+			{
+				Config:      testAccResourceDNSRecordConfigCNAMEConflict,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`a CNAME record cannot coexist with records of other types`),
+			},
+		},
+	})
+}
+
 func testAccResourceDNSRecordExists(resource string, expectedRecords []string, expectedRecordType string, expected bool) resource.TestCheckFunc {
 	ctx := context.Background()
 	return func(s *terraform.State) error {
@@ -434,15 +2768,16 @@ func testAccResourceDNSRecordExists(resource string, expectedRecords []string, e
 			return fmt.Errorf("%s key not found in state", resource)
 		}
 
-		r, err := dnshelper.GetDNSRecordFromId(ctx, testAccProvider.Meta().(*config.ProviderConf), rs.Primary.ID)
+		conf := testAccProvider.Meta().(*config.ProviderConf).ForDnsServer(rs.Primary.Attributes["dns_server"])
+		r, err := dnshelper.GetDNSRecordFromId(ctx, conf, rs.Primary.ID)
 		if err != nil {
-			if strings.Contains(err.Error(), "ObjectNotFound") && !expected {
+			if dnshelper.IsNotFound(err) && !expected {
 				return nil
 			}
 			return err
 		}
 
-		found := suppressRecordDiffForType(r.Records, expectedRecords, expectedRecordType)
+		found := suppressRecordDiffForType(r.Records, expectedRecords, expectedRecordType, false)
 
 		if !found {
 			return fmt.Errorf("record %s did not contain expected record data. Found %q, Expected %q", r.Id(), r.Records, expectedRecords)
@@ -450,3 +2785,151 @@ func testAccResourceDNSRecordExists(resource string, expectedRecords []string, e
 		return nil
 	}
 }
+
+// testAccDNSOwnerTagExists checks whether the records of resource are tagged with owner, or not tagged when owner is
+// empty.
+func testAccDNSOwnerTagExists(resource string, owner string) resource.TestCheckFunc {
+	ctx := context.Background()
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("%s key not found in state", resource)
+		}
+
+		r := dnshelper.Record{
+			HostName:   rs.Primary.Attributes["name"],
+			ZoneName:   rs.Primary.Attributes["zone_name"],
+			RecordType: rs.Primary.Attributes["type"],
+		}
+		got, err := r.GetOwnerTag(ctx, testAccProvider.Meta().(*config.ProviderConf))
+		if err != nil {
+			return err
+		}
+		if got != owner {
+			return fmt.Errorf("records of %s are tagged with owner %q, want %q", r.Id(), got, owner)
+		}
+		return nil
+	}
+}
+
+// testAccDNSPtrRecordExists checks whether the PTR record for address exists in the reverse zone ptrZoneName.
+func testAccDNSPtrRecordExists(address, ptrZoneName string, expected bool) resource.TestCheckFunc {
+	ctx := context.Background()
+	return func(s *terraform.State) error {
+		ptrName, err := dnshelper.ReverseNameInZone(address, ptrZoneName)
+		if err != nil {
+			return err
+		}
+		ptr := dnshelper.Record{HostName: ptrName, ZoneName: ptrZoneName, RecordType: dnshelper.RecordTypePTR}
+
+		_, err = dnshelper.GetDNSRecordFromId(ctx, testAccProvider.Meta().(*config.ProviderConf), ptr.Id())
+		if dnshelper.IsNotFound(err) {
+			if expected {
+				return fmt.Errorf("PTR record for %s not found in zone %q", address, ptrZoneName)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !expected {
+			return fmt.Errorf("PTR record for %s still exists in zone %q", address, ptrZoneName)
+		}
+		return nil
+	}
+}
+
+// testAccResourceDNSRecordID stores the ID of resource in id, for later steps to check the records it had.
+func testAccResourceDNSRecordID(resource string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("%s key not found in state", resource)
+		}
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
+// testAccDNSRecordIDGone checks that no records with the name, zone and type of the ID in id are left on the server.
+func testAccDNSRecordIDGone(id *string) resource.TestCheckFunc {
+	ctx := context.Background()
+	return func(s *terraform.State) error {
+		r, err := dnshelper.GetDNSRecordFromId(ctx, testAccProvider.Meta().(*config.ProviderConf), *id)
+		if err != nil {
+			if dnshelper.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		return fmt.Errorf("records %s were left on the server: %q", *id, r.Records)
+	}
+}
+
+func testAccResourceDNSRecordNameImportID(resource string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resource]
+		if !ok {
+			return "", fmt.Errorf("%s key not found in state", resource)
+		}
+		return strings.Join([]string{rs.Primary.Attributes["name"], rs.Primary.Attributes["zone_name"]}, dnshelper.IDSeparator), nil
+	}
+}
+
+func testAccResourceDNSRecordDNImportID(resource string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resource]
+		if !ok {
+			return "", fmt.Errorf("%s key not found in state", resource)
+		}
+		return rs.Primary.Attributes["dn"], nil
+	}
+}
+
+// testAccResourceDNSRecordDisappears deletes the records of resource outside of Terraform.
+func testAccResourceDNSRecordDisappears(resource string) resource.TestCheckFunc {
+	ctx := context.Background()
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("%s key not found in state", resource)
+		}
+
+		conf := testAccProvider.Meta().(*config.ProviderConf)
+		r, err := dnshelper.GetDNSRecordFromId(ctx, conf, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		r.CreatePtr = false
+		return r.Delete(ctx, conf)
+	}
+}
+
+func Test_splitImportDNSServer(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            string
+		wantID        string
+		wantDNSServer string
+	}{
+		{"test-id", "www_example.com_A_false", "www_example.com_A_false", ""},
+		{"test-id-dns-server", "www_example.com_A_false@dc02.example.com", "www_example.com_A_false", "dc02.example.com"},
+		{"test-apex", "@_example.com_NS_false", "@_example.com_NS_false", ""},
+		{"test-apex-dns-server", "@_example.com_NS_false@dc02", "@_example.com_NS_false", "dc02"},
+		{"test-short-form-dns-server", "www_example.com@dc02.example.com", "www_example.com", "dc02.example.com"},
+		{"test-apex-dn", "DC=@,DC=example.com,CN=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com", "DC=@,DC=example.com,CN=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com", ""},
+		{
+			"test-apex-dn-dns-server", "DC=@,DC=example.com,CN=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com@dc02.example.com",
+			"DC=@,DC=example.com,CN=MicrosoftDNS,DC=DomainDnsZones,DC=example,DC=com", "dc02.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, dnsServer := splitImportDNSServer(tt.id)
+			if id != tt.wantID || dnsServer != tt.wantDNSServer {
+				t.Errorf("splitImportDNSServer() = %q, %q, want %q, %q", id, dnsServer, tt.wantID, tt.wantDNSServer)
+			}
+		})
+	}
+}
@@ -0,0 +1,371 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+	"golang.org/x/exp/slices"
+)
+
+func resourceDNSRecords() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windns_records` manages many DNS records in a zone of a Windows DNS Server as a single resource.",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultRecordTimeout),
+			Read:   schema.DefaultTimeout(defaultRecordTimeout),
+			Update: schema.DefaultTimeout(defaultRecordTimeout),
+			Delete: schema.DefaultTimeout(defaultRecordTimeout),
+		},
+		ReadContext:   resourceDNSRecordsRead,
+		CreateContext: resourceDNSRecordsCreate,
+		UpdateContext: resourceDNSRecordsUpdate,
+		DeleteContext: resourceDNSRecordsDelete,
+		Schema: map[string]*schema.Schema{
+			"zone_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateDomainName,
+				DiffSuppressFunc: suppressDomainNameDiff,
+				Description:      "The zone name for the dns records.",
+			},
+			"record": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "The records to manage, one block per name and type.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateDomainName,
+							Description:  "The name of the dns records. Use `@` or an empty string for the zone apex. Internationalized names may be written in Unicode or as A-labels.",
+						},
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRecordType,
+							Description:  "The type of the dns records. `SOA` records are managed with `windns_zone_soa`.",
+						},
+						"records": {
+							Type:        schema.TypeSet,
+							Required:    true,
+							MinItems:    1,
+							Description: "A set of records, written as in the `records` attribute of `windns_record`.",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+		CustomizeDiff: customizeDiffRecordsZone,
+	}
+}
+
+// customizeDiffRecordsZone verifies at plan time that the type and name of every record block fit the kind of zone, see
+// customizeDiffRecordZone.
+func customizeDiffRecordsZone(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if !d.NewValueKnown("zone_name") || !d.NewValueKnown("record") {
+		return nil
+	}
+	zoneName := asciiName(d.Get("zone_name").(string))
+	for _, v := range d.Get("record").(*schema.Set).List() {
+		block := v.(map[string]interface{})
+		if err := dnshelper.CheckRecordZone(zoneName, asciiName(block["name"].(string)), block["type"].(string)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordsKey identifies a record block of windns_records by its name and type.
+func recordsKey(name, recordType string) string {
+	if dnshelper.IsApexName(name) {
+		name = dnshelper.ApexName
+	}
+	return strings.ToLower(name) + dnshelper.IDSeparator + strings.ToUpper(recordType)
+}
+
+// recordsBlockKey returns the recordsKey of a record block of windns_records.
+func recordsBlockKey(conf *config.ProviderConf, v interface{}) string {
+	block := v.(map[string]interface{})
+	return recordsKey(dnshelper.OwnerName(conf, asciiName(block["name"].(string))), block["type"].(string))
+}
+
+// recordsFromSet returns the records of each record block in set, keyed by recordsKey.
+// Several blocks with the same name and type are rejected.
+func recordsFromSet(conf *config.ProviderConf, zoneName string, set *schema.Set) (map[string]*dnshelper.Record, error) {
+	records := make(map[string]*dnshelper.Record)
+	for _, v := range set.List() {
+		record, err := dnshelper.NewDNSRecordFromMap(conf, zoneName, v.(map[string]interface{}))
+		if err != nil {
+			return nil, err
+		}
+		key := recordsKey(record.HostName, record.RecordType)
+		if _, ok := records[key]; ok {
+			return nil, fmt.Errorf("%s records for %q are given in more than one record block", record.RecordType, record.HostName)
+		}
+		records[key] = record
+	}
+	return records, nil
+}
+
+// appliedRecordBlocks returns the record blocks to store in the state after applying the change from oldBlocks to
+// newBlocks, where failed holds the recordsKey of the record blocks whose records could not be changed. The changes of
+// the other blocks were committed, so their new blocks are kept, while the failed blocks are kept as they were before.
+func appliedRecordBlocks(conf *config.ProviderConf, oldBlocks, newBlocks []interface{}, failed map[string]bool) []interface{} {
+	var blocks []interface{}
+	for _, v := range newBlocks {
+		if !failed[recordsBlockKey(conf, v)] {
+			blocks = append(blocks, v)
+		}
+	}
+	for _, v := range oldBlocks {
+		if failed[recordsBlockKey(conf, v)] {
+			blocks = append(blocks, v)
+		}
+	}
+	return blocks
+}
+
+// recordsFailed returns the error diagnostic summing up a change of which the records of failed record blocks out of
+// changed could not be applied. It is returned ahead of the diagnostics of the failed blocks.
+func recordsFailed(zoneName string, failed, changed int) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("%d of %d record blocks of zone %q could not be applied", failed, changed, zoneName),
+		Detail:   "The records of the other record blocks were applied and are kept in the Terraform state. The record blocks that failed are listed below, and are planned to be applied again.",
+	}}
+}
+
+// asWarnings returns diags with every error turned into a warning.
+func asWarnings(diags diag.Diagnostics) diag.Diagnostics {
+	warnings := make(diag.Diagnostics, 0, len(diags))
+	for _, v := range diags {
+		v.Severity = diag.Warning
+		warnings = append(warnings, v)
+	}
+	return warnings
+}
+
+// recordsInCreateOrder returns the records of recordsFromSet in the order they are created in, see
+// dnshelper.SortRecordsForCreate. They are deleted in the reverse order.
+func recordsInCreateOrder(records map[string]*dnshelper.Record) []*dnshelper.Record {
+	ordered := make([]*dnshelper.Record, 0, len(records))
+	for _, record := range records {
+		ordered = append(ordered, record)
+	}
+	dnshelper.SortRecordsForCreate(ordered)
+	return ordered
+}
+
+// recordsInDeleteOrder returns the records of recordsFromSet in the reverse of the order they are created in.
+func recordsInDeleteOrder(records map[string]*dnshelper.Record) []*dnshelper.Record {
+	ordered := recordsInCreateOrder(records)
+	slices.Reverse(ordered)
+	return ordered
+}
+
+func resourceDNSRecordsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zoneName := d.Get("zone_name").(string)
+	conf := meta.(*config.ProviderConf)
+	records, err := recordsFromSet(conf, zoneName, d.Get("record").(*schema.Set))
+	if err != nil {
+		return diag.Errorf("error when mapping input data: %s", err)
+	}
+
+	// A record block that fails does not stop the others from being created, so a few failures in a large resource
+	// leave the rest of the records committed. Only the blocks created are stored in the state.
+	var errs diag.Diagnostics
+	failed := make(map[string]bool)
+	for _, record := range recordsInCreateOrder(records) {
+		_, err = record.Create(ctx, conf)
+		if err != nil {
+			failed[recordsKey(record.HostName, record.RecordType)] = true
+			errs = append(errs, diag.Errorf("error while creating %s records for %q: %s", record.RecordType, record.HostName, err)...)
+		}
+	}
+	if len(failed) == len(records) {
+		return append(recordsFailed(zoneName, len(failed), len(records)), errs...)
+	}
+	d.SetId(zoneName)
+	diags := waitPostWrite(ctx, conf, true)
+
+	if len(failed) == 0 {
+		return append(diags, resourceDNSRecordsRead(ctx, d, meta)...)
+	}
+	// Terraform taints a resource created with errors, which would replace the records of every block on the next
+	// apply. The failures are therefore reported as warnings, and the blocks that failed are left out of the state so
+	// that the next plan only adds those again.
+	_ = d.Set("record", appliedRecordBlocks(conf, nil, d.Get("record").(*schema.Set).List(), failed))
+	diags = append(append(asWarnings(recordsFailed(zoneName, len(failed), len(records))), asWarnings(errs)...), diags...)
+	return append(diags, resourceDNSRecordsRead(ctx, d, meta)...)
+}
+
+func resourceDNSRecordsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Id() == "" {
+		return nil
+	}
+
+	conf := meta.(*config.ProviderConf)
+	existing, err := dnshelper.GetDNSRecords(ctx, conf, asciiName(d.Id()))
+	if err != nil {
+		if dnshelper.IsNotFound(err) {
+			// The zone was deleted outside of Terraform, remove the records from state to plan their recreation
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error while reading records of zone %q: %s", d.Id(), err)
+	}
+
+	serverRecords := make(map[string]*dnshelper.Record)
+	for _, record := range existing {
+		serverRecords[recordsKey(record.HostName, record.RecordType)] = record
+	}
+
+	// Only the record blocks in the state are read back, as other records in the zone are not managed by the resource.
+	// Blocks whose records are all gone are dropped, and the records of the others are replaced by the server's when
+	// they differ.
+	var blocks []interface{}
+	for _, v := range d.Get("record").(*schema.Set).List() {
+		block := v.(map[string]interface{})
+		record, ok := serverRecords[recordsBlockKey(conf, block)]
+		if !ok {
+			continue
+		}
+		configured := listToStringSlice(block["records"].(*schema.Set).List())
+		values := dnshelper.RecordValues(record.RecordType, record.Records, false)
+		if !suppressRecordDiffForType(configured, values, record.RecordType, false) {
+			block["records"] = values
+		}
+		blocks = append(blocks, block)
+	}
+
+	if len(blocks) == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	_ = d.Set("zone_name", d.Id())
+	_ = d.Set("record", blocks)
+
+	return nil
+}
+
+func resourceDNSRecordsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if !d.HasChange("record") {
+		return resourceDNSRecordsRead(ctx, d, meta)
+	}
+
+	conf := meta.(*config.ProviderConf)
+	oldSet, newSet := d.GetChange("record")
+	oldRecords, err := recordsFromSet(conf, d.Id(), oldSet.(*schema.Set))
+	if err != nil {
+		return diag.Errorf("error when mapping prior state: %s", err)
+	}
+	newRecords, err := recordsFromSet(conf, d.Id(), newSet.(*schema.Set))
+	if err != nil {
+		return diag.Errorf("error when mapping input data: %s", err)
+	}
+
+	// As when creating, a record block that fails does not stop the changes of the others. The failed blocks are kept
+	// in the state as they were, so they are planned to be changed again.
+	var diags, errs diag.Diagnostics
+	failed := make(map[string]bool)
+	changed := 0
+	for _, record := range recordsInDeleteOrder(oldRecords) {
+		key := recordsKey(record.HostName, record.RecordType)
+		if _, ok := newRecords[key]; ok {
+			continue
+		}
+		if dnshelper.IsProtectedRecord(record.HostName, record.RecordType) {
+			diags = append(diags, protectedRecordsLeftInPlace(record.RecordType, record.ZoneName)...)
+			continue
+		}
+		changed++
+		err = record.Delete(ctx, conf)
+		if err != nil {
+			failed[key] = true
+			errs = append(errs, diag.Errorf("error while deleting %s records for %q: %s", record.RecordType, record.HostName, err)...)
+		}
+	}
+
+	for _, record := range recordsInCreateOrder(newRecords) {
+		key := recordsKey(record.HostName, record.RecordType)
+		old, ok := oldRecords[key]
+		if !ok {
+			changed++
+			_, err = record.Create(ctx, conf)
+			if err != nil {
+				failed[key] = true
+				errs = append(errs, diag.Errorf("error while creating %s records for %q: %s", record.RecordType, record.HostName, err)...)
+			}
+			continue
+		}
+		if suppressRecordDiffForType(old.Records, record.Records, record.RecordType, false) {
+			continue
+		}
+
+		records := make([]interface{}, 0, len(record.Records))
+		for _, v := range record.Records {
+			records = append(records, v)
+		}
+		changed++
+		err = record.Update(ctx, conf, map[string]interface{}{"records": records})
+		if err != nil {
+			failed[key] = true
+			errs = append(errs, diag.Errorf("error while updating %s records for %q: %s", record.RecordType, record.HostName, err)...)
+		}
+	}
+
+	diags = append(diags, waitPostWrite(ctx, conf, changed > len(failed))...)
+	if len(failed) > 0 {
+		_ = d.Set("record", appliedRecordBlocks(conf, oldSet.(*schema.Set).List(), newSet.(*schema.Set).List(), failed))
+		diags = append(append(recordsFailed(d.Id(), len(failed), changed), errs...), diags...)
+	}
+	return append(diags, resourceDNSRecordsRead(ctx, d, meta)...)
+}
+
+func resourceDNSRecordsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Id() == "" {
+		return nil
+	}
+	conf := meta.(*config.ProviderConf)
+	records, err := recordsFromSet(conf, d.Id(), d.Get("record").(*schema.Set))
+	if err != nil {
+		return diag.Errorf("error when mapping input data: %s", err)
+	}
+
+	// The NS records at the zone apex are left on the server, as the zone cannot be delegated to without them.
+	var diags, errs diag.Diagnostics
+	failed := make(map[string]bool)
+	changed := 0
+	for _, record := range recordsInDeleteOrder(records) {
+		if dnshelper.IsProtectedRecord(record.HostName, record.RecordType) {
+			diags = append(diags, protectedRecordsLeftInPlace(record.RecordType, record.ZoneName)...)
+			continue
+		}
+		changed++
+		err = record.Delete(ctx, conf)
+		if err != nil {
+			failed[recordsKey(record.HostName, record.RecordType)] = true
+			errs = append(errs, diag.Errorf("error while deleting %s records for %q: %s", record.RecordType, record.HostName, err)...)
+		}
+	}
+
+	// The blocks whose records could not be deleted are kept in the state, so destroying the resource again only deletes
+	// those.
+	if len(failed) > 0 {
+		_ = d.Set("record", appliedRecordBlocks(conf, d.Get("record").(*schema.Set).List(), nil, failed))
+		diags = append(append(recordsFailed(d.Id(), len(failed), changed), errs...), diags...)
+	}
+	return diags
+}
@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+)
+
+const testAccResourceDNSRecordsConfig = `
+variable "windns_record_name" {}
+
+resource "windns_records" "r" {
+  zone_name = "example.com"
+
+  record {
+    name    = var.windns_record_name
+    type    = "A"
+    records = ["203.0.113.11", "203.0.113.12"]
+  }
+
+  record {
+    name    = var.windns_record_name
+    type    = "TXT"
+    records = ["bulk"]
+  }
+}
+`
+
+const testAccResourceDNSRecordsConfigUpdated = `
+variable "windns_record_name" {}
+
+resource "windns_records" "r" {
+  zone_name = "example.com"
+
+  record {
+    name    = var.windns_record_name
+    type    = "A"
+    records = ["203.0.113.11", "203.0.113.13"]
+  }
+
+  record {
+    name    = "${var.windns_record_name}-alias"
+    type    = "CNAME"
+    records = ["${var.windns_record_name}.example.com"]
+  }
+}
+`
+
+// testAccResourceDNSRecordsConfigConflict adds a CNAME record for a name that has A records, which the server rejects,
+// while the other record blocks are left as they were.
+const testAccResourceDNSRecordsConfigConflict = `
+variable "windns_record_name" {}
+
+resource "windns_records" "r" {
+  zone_name = "example.com"
+
+  record {
+    name    = var.windns_record_name
+    type    = "A"
+    records = ["203.0.113.11", "203.0.113.13"]
+  }
+
+  record {
+    name    = "${var.windns_record_name}-alias"
+    type    = "CNAME"
+    records = ["${var.windns_record_name}.example.com"]
+  }
+
+  record {
+    name    = var.windns_record_name
+    type    = "CNAME"
+    records = ["${var.windns_record_name}-alias.example.com"]
+  }
+}
+`
+
+func TestAccResourceDNSRecords(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceDNSRecordsCount("windns_records.r", 0),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRecordsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_records.r", "record.#", "2"),
+					testAccResourceDNSRecordsCount("windns_records.r", 2),
+				),
+			},
+			{
+				Config: testAccResourceDNSRecordsConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_records.r", "record.#", "2"),
+					testAccResourceDNSRecordsCount("windns_records.r", 2),
+				),
+			},
+			{
+				Config:      testAccResourceDNSRecordsConfigConflict,
+				ExpectError: regexp.MustCompile(`1 of 1 record blocks of zone "example.com" could not be applied`),
+			},
+			{
+				Config: testAccResourceDNSRecordsConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_records.r", "record.#", "2"),
+					testAccResourceDNSRecordsCount("windns_records.r", 2),
+				),
+			},
+		},
+	})
+}
+
+func Test_appliedRecordBlocks(t *testing.T) {
+	block := func(name, recordType string, records ...interface{}) interface{} {
+		return map[string]interface{}{"name": name, "type": recordType, "records": records}
+	}
+	oldBlocks := []interface{}{
+		block("www", "A", "203.0.113.11"),
+		block("www", "TXT", "old"),
+		block("mail", "A", "203.0.113.25"),
+	}
+	newBlocks := []interface{}{
+		block("www", "A", "203.0.113.12"),
+		block("www", "TXT", "new"),
+		block("ftp", "CNAME", "www.example.com."),
+	}
+
+	tests := []struct {
+		name   string
+		old    []interface{}
+		new    []interface{}
+		failed map[string]bool
+		want   []interface{}
+	}{
+		{
+			"test-none-failed", oldBlocks, newBlocks, map[string]bool{}, newBlocks,
+		},
+		{
+			"test-update-failed", oldBlocks, newBlocks, map[string]bool{"www" + dnshelper.IDSeparator + "TXT": true},
+			[]interface{}{newBlocks[0], newBlocks[2], oldBlocks[1]},
+		},
+		{
+			"test-create-and-delete-failed", oldBlocks, newBlocks,
+			map[string]bool{"ftp" + dnshelper.IDSeparator + "CNAME": true, "mail" + dnshelper.IDSeparator + "A": true},
+			[]interface{}{newBlocks[0], newBlocks[1], oldBlocks[2]},
+		},
+		{
+			"test-create-failed", nil, newBlocks, map[string]bool{"www" + dnshelper.IDSeparator + "A": true},
+			[]interface{}{newBlocks[1], newBlocks[2]},
+		},
+		{
+			"test-destroy-failed", oldBlocks, nil, map[string]bool{"www" + dnshelper.IDSeparator + "A": true},
+			[]interface{}{oldBlocks[0]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := appliedRecordBlocks(nil, tt.old, tt.new, tt.failed); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("appliedRecordBlocks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_asWarnings(t *testing.T) {
+	errs := append(recordsFailed("example.com", 1, 2), diag.Errorf("error while creating A records for %q: failed", "www")...)
+	got := asWarnings(errs)
+	if got.HasError() || len(got) != len(errs) {
+		t.Fatalf("asWarnings() = %v, want %d warnings", got, len(errs))
+	}
+	for i, v := range got {
+		if v.Summary != errs[i].Summary || v.Detail != errs[i].Detail {
+			t.Errorf("asWarnings()[%d] = %v, want the summary and detail of %v", i, v, errs[i])
+		}
+	}
+	if !errs.HasError() {
+		t.Errorf("asWarnings() changed the severity of its input")
+	}
+}
+
+// testAccResourceDNSRecordsCount checks how many of the record blocks in the state of resource exist on the server.
+func testAccResourceDNSRecordsCount(resource string, expected int) resource.TestCheckFunc {
+	ctx := context.Background()
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("%s key not found in state", resource)
+		}
+
+		existing, err := dnshelper.GetDNSRecords(ctx, testAccProvider.Meta().(*config.ProviderConf), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		keys := make(map[string]bool)
+		for _, r := range existing {
+			keys[recordsKey(r.HostName, r.RecordType)] = true
+		}
+
+		found := 0
+		for k, v := range rs.Primary.Attributes {
+			if !strings.HasPrefix(k, "record.") || !strings.HasSuffix(k, ".name") {
+				continue
+			}
+			if keys[recordsKey(v, rs.Primary.Attributes[strings.TrimSuffix(k, "name")+"type"])] {
+				found++
+			}
+		}
+		if found != expected {
+			return fmt.Errorf("expected %d record blocks of %s on the server, found %d", expected, resource, found)
+		}
+		return nil
+	}
+}
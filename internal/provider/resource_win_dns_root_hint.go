@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+	"golang.org/x/exp/slices"
+)
+
+func resourceDNSRootHint() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windns_root_hint` manages a root hint of a Windows DNS Server: a root name server and its glue addresses.",
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		ReadContext:   resourceDNSRootHintRead,
+		CreateContext: resourceDNSRootHintCreate,
+		UpdateContext: resourceDNSRootHintUpdate,
+		DeleteContext: resourceDNSRootHintDelete,
+		Schema: map[string]*schema.Schema{
+			"name_server": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressFQDNDiff,
+				Description:      "The fully qualified name of the root server, e.g. `a.root-servers.net`, written with or without the trailing `.`.",
+			},
+			"ip_addresses": {
+				Type:             schema.TypeList,
+				Required:         true,
+				MinItems:         1,
+				DiffSuppressFunc: suppressIPAddressListDiff,
+				Description:      "The glue addresses of the root server, IPv4 or IPv6. The order is not significant, as the server keeps none. Each address may only be listed once.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsIPAddress,
+				},
+			},
+		},
+		CustomizeDiff: customizeDiffRootHintAddresses,
+	}
+}
+
+// customizeDiffRootHintAddresses rejects addresses listed more than once, which would otherwise be planned to be added
+// again on every run.
+func customizeDiffRootHintAddresses(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if !d.NewValueKnown("ip_addresses") {
+		return nil
+	}
+	return validateUniqueIPAddresses("ip_addresses", d.Get("ip_addresses").([]interface{}))
+}
+
+// rootHintID returns the ID of the root hint of nameServer, its name with the trailing `.`, as returned by the server.
+func rootHintID(nameServer string) string {
+	return strings.TrimSuffix(nameServer, ".") + "."
+}
+
+// diffIPAddresses returns the addresses of wanted missing from current, and those of current missing from wanted.
+func diffIPAddresses(current, wanted []string) (added, removed []string) {
+	contains := func(addresses []string, address string) bool {
+		return slices.ContainsFunc(addresses, func(v string) bool {
+			return suppressIPAddressDiff("", v, address, nil)
+		})
+	}
+	for _, v := range wanted {
+		if !contains(current, v) {
+			added = append(added, v)
+		}
+	}
+	for _, v := range current {
+		if !contains(wanted, v) {
+			removed = append(removed, v)
+		}
+	}
+	return added, removed
+}
+
+// updateRootHint changes the addresses of the root hint of nameServer from current to wanted. The new addresses are
+// added before the old ones are removed, so the root hint is never left without addresses.
+func updateRootHint(ctx context.Context, conf *config.ProviderConf, nameServer string, current, wanted []string) error {
+	added, removed := diffIPAddresses(current, wanted)
+	if err := dnshelper.AddRootHint(ctx, conf, nameServer, added); err != nil {
+		return err
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	return dnshelper.RemoveRootHint(ctx, conf, nameServer, removed)
+}
+
+func resourceDNSRootHintCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.ProviderConf)
+
+	nameServer, err := dnshelper.SanitizeInputString("", rootHintID(d.Get("name_server").(string)))
+	if err != nil {
+		return diag.Errorf("error when mapping input data: %s", err)
+	}
+
+	// A root hint the server already has, e.g. one of the default root servers, is adopted and given the configured
+	// addresses.
+	var current []string
+	hint, err := dnshelper.GetRootHint(ctx, conf, nameServer)
+	if err != nil && !dnshelper.IsNotFound(err) {
+		return diag.Errorf("error while reading root hint %q: %s", nameServer, err)
+	}
+	if hint != nil {
+		current = hint.IPAddresses
+	}
+
+	err = updateRootHint(ctx, conf, nameServer, current, listToStringSlice(d.Get("ip_addresses").([]interface{})))
+	if err != nil {
+		return diag.Errorf("error while adding root hint %q: %s", nameServer, err)
+	}
+
+	d.SetId(nameServer)
+	return resourceDNSRootHintRead(ctx, d, meta)
+}
+
+func resourceDNSRootHintRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Id() == "" {
+		return nil
+	}
+
+	hint, err := dnshelper.GetRootHint(ctx, meta.(*config.ProviderConf), d.Id())
+	if err != nil {
+		if dnshelper.IsNotFound(err) {
+			// The root hint was removed outside of Terraform, remove it from state to plan its recreation
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error while reading root hint %q: %s", d.Id(), err)
+	}
+
+	if current := d.Get("name_server").(string); current == "" || !suppressFQDNDiff("", current, hint.NameServer, nil) {
+		_ = d.Set("name_server", hint.NameServer)
+	}
+	_ = d.Set("ip_addresses", hint.IPAddresses)
+	return nil
+}
+
+func resourceDNSRootHintUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.HasChange("ip_addresses") {
+		old, new := d.GetChange("ip_addresses")
+		err := updateRootHint(ctx, meta.(*config.ProviderConf), d.Id(), listToStringSlice(old.([]interface{})), listToStringSlice(new.([]interface{})))
+		if err != nil {
+			return diag.Errorf("error while updating root hint %q: %s", d.Id(), err)
+		}
+	}
+	return resourceDNSRootHintRead(ctx, d, meta)
+}
+
+func resourceDNSRootHintDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	err := dnshelper.RemoveRootHint(ctx, meta.(*config.ProviderConf), d.Id(), nil)
+	if err != nil && !dnshelper.IsNotFound(err) {
+		return diag.Errorf("error while removing root hint %q: %s", d.Id(), err)
+	}
+	return nil
+}
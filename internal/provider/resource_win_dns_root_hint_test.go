@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+)
+
+const testAccResourceDNSRootHintConfig = `
+resource "windns_root_hint" "h" {
+  name_server  = "root.example.test"
+  ip_addresses = ["192.0.2.1", "2001:db8::1"]
+}
+`
+
+const testAccResourceDNSRootHintConfigReordered = `
+resource "windns_root_hint" "h" {
+  name_server  = "root.example.test."
+  ip_addresses = ["2001:DB8:0::1", "192.0.2.1"]
+}
+`
+
+const testAccResourceDNSRootHintConfigDuplicate = `
+resource "windns_root_hint" "h" {
+  name_server  = "root.example.test"
+  ip_addresses = ["192.0.2.1", "192.0.2.1"]
+}
+`
+
+const testAccResourceDNSRootHintConfigUpdated = `
+resource "windns_root_hint" "h" {
+  name_server  = "root.example.test"
+  ip_addresses = ["192.0.2.2", "2001:db8::1"]
+}
+`
+
+func Test_diffIPAddresses(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     []string
+		wanted      []string
+		wantAdded   []string
+		wantRemoved []string
+	}{
+		{"test-same", []string{"192.0.2.1", "2001:db8::1"}, []string{"192.0.2.1", "2001:db8::1"}, nil, nil},
+		{"test-reordered-casemix", []string{"192.0.2.1", "2001:db8::1"}, []string{"2001:DB8:0::1", "192.0.2.1"}, nil, nil},
+		{"test-new", nil, []string{"192.0.2.1"}, []string{"192.0.2.1"}, nil},
+		{"test-replaced", []string{"192.0.2.1", "2001:db8::1"}, []string{"192.0.2.2", "2001:db8::1"}, []string{"192.0.2.2"}, []string{"192.0.2.1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed := diffIPAddresses(tt.current, tt.wanted)
+			if !reflect.DeepEqual(added, tt.wantAdded) || !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("diffIPAddresses() = %q, %q, want %q, %q", added, removed, tt.wantAdded, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestAccResourceDNSRootHint(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, nil) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccResourceDNSRootHintExists("windns_root_hint.h", nil, false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSRootHintConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRootHintExists("windns_root_hint.h", []string{"192.0.2.1", "2001:db8::1"}, true),
+					resource.TestCheckResourceAttr("windns_root_hint.h", "id", "root.example.test."),
+					resource.TestCheckResourceAttr("windns_root_hint.h", "ip_addresses.#", "2"),
+				),
+			},
+			{
+				Config:   testAccResourceDNSRootHintConfigReordered,
+				PlanOnly: true,
+			},
+			{
+				Config:      testAccResourceDNSRootHintConfigDuplicate,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ip_addresses lists 192.0.2.1 more than once"),
+			},
+			{
+				Config: testAccResourceDNSRootHintConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRootHintExists("windns_root_hint.h", []string{"192.0.2.2", "2001:db8::1"}, true),
+				),
+			},
+			{
+				ResourceName:      "windns_root_hint.h",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testAccResourceDNSRootHintExists checks whether the root hint of resource exists on the server with the addresses
+// expectedAddresses, in any order.
+func testAccResourceDNSRootHintExists(resource string, expectedAddresses []string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("%s key not found in state", resource)
+		}
+
+		hint, err := dnshelper.GetRootHint(context.Background(), testAccProvider.Meta().(*config.ProviderConf), rs.Primary.ID)
+		if err != nil {
+			if dnshelper.IsNotFound(err) && !expected {
+				return nil
+			}
+			return err
+		}
+		if !expected {
+			return fmt.Errorf("root hint %s still exists", rs.Primary.ID)
+		}
+
+		added, removed := diffIPAddresses(hint.IPAddresses, expectedAddresses)
+		if len(added) > 0 || len(removed) > 0 {
+			return fmt.Errorf("root hint %s has the addresses %q, expected %q", rs.Primary.ID, hint.IPAddresses, expectedAddresses)
+		}
+		return nil
+	}
+}
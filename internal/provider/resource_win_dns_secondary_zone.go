@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+)
+
+func resourceDNSSecondaryZone() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windns_secondary_zone` manages secondary zones in a Windows DNS Server.",
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		ReadContext:   resourceDNSSecondaryZoneRead,
+		CreateContext: resourceDNSSecondaryZoneCreate,
+		UpdateContext: resourceDNSSecondaryZoneUpdate,
+		DeleteContext: resourceDNSSecondaryZoneDelete,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The name of the zone.",
+			},
+			"master_servers": {
+				Type:             schema.TypeList,
+				Required:         true,
+				MinItems:         1,
+				DiffSuppressFunc: suppressIPAddressDiff,
+				Description:      "The IP addresses of the servers the zone is transferred from, in the order they are tried. The order is kept on the server, so reordering the list updates the zone. Each address may only be listed once.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsIPAddress,
+				},
+			},
+			"zone_file": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The name of the file the zone is stored in on the DNS server. Defaults to `<name>.dns`.",
+			},
+		},
+		CustomizeDiff: customizeDiffMasterServers,
+	}
+}
+
+// customizeDiffMasterServers rejects master servers listed more than once, which would otherwise be planned to be
+// added again on every run.
+func customizeDiffMasterServers(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if !d.NewValueKnown("master_servers") {
+		return nil
+	}
+	return validateUniqueIPAddresses("master_servers", d.Get("master_servers").([]interface{}))
+}
+
+func resourceDNSSecondaryZoneCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zoneName, err := dnshelper.SanitizeInputString("", d.Get("name").(string))
+	if err != nil {
+		return diag.Errorf("error when mapping input data: %s", err)
+	}
+
+	zoneFile := d.Get("zone_file").(string)
+	if zoneFile == "" {
+		zoneFile = fmt.Sprintf("%s.dns", zoneName)
+	}
+	zoneFile, err = dnshelper.SanitizeInputString("", zoneFile)
+	if err != nil {
+		return diag.Errorf("error when mapping input data: %s", err)
+	}
+
+	err = dnshelper.AddSecondaryZone(ctx, meta.(*config.ProviderConf), zoneName, zoneFile, listToStringSlice(d.Get("master_servers").([]interface{})))
+	if err != nil {
+		return diag.Errorf("error while creating secondary zone %q: %s", zoneName, err)
+	}
+
+	d.SetId(zoneName)
+	return resourceDNSSecondaryZoneRead(ctx, d, meta)
+}
+
+func resourceDNSSecondaryZoneRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Id() == "" {
+		return nil
+	}
+
+	zone, err := dnshelper.GetDNSZone(ctx, meta.(*config.ProviderConf), d.Id())
+	if err != nil {
+		if dnshelper.IsNotFound(err) {
+			// The zone was deleted outside of Terraform, remove it from state to plan its recreation
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error while reading zone %q: %s", d.Id(), err)
+	}
+
+	if zone.ZoneType != "Secondary" {
+		return diag.Errorf("zone %q is a %s zone, not a secondary zone", d.Id(), zone.ZoneType)
+	}
+
+	_ = d.Set("name", preserveCase(d.Get("name").(string), zone.ZoneName))
+	_ = d.Set("master_servers", zone.MasterServerAddresses())
+	_ = d.Set("zone_file", zone.ZoneFile)
+	return nil
+}
+
+func resourceDNSSecondaryZoneUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.HasChange("master_servers") {
+		err := dnshelper.SetSecondaryZoneMasterServers(ctx, meta.(*config.ProviderConf), d.Id(), listToStringSlice(d.Get("master_servers").([]interface{})))
+		if err != nil {
+			return diag.Errorf("error while updating master servers of zone %q: %s", d.Id(), err)
+		}
+	}
+	return resourceDNSSecondaryZoneRead(ctx, d, meta)
+}
+
+func resourceDNSSecondaryZoneDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	err := dnshelper.RemoveDNSZone(ctx, meta.(*config.ProviderConf), d.Id())
+	if err != nil && !dnshelper.IsNotFound(err) {
+		return diag.Errorf("error while deleting zone %q: %s", d.Id(), err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccResourceDNSSecondaryZoneConfig = `
+variable "windns_secondary_zone_name" {}
+
+resource "windns_secondary_zone" "z" {
+  name           = var.windns_secondary_zone_name
+  master_servers = ["203.0.113.11", "203.0.113.12", "203.0.113.13"]
+}
+`
+
+const testAccResourceDNSSecondaryZoneConfigReordered = `
+variable "windns_secondary_zone_name" {}
+
+resource "windns_secondary_zone" "z" {
+  name           = var.windns_secondary_zone_name
+  master_servers = ["203.0.113.12", "203.0.113.13", "203.0.113.11"]
+}
+`
+
+const testAccResourceDNSSecondaryZoneConfigDuplicate = `
+variable "windns_secondary_zone_name" {}
+
+resource "windns_secondary_zone" "z" {
+  name           = var.windns_secondary_zone_name
+  master_servers = ["203.0.113.11", "203.0.113.11"]
+}
+`
+
+const testAccResourceDNSSecondaryZoneConfigUpdated = `
+variable "windns_secondary_zone_name" {}
+
+resource "windns_secondary_zone" "z" {
+  name           = var.windns_secondary_zone_name
+  master_servers = ["203.0.113.13", "203.0.113.11"]
+}
+`
+
+func TestAccResourceDNSSecondaryZone(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_secondary_zone_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSSecondaryZoneConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_secondary_zone.z", "master_servers.#", "3"),
+					resource.TestCheckResourceAttr("windns_secondary_zone.z", "master_servers.0", "203.0.113.11"),
+					resource.TestCheckResourceAttr("windns_secondary_zone.z", "master_servers.1", "203.0.113.12"),
+					resource.TestCheckResourceAttr("windns_secondary_zone.z", "master_servers.2", "203.0.113.13"),
+					resource.TestCheckResourceAttrSet("windns_secondary_zone.z", "zone_file"),
+				),
+			},
+			{
+				Config: testAccResourceDNSSecondaryZoneConfigReordered,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_secondary_zone.z", "master_servers.#", "3"),
+					resource.TestCheckResourceAttr("windns_secondary_zone.z", "master_servers.0", "203.0.113.12"),
+					resource.TestCheckResourceAttr("windns_secondary_zone.z", "master_servers.1", "203.0.113.13"),
+					resource.TestCheckResourceAttr("windns_secondary_zone.z", "master_servers.2", "203.0.113.11"),
+				),
+			},
+			{
+				Config:      testAccResourceDNSSecondaryZoneConfigDuplicate,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("master_servers lists 203.0.113.11 more than once"),
+			},
+			{
+				Config: testAccResourceDNSSecondaryZoneConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_secondary_zone.z", "master_servers.#", "2"),
+					resource.TestCheckResourceAttr("windns_secondary_zone.z", "master_servers.0", "203.0.113.13"),
+					resource.TestCheckResourceAttr("windns_secondary_zone.z", "master_servers.1", "203.0.113.11"),
+				),
+			},
+			{
+				ResourceName:      "windns_secondary_zone.z",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
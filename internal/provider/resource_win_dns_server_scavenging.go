@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+)
+
+// scavengingParams maps the attributes of windns_server_scavenging to the Set-DnsServerScavenging parameters they
+// manage.
+var scavengingParams = map[string]string{
+	"scavenging_state":    "ScavengingState",
+	"scavenging_interval": "ScavengingInterval",
+	"refresh_interval":    "RefreshInterval",
+	"no_refresh_interval": "NoRefreshInterval",
+}
+
+func resourceDNSServerScavenging() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windns_server_scavenging` manages the scavenging configuration of a Windows DNS Server.",
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDNSServerScavengingImport,
+		},
+		ReadContext:   resourceDNSServerScavengingRead,
+		CreateContext: resourceDNSServerScavengingCreate,
+		UpdateContext: resourceDNSServerScavengingUpdate,
+		DeleteContext: resourceDNSServerScavengingDelete,
+		Schema: map[string]*schema.Schema{
+			"scavenging_state": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether aging is enabled by default on zones created on the server. Read from the server when unset.",
+			},
+			"scavenging_interval": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The number of hours between the scavenging runs of the server. `0` disables scavenging. Read from the server when unset.",
+			},
+			"refresh_interval": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The default refresh interval, in hours, of zones created on the server. Read from the server when unset.",
+			},
+			"no_refresh_interval": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The default no-refresh interval, in hours, of zones created on the server. Read from the server when unset.",
+			},
+			"last_scavenge_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time the server last scavenged stale records, in RFC 3339 format. Empty if it never has.",
+			},
+		},
+	}
+}
+
+// serverID returns the ID of server level settings, e.g. the scavenging configuration, of the DNS server the provider
+// manages.
+func serverID(conf *config.ProviderConf) string {
+	return conf.Settings.SshHostname + dnshelper.IDSeparator + conf.Settings.DnsServer
+}
+
+func resourceDNSServerScavengingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.ProviderConf)
+
+	// Every server has a scavenging configuration, so creating the resource adopts it and applies the configured fields.
+	// false and 0 are meaningful values, so the configuration tells which fields are set.
+	changes := make(map[string]any)
+	raw := d.GetRawConfig()
+	for attr, param := range scavengingParams {
+		if !raw.IsNull() && !raw.GetAttr(attr).IsNull() {
+			changes[param] = d.Get(attr)
+		}
+	}
+
+	scavenging := dnshelper.ServerScavenging{}
+	err := scavenging.Update(ctx, conf, changes)
+	if err != nil {
+		return diag.Errorf("error while updating scavenging of the DNS server: %s", err)
+	}
+
+	d.SetId(serverID(conf))
+	return resourceDNSServerScavengingRead(ctx, d, meta)
+}
+
+func resourceDNSServerScavengingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Id() == "" {
+		return nil
+	}
+
+	scavenging, err := dnshelper.GetServerScavenging(ctx, meta.(*config.ProviderConf))
+	if err != nil {
+		return diag.Errorf("error while reading scavenging of the DNS server: %s", err)
+	}
+
+	_ = d.Set("scavenging_state", scavenging.ScavengingState)
+	_ = d.Set("scavenging_interval", scavenging.ScavengingInterval)
+	_ = d.Set("refresh_interval", scavenging.RefreshInterval)
+	_ = d.Set("no_refresh_interval", scavenging.NoRefreshInterval)
+	lastScavengeTime := ""
+	if !scavenging.LastScavengeTime.IsZero() {
+		lastScavengeTime = scavenging.LastScavengeTime.Format(time.RFC3339)
+	}
+	_ = d.Set("last_scavenge_time", lastScavengeTime)
+
+	return nil
+}
+
+func resourceDNSServerScavengingUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	changes := make(map[string]any)
+	for attr, param := range scavengingParams {
+		if d.HasChange(attr) {
+			changes[param] = d.Get(attr)
+		}
+	}
+
+	scavenging := dnshelper.ServerScavenging{}
+	err := scavenging.Update(ctx, meta.(*config.ProviderConf), changes)
+	if err != nil {
+		return diag.Errorf("error while updating scavenging of the DNS server: %s", err)
+	}
+	return resourceDNSServerScavengingRead(ctx, d, meta)
+}
+
+// A server always has a scavenging configuration, so deleting the resource only removes it from the state.
+func resourceDNSServerScavengingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+// resourceDNSServerScavengingImport imports the scavenging configuration of the DNS server the provider manages,
+// whatever ID is given.
+func resourceDNSServerScavengingImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.SetId(serverID(meta.(*config.ProviderConf)))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccResourceDNSServerScavengingConfigBasic = `
+resource "windns_server_scavenging" "scavenging" {
+  scavenging_state    = true
+  scavenging_interval = 168
+  refresh_interval    = 168
+  no_refresh_interval = 168
+}
+`
+
+const testAccResourceDNSServerScavengingConfigDisabled = `
+resource "windns_server_scavenging" "scavenging" {
+  scavenging_state    = false
+  scavenging_interval = 0
+}
+`
+
+func TestAccResourceDNSServerScavenging_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSServerScavengingConfigBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_server_scavenging.scavenging", "scavenging_state", "true"),
+					resource.TestCheckResourceAttr("windns_server_scavenging.scavenging", "scavenging_interval", "168"),
+					resource.TestCheckResourceAttr("windns_server_scavenging.scavenging", "refresh_interval", "168"),
+					resource.TestCheckResourceAttr("windns_server_scavenging.scavenging", "no_refresh_interval", "168"),
+				),
+			},
+			{
+				Config: testAccResourceDNSServerScavengingConfigDisabled,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_server_scavenging.scavenging", "scavenging_state", "false"),
+					resource.TestCheckResourceAttr("windns_server_scavenging.scavenging", "scavenging_interval", "0"),
+					resource.TestCheckResourceAttr("windns_server_scavenging.scavenging", "refresh_interval", "168"),
+				),
+			},
+			{
+				ResourceName:      "windns_server_scavenging.scavenging",
+				ImportState:       true,
+				ImportStateId:     "dc01",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+)
+
+func resourceDNSZone() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windns_zone` manages primary zones in a Windows DNS Server.",
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		ReadContext:   resourceDNSZoneRead,
+		CreateContext: resourceDNSZoneCreate,
+		DeleteContext: resourceDNSZoneDelete,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The name of the zone.",
+			},
+			"replication_scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"Forest", "Domain", "Legacy"}, false),
+				Description:  "Store the zone in Active Directory, replicated to the DNS servers of the `Forest`, of the `Domain`, or to all domain controllers of the domain (`Legacy`). The DNS server must be a domain controller. By default the zone is file-backed, stored in `zone_file`.",
+			},
+			"zone_file": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ConflictsWith:    []string{"replication_scope"},
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The name of the file a file-backed zone is stored in on the DNS server. Cannot be set with `replication_scope`. Defaults to `<name>.dns` for file-backed zones, and is empty for zones stored in Active Directory.",
+			},
+		},
+	}
+}
+
+func resourceDNSZoneCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zoneName, err := dnshelper.SanitizeInputString("", d.Get("name").(string))
+	if err != nil {
+		return diag.Errorf("error when mapping input data: %s", err)
+	}
+
+	replicationScope := d.Get("replication_scope").(string)
+	var zoneFile string
+	if replicationScope == "" {
+		zoneFile = d.Get("zone_file").(string)
+		if zoneFile == "" {
+			zoneFile = fmt.Sprintf("%s.dns", zoneName)
+		}
+		zoneFile, err = dnshelper.SanitizeInputString("", zoneFile)
+		if err != nil {
+			return diag.Errorf("error when mapping input data: %s", err)
+		}
+	}
+
+	err = dnshelper.AddPrimaryZone(ctx, meta.(*config.ProviderConf), zoneName, replicationScope, zoneFile)
+	if err != nil {
+		return diag.Errorf("error while creating primary zone %q: %s", zoneName, err)
+	}
+
+	d.SetId(zoneName)
+	return resourceDNSZoneRead(ctx, d, meta)
+}
+
+func resourceDNSZoneRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Id() == "" {
+		return nil
+	}
+
+	zone, err := dnshelper.GetDNSZone(ctx, meta.(*config.ProviderConf), d.Id())
+	if err != nil {
+		if dnshelper.IsNotFound(err) {
+			// The zone was deleted outside of Terraform, remove it from state to plan its recreation
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error while reading zone %q: %s", d.Id(), err)
+	}
+
+	if zone.ZoneType != "Primary" {
+		return diag.Errorf("zone %q is a %s zone, not a primary zone", d.Id(), zone.ZoneType)
+	}
+
+	// File-backed zones have the replication scope None.
+	replicationScope := ""
+	if zone.IsDsIntegrated {
+		replicationScope = zone.ReplicationScope
+	}
+
+	_ = d.Set("name", preserveCase(d.Get("name").(string), zone.ZoneName))
+	_ = d.Set("replication_scope", replicationScope)
+	_ = d.Set("zone_file", zone.ZoneFile)
+	return nil
+}
+
+func resourceDNSZoneDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	err := dnshelper.RemoveDNSZone(ctx, meta.(*config.ProviderConf), d.Id())
+	if err != nil && !dnshelper.IsNotFound(err) {
+		return diag.Errorf("error while deleting zone %q: %s", d.Id(), err)
+	}
+	return nil
+}
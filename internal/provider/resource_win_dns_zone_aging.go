@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+)
+
+// agingParams maps the attributes of windns_zone_aging to the Set-DnsServerZoneAging parameters they manage.
+var agingParams = map[string]string{
+	"aging_enabled":       "Aging",
+	"refresh_interval":    "RefreshInterval",
+	"no_refresh_interval": "NoRefreshInterval",
+	"scavenge_servers":    "ScavengeServers",
+}
+
+func resourceDNSZoneAging() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windns_zone_aging` manages the aging and scavenging configuration of a zone in a Windows DNS Server.",
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		ReadContext:   resourceDNSZoneAgingRead,
+		CreateContext: resourceDNSZoneAgingCreate,
+		UpdateContext: resourceDNSZoneAgingUpdate,
+		DeleteContext: resourceDNSZoneAgingDelete,
+		Schema: map[string]*schema.Schema{
+			"zone_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The zone whose aging configuration is managed.",
+			},
+			"aging_enabled": {
+				Type:        schema.TypeBool,
+				Required:    true,
+				Description: "Whether aging is enabled for the zone. Only records in zones with aging enabled are scavenged.",
+			},
+			"refresh_interval": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The number of hours after the no-refresh interval during which a dynamic record can be refreshed, before it may be scavenged.",
+			},
+			"no_refresh_interval": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The number of hours after a dynamic record's timestamp is refreshed during which refreshes are not written to the zone.",
+			},
+			"scavenge_servers": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Description: "The IP addresses of the DNS servers allowed to scavenge the zone. Any server with scavenging enabled may scavenge the zone when the list is empty. Read from the server when unset.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsIPAddress,
+				},
+			},
+		},
+	}
+}
+
+// agingParamValue returns the value of attr in the form Set-DnsServerZoneAging takes it.
+func agingParamValue(d *schema.ResourceData, attr string) any {
+	if set, ok := d.Get(attr).(*schema.Set); ok {
+		return listToStringSlice(set.List())
+	}
+	return d.Get(attr)
+}
+
+func resourceDNSZoneAgingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zoneName, err := dnshelper.SanitizeInputString("", d.Get("zone_name").(string))
+	if err != nil {
+		return diag.Errorf("error when mapping input data: %s", err)
+	}
+
+	// Every zone has an aging configuration, so creating the resource adopts it and applies the configured fields.
+	changes := map[string]any{"Aging": d.Get("aging_enabled")}
+	for attr, param := range agingParams {
+		if _, ok := d.GetOk(attr); ok {
+			changes[param] = agingParamValue(d, attr)
+		}
+	}
+
+	aging := dnshelper.ZoneAging{ZoneName: zoneName}
+	err = aging.Update(ctx, meta.(*config.ProviderConf), changes)
+	if err != nil {
+		return diag.Errorf("error while updating aging of zone %q: %s", zoneName, err)
+	}
+
+	d.SetId(zoneName)
+	return resourceDNSZoneAgingRead(ctx, d, meta)
+}
+
+func resourceDNSZoneAgingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Id() == "" {
+		return nil
+	}
+
+	aging, err := dnshelper.GetZoneAging(ctx, meta.(*config.ProviderConf), d.Id())
+	if err != nil {
+		if dnshelper.IsNotFound(err) {
+			// The zone no longer exists
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error while reading aging of zone %q: %s", d.Id(), err)
+	}
+
+	_ = d.Set("zone_name", d.Id())
+	_ = d.Set("aging_enabled", aging.AgingEnabled)
+	_ = d.Set("refresh_interval", aging.RefreshInterval)
+	_ = d.Set("no_refresh_interval", aging.NoRefreshInterval)
+	_ = d.Set("scavenge_servers", aging.ScavengeServers)
+
+	return nil
+}
+
+func resourceDNSZoneAgingUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	changes := make(map[string]any)
+	for attr, param := range agingParams {
+		if d.HasChange(attr) {
+			changes[param] = agingParamValue(d, attr)
+		}
+	}
+
+	aging := dnshelper.ZoneAging{ZoneName: d.Id()}
+	err := aging.Update(ctx, meta.(*config.ProviderConf), changes)
+	if err != nil {
+		return diag.Errorf("error while updating aging of zone %q: %s", d.Id(), err)
+	}
+	return resourceDNSZoneAgingRead(ctx, d, meta)
+}
+
+// A zone always has an aging configuration, so deleting the resource only removes it from the state.
+func resourceDNSZoneAgingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
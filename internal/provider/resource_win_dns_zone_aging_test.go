@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccResourceDNSZoneAgingConfigBasic = `
+resource "windns_zone_aging" "aging" {
+  zone_name           = "example.com"
+  aging_enabled       = true
+  refresh_interval    = 168
+  no_refresh_interval = 168
+}
+`
+
+const testAccResourceDNSZoneAgingConfigScavengeServers = `
+resource "windns_zone_aging" "aging" {
+  zone_name        = "example.com"
+  aging_enabled    = true
+  scavenge_servers = ["10.1.1.1"]
+}
+`
+
+const testAccResourceDNSZoneAgingConfigDisabled = `
+resource "windns_zone_aging" "aging" {
+  zone_name     = "example.com"
+  aging_enabled = false
+}
+`
+
+func TestAccResourceDNSZoneAging_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSZoneAgingConfigBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_zone_aging.aging", "aging_enabled", "true"),
+					resource.TestCheckResourceAttr("windns_zone_aging.aging", "refresh_interval", "168"),
+					resource.TestCheckResourceAttr("windns_zone_aging.aging", "no_refresh_interval", "168"),
+				),
+			},
+			{
+				Config: testAccResourceDNSZoneAgingConfigScavengeServers,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_zone_aging.aging", "scavenge_servers.#", "1"),
+					resource.TestCheckTypeSetElemAttr("windns_zone_aging.aging", "scavenge_servers.*", "10.1.1.1"),
+				),
+			},
+			{
+				Config: testAccResourceDNSZoneAgingConfigDisabled,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_zone_aging.aging", "aging_enabled", "false"),
+				),
+			},
+			{
+				ResourceName:      "windns_zone_aging.aging",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+)
+
+// defaultZoneSigningTimeout is used for each operation unless overridden in the resource's timeouts block.
+// Signing a large zone can take a while, as every record set is signed.
+const defaultZoneSigningTimeout = 30 * time.Minute
+
+func resourceDNSZoneSigning() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windns_zone_signing` signs a zone with DNSSEC in a Windows DNS Server.",
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultZoneSigningTimeout),
+			Read:   schema.DefaultTimeout(defaultZoneSigningTimeout),
+			Delete: schema.DefaultTimeout(defaultZoneSigningTimeout),
+		},
+		ReadContext:   resourceDNSZoneSigningRead,
+		CreateContext: resourceDNSZoneSigningCreate,
+		DeleteContext: resourceDNSZoneSigningDelete,
+		Schema: map[string]*schema.Schema{
+			"zone_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The zone to sign.",
+			},
+		},
+	}
+}
+
+func resourceDNSZoneSigningCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zoneName, err := dnshelper.SanitizeInputString("", d.Get("zone_name").(string))
+	if err != nil {
+		return diag.Errorf("error when mapping input data: %s", err)
+	}
+
+	err = dnshelper.SignZone(ctx, meta.(*config.ProviderConf), zoneName)
+	if err != nil {
+		return diag.Errorf("error while signing zone %q: %s", zoneName, err)
+	}
+
+	d.SetId(zoneName)
+	return resourceDNSZoneSigningRead(ctx, d, meta)
+}
+
+func resourceDNSZoneSigningRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Id() == "" {
+		return nil
+	}
+
+	zone, err := dnshelper.GetDNSZone(ctx, meta.(*config.ProviderConf), d.Id())
+	if err != nil {
+		if dnshelper.IsNotFound(err) {
+			// The zone no longer exists
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error while reading zone %q: %s", d.Id(), err)
+	}
+
+	if !zone.IsSigned {
+		// The zone was unsigned outside of Terraform
+		d.SetId("")
+		return nil
+	}
+
+	_ = d.Set("zone_name", preserveCase(d.Get("zone_name").(string), zone.ZoneName))
+	return nil
+}
+
+func resourceDNSZoneSigningDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	err := dnshelper.UnsignZone(ctx, meta.(*config.ProviderConf), d.Id())
+	if err != nil && !dnshelper.IsNotFound(err) {
+		return diag.Errorf("error while unsigning zone %q: %s", d.Id(), err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccResourceDNSZoneSigningConfig = `
+variable "windns_signed_zone_name" {}
+
+resource "windns_zone_signing" "z" {
+  zone_name = var.windns_signed_zone_name
+}
+`
+
+func TestAccResourceDNSZoneSigning(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_signed_zone_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSZoneSigningConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("windns_zone_signing.z", "zone_name"),
+				),
+			},
+			{
+				ResourceName:      "windns_zone_signing.z",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
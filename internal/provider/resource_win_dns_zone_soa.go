@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+)
+
+// soaFields maps the attributes of windns_zone_soa to the SOA fields they manage.
+var soaFields = map[string]string{
+	"primary_server":     "PrimaryServer",
+	"responsible_person": "ResponsiblePerson",
+	"serial_number":      "SerialNumber",
+	"refresh":            "RefreshInterval",
+	"retry":              "RetryDelay",
+	"expire":             "ExpireLimit",
+	"minimum_ttl":        "MinimumTimeToLive",
+}
+
+func resourceDNSZoneSOA() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windns_zone_soa` manages the SOA record of a zone in a Windows DNS Server.",
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		ReadContext:   resourceDNSZoneSOARead,
+		CreateContext: resourceDNSZoneSOACreate,
+		UpdateContext: resourceDNSZoneSOAUpdate,
+		DeleteContext: resourceDNSZoneSOADelete,
+		Schema: map[string]*schema.Schema{
+			"zone_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The zone whose SOA record is managed.",
+			},
+			"primary_server": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressFQDNDiff,
+				Description:      "The primary name server of the zone.",
+			},
+			"responsible_person": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressFQDNDiff,
+				Description:      "The mailbox of the person responsible for the zone, with the `@` written as a `.`, e.g. `hostmaster.example.com`.",
+			},
+			"serial_number": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validation.IntAtLeast(0),
+				DiffSuppressFunc: suppressSerialDiff,
+				Description:      "The serial number of the zone. The server increments the serial number whenever the zone changes, so a configured serial number only causes a change while it is ahead of the server's.",
+			},
+			"refresh": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The number of seconds secondary servers wait before checking the zone for changes.",
+			},
+			"retry": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The number of seconds secondary servers wait before retrying a failed zone transfer.",
+			},
+			"expire": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The number of seconds secondary servers keep answering for the zone without reaching the primary server.",
+			},
+			"minimum_ttl": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The minimum TTL of the zone in seconds, which is also how long negative answers are cached.",
+			},
+		},
+	}
+}
+
+func resourceDNSZoneSOACreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zoneName, err := dnshelper.SanitizeInputString("", d.Get("zone_name").(string))
+	if err != nil {
+		return diag.Errorf("error when mapping input data: %s", err)
+	}
+
+	// Every zone has a SOA record, so creating the resource adopts it and applies the configured fields.
+	changes := make(map[string]any)
+	for attr, field := range soaFields {
+		if v, ok := d.GetOk(attr); ok {
+			changes[field] = v
+		}
+	}
+
+	soa := dnshelper.SOA{ZoneName: zoneName}
+	err = soa.Update(ctx, meta.(*config.ProviderConf), changes)
+	if err != nil {
+		return diag.Errorf("error while updating SOA record of zone %q: %s", zoneName, err)
+	}
+
+	d.SetId(zoneName)
+	return resourceDNSZoneSOARead(ctx, d, meta)
+}
+
+func resourceDNSZoneSOARead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Id() == "" {
+		return nil
+	}
+
+	soa, err := dnshelper.GetSOA(ctx, meta.(*config.ProviderConf), d.Id())
+	if err != nil {
+		if dnshelper.IsNotFound(err) {
+			// The zone no longer exists
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error while reading SOA record of zone %q: %s", d.Id(), err)
+	}
+
+	_ = d.Set("zone_name", d.Id())
+	_ = d.Set("primary_server", soa.PrimaryServer)
+	_ = d.Set("responsible_person", soa.ResponsiblePerson)
+	_ = d.Set("serial_number", soa.SerialNumber)
+	_ = d.Set("refresh", soa.RefreshInterval)
+	_ = d.Set("retry", soa.RetryDelay)
+	_ = d.Set("expire", soa.ExpireLimit)
+	_ = d.Set("minimum_ttl", soa.MinimumTimeToLive)
+
+	return nil
+}
+
+func resourceDNSZoneSOAUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	changes := make(map[string]any)
+	for attr, field := range soaFields {
+		if d.HasChange(attr) {
+			changes[field] = d.Get(attr)
+		}
+	}
+
+	soa := dnshelper.SOA{ZoneName: d.Id()}
+	err := soa.Update(ctx, meta.(*config.ProviderConf), changes)
+	if err != nil {
+		return diag.Errorf("error while updating SOA record of zone %q: %s", d.Id(), err)
+	}
+	return resourceDNSZoneSOARead(ctx, d, meta)
+}
+
+// A zone cannot exist without its SOA record, so deleting the resource only removes it from the state.
+func resourceDNSZoneSOADelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
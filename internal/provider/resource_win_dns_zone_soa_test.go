@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccResourceDNSZoneSOAConfigBasic = `
+resource "windns_zone_soa" "soa" {
+  zone_name   = "example.com"
+  refresh     = 900
+  retry       = 600
+  expire      = 86400
+  minimum_ttl = 3600
+}
+`
+
+const testAccResourceDNSZoneSOAConfigUpdated = `
+resource "windns_zone_soa" "soa" {
+  zone_name   = "example.com"
+  refresh     = 1800
+  retry       = 600
+  expire      = 86400
+  minimum_ttl = 300
+}
+`
+
+func TestAccResourceDNSZoneSOA_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSZoneSOAConfigBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_zone_soa.soa", "refresh", "900"),
+					resource.TestCheckResourceAttr("windns_zone_soa.soa", "minimum_ttl", "3600"),
+					resource.TestCheckResourceAttrSet("windns_zone_soa.soa", "primary_server"),
+					resource.TestCheckResourceAttrSet("windns_zone_soa.soa", "serial_number"),
+				),
+			},
+			{
+				Config: testAccResourceDNSZoneSOAConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_zone_soa.soa", "refresh", "1800"),
+					resource.TestCheckResourceAttr("windns_zone_soa.soa", "minimum_ttl", "300"),
+				),
+			},
+			{
+				ResourceName:      "windns_zone_soa.soa",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
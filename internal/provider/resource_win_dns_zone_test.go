@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccResourceDNSZoneConfig = `
+variable "windns_zone_name" {}
+
+resource "windns_zone" "z" {
+  name = var.windns_zone_name
+}
+`
+
+const testAccResourceDNSZoneConfigZoneFile = `
+variable "windns_zone_name" {}
+
+resource "windns_zone" "z" {
+  name      = var.windns_zone_name
+  zone_file = "${var.windns_zone_name}.custom.dns"
+}
+`
+
+const testAccResourceDNSZoneConfigConflict = `
+variable "windns_zone_name" {}
+
+resource "windns_zone" "z" {
+  name              = var.windns_zone_name
+  replication_scope = "Domain"
+  zone_file         = "${var.windns_zone_name}.dns"
+}
+`
+
+const testAccResourceDNSZoneConfigWithRecord = `
+variable "windns_zone_name" {}
+
+resource "windns_zone" "z" {
+  name      = var.windns_zone_name
+  zone_file = "${var.windns_zone_name}.custom.dns"
+}
+
+resource "windns_record" "r" {
+  name      = "www"
+  zone_name = windns_zone.z.name
+  type      = "A"
+  records   = ["203.0.113.11"]
+}
+`
+
+func TestAccResourceDNSZone(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_zone_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSZoneConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_zone.z", "replication_scope", ""),
+					resource.TestCheckResourceAttr("windns_zone.z", "zone_file", fmt.Sprintf("%s.dns", os.Getenv("TF_VAR_windns_zone_name"))),
+				),
+			},
+			{
+				Config: testAccResourceDNSZoneConfigZoneFile,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_zone.z", "zone_file", fmt.Sprintf("%s.custom.dns", os.Getenv("TF_VAR_windns_zone_name"))),
+				),
+			},
+			{
+				Config:      testAccResourceDNSZoneConfigConflict,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`"zone_file": conflicts with replication_scope`),
+			},
+			{
+				ResourceName:      "windns_zone.z",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSZone_WithRecord(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_zone_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The zone of the record is created in the same run, so it is not on the server yet when planning.
+				Config: testAccResourceDNSZoneConfigWithRecord,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r", []string{"203.0.113.11"}, "A", true),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceDNSZoneConfigWithPtrRecord = `
+variable "windns_record_name" {}
+
+resource "windns_zone" "reverse" {
+  name = "100.51.198.in-addr.arpa"
+}
+
+resource "windns_record" "r" {
+  name          = var.windns_record_name
+  zone_name     = "example.com"
+  type          = "A"
+  records       = ["198.51.100.12"]
+  create_ptr    = true
+  ptr_zone_name = windns_zone.reverse.name
+}
+`
+
+func TestAccResourceDNSZone_WithPtrRecord(t *testing.T) {
+	envVars := []string{"TF_VAR_windns_record_name"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t, envVars) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The reverse zone is created in the same run, so it is not on the server yet when planning.
+				Config: testAccResourceDNSZoneConfigWithPtrRecord,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceDNSRecordExists("windns_record.r", []string{"198.51.100.12"}, "A", true),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/nrkno/terraform-provider-windns/internal/config"
+	"github.com/nrkno/terraform-provider-windns/internal/dnshelper"
+)
+
+// transferParams maps the attributes of windns_zone_transfer to the Set-DnsServerPrimaryZone parameters they manage.
+var transferParams = map[string]string{
+	"secure_secondaries": "SecureSecondaries",
+	"secondary_servers":  "SecondaryServers",
+	"notify":             "Notify",
+	"notify_servers":     "NotifyServers",
+}
+
+func resourceDNSZoneTransfer() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windns_zone_transfer` manages the zone transfer and notify settings of a primary zone in a Windows DNS Server.",
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		ReadContext:   resourceDNSZoneTransferRead,
+		CreateContext: resourceDNSZoneTransferCreate,
+		UpdateContext: resourceDNSZoneTransferUpdate,
+		DeleteContext: resourceDNSZoneTransferDelete,
+		Schema: map[string]*schema.Schema{
+			"zone_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The primary zone whose zone transfer settings are managed.",
+			},
+			"secure_secondaries": {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					dnshelper.TransferAnyServer,
+					dnshelper.TransferToZoneNameServer,
+					dnshelper.TransferToSecureServers,
+					dnshelper.NoTransfer,
+				}, false),
+				Description: "The servers the zone may be transferred to: `TransferAnyServer`, `TransferToZoneNameServer` for the name servers of the zone, `TransferToSecureServers` for the `secondary_servers`, or `NoTransfer`.",
+			},
+			"secondary_servers": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The IP addresses of the servers the zone may be transferred to. Required when `secure_secondaries` is `TransferToSecureServers`.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsIPAddress,
+				},
+			},
+			"notify": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					dnshelper.NoNotify,
+					dnshelper.Notify,
+					dnshelper.NotifyServers,
+				}, false),
+				Description: "The servers notified of changes to the zone: `NoNotify`, `Notify` for the name servers of the zone, or `NotifyServers` for the `notify_servers`. Read from the server when unset.",
+			},
+			"notify_servers": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The IP addresses of the servers notified of changes to the zone. Required when `notify` is `NotifyServers`.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsIPAddress,
+				},
+			},
+		},
+		CustomizeDiff: customizeDiffTransferServers,
+	}
+}
+
+// customizeDiffTransferServers requires the servers to be listed when transfers or notifications are limited to them.
+func customizeDiffTransferServers(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if d.Get("secure_secondaries").(string) == dnshelper.TransferToSecureServers && d.NewValueKnown("secondary_servers") &&
+		d.Get("secondary_servers").(*schema.Set).Len() == 0 {
+		return fmt.Errorf("secondary_servers must be set when secure_secondaries is %s", dnshelper.TransferToSecureServers)
+	}
+	if d.Get("notify").(string) == dnshelper.NotifyServers && d.NewValueKnown("notify_servers") &&
+		d.Get("notify_servers").(*schema.Set).Len() == 0 {
+		return fmt.Errorf("notify_servers must be set when notify is %s", dnshelper.NotifyServers)
+	}
+	return nil
+}
+
+// transferParamValue returns the value of attr in the form Set-DnsServerPrimaryZone takes it.
+func transferParamValue(d *schema.ResourceData, attr string) any {
+	if set, ok := d.Get(attr).(*schema.Set); ok {
+		return listToStringSlice(set.List())
+	}
+	return d.Get(attr)
+}
+
+func resourceDNSZoneTransferCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zoneName, err := dnshelper.SanitizeInputString("", d.Get("zone_name").(string))
+	if err != nil {
+		return diag.Errorf("error when mapping input data: %s", err)
+	}
+
+	// Every primary zone has transfer settings, so creating the resource adopts them and applies the configured fields.
+	changes := make(map[string]any)
+	for attr, param := range transferParams {
+		if _, ok := d.GetOk(attr); ok {
+			changes[param] = transferParamValue(d, attr)
+		}
+	}
+
+	transfer := dnshelper.ZoneTransfer{ZoneName: zoneName}
+	err = transfer.Update(ctx, meta.(*config.ProviderConf), changes)
+	if err != nil {
+		return diag.Errorf("error while updating transfer settings of zone %q: %s", zoneName, err)
+	}
+
+	d.SetId(zoneName)
+	return resourceDNSZoneTransferRead(ctx, d, meta)
+}
+
+func resourceDNSZoneTransferRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Id() == "" {
+		return nil
+	}
+
+	transfer, err := dnshelper.GetZoneTransfer(ctx, meta.(*config.ProviderConf), d.Id())
+	if err != nil {
+		if dnshelper.IsNotFound(err) {
+			// The zone no longer exists
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error while reading transfer settings of zone %q: %s", d.Id(), err)
+	}
+
+	_ = d.Set("zone_name", preserveCase(d.Get("zone_name").(string), d.Id()))
+	_ = d.Set("secure_secondaries", transfer.SecureSecondaries)
+	_ = d.Set("secondary_servers", transfer.SecondaryServers)
+	_ = d.Set("notify", transfer.Notify)
+	_ = d.Set("notify_servers", transfer.NotifyServers)
+
+	return nil
+}
+
+func resourceDNSZoneTransferUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	changes := make(map[string]any)
+	for attr, param := range transferParams {
+		if d.HasChange(attr) {
+			changes[param] = transferParamValue(d, attr)
+		}
+	}
+
+	transfer := dnshelper.ZoneTransfer{ZoneName: d.Id()}
+	err := transfer.Update(ctx, meta.(*config.ProviderConf), changes)
+	if err != nil {
+		return diag.Errorf("error while updating transfer settings of zone %q: %s", d.Id(), err)
+	}
+	return resourceDNSZoneTransferRead(ctx, d, meta)
+}
+
+// A primary zone always has transfer settings, so deleting the resource only removes them from the state.
+func resourceDNSZoneTransferDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccResourceDNSZoneTransferConfigSecure = `
+resource "windns_zone_transfer" "transfer" {
+  zone_name          = "example.com"
+  secure_secondaries = "TransferToSecureServers"
+  secondary_servers  = ["203.0.113.21", "203.0.113.22"]
+  notify             = "NotifyServers"
+  notify_servers     = ["203.0.113.21"]
+}
+`
+
+const testAccResourceDNSZoneTransferConfigNameServers = `
+resource "windns_zone_transfer" "transfer" {
+  zone_name          = "example.com"
+  secure_secondaries = "TransferToZoneNameServer"
+  notify             = "Notify"
+}
+`
+
+const testAccResourceDNSZoneTransferConfigMissingServers = `
+resource "windns_zone_transfer" "transfer" {
+  zone_name          = "example.com"
+  secure_secondaries = "TransferToSecureServers"
+}
+`
+
+func TestAccResourceDNSZoneTransfer_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDNSZoneTransferConfigSecure,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_zone_transfer.transfer", "secure_secondaries", "TransferToSecureServers"),
+					resource.TestCheckTypeSetElemAttr("windns_zone_transfer.transfer", "secondary_servers.*", "203.0.113.22"),
+					resource.TestCheckResourceAttr("windns_zone_transfer.transfer", "notify", "NotifyServers"),
+					resource.TestCheckResourceAttr("windns_zone_transfer.transfer", "notify_servers.#", "1"),
+				),
+			},
+			{
+				Config: testAccResourceDNSZoneTransferConfigNameServers,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windns_zone_transfer.transfer", "secure_secondaries", "TransferToZoneNameServer"),
+					resource.TestCheckResourceAttr("windns_zone_transfer.transfer", "secondary_servers.#", "0"),
+					resource.TestCheckResourceAttr("windns_zone_transfer.transfer", "notify", "Notify"),
+				),
+			},
+			{
+				ResourceName:      "windns_zone_transfer.transfer",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceDNSZoneTransfer_MissingServers(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceDNSZoneTransferConfigMissingServers,
+				ExpectError: regexp.MustCompile("secondary_servers must be set"),
+			},
+		},
+	})
+}
@@ -3,7 +3,10 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -17,6 +20,157 @@ func suppressCaseDiff(key, old, new string, d *schema.ResourceData) bool {
 	return strings.EqualFold(old, new)
 }
 
+// The zone apex can be written as either "@" or an empty name, while the server always returns "@".
+func suppressNameDiff(key, old, new string, d *schema.ResourceData) bool {
+	if dnshelper.IsApexName(old) && dnshelper.IsApexName(new) {
+		return true
+	}
+	return suppressDomainNameDiff(key, old, new, d)
+}
+
+// Internationalized labels can be written either in Unicode or as A-labels, e.g. bücher or xn--bcher-kva.
+func suppressDomainNameDiff(key, old, new string, d *schema.ResourceData) bool {
+	return dnshelper.SameDomainName(old, new)
+}
+
+// validateDomainName rejects names with invalid internationalized labels at plan time. Other invalid characters are
+// rejected when the name is sanitized.
+func validateDomainName(v any, key string) ([]string, []error) {
+	if _, err := dnshelper.ToASCIIName(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", key, err)}
+	}
+	return nil, nil
+}
+
+// validateRecordType rejects SOA records, which are managed with windns_zone_soa as every zone has exactly one.
+func validateRecordType(v any, key string) ([]string, []error) {
+	if strings.EqualFold(v.(string), dnshelper.RecordTypeSOA) {
+		return nil, []error{fmt.Errorf("%s: SOA records cannot be managed as records, as every zone has exactly one SOA record, which cannot be removed. Use windns_zone_soa to manage the SOA parameters of the zone", key)}
+	}
+	return nil, nil
+}
+
+// validateTTL accepts a TTL of at least one second, written in seconds or with units, see dnshelper.ParseTTL.
+func validateTTL(v any, key string) ([]string, []error) {
+	ttl, err := dnshelper.ParseTTL(v.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", key, err)}
+	}
+	if ttl < 1 {
+		return nil, []error{fmt.Errorf("%s: the TTL must be at least one second", key)}
+	}
+	return nil, nil
+}
+
+// canonicalTTL stores a TTL in seconds, so TTLs written with units do not differ from the TTL read from the server.
+// TTLs that cannot be parsed are stored as written, and rejected by validateTTL.
+func canonicalTTL(v any) string {
+	ttl, err := dnshelper.ParseTTL(v.(string))
+	if err != nil {
+		return v.(string)
+	}
+	return dnshelper.FormatTTL(ttl)
+}
+
+// asciiName returns name with its internationalized labels as A-labels, or name as is when it is not valid.
+func asciiName(name string) string {
+	if ascii, err := dnshelper.ToASCIIName(name); err == nil {
+		return ascii
+	}
+	return name
+}
+
+// DNS names and types are case-insensitive, so only changes to more than their casing replace a record.
+func changedIgnoringCase(ctx context.Context, old, new, meta any) bool {
+	return !suppressCaseDiff("", old.(string), new.(string), nil)
+}
+
+func nameChanged(ctx context.Context, old, new, meta any) bool {
+	return !suppressNameDiff("", old.(string), new.(string), nil)
+}
+
+func domainNameChanged(ctx context.Context, old, new, meta any) bool {
+	return !suppressDomainNameDiff("", old.(string), new.(string), nil)
+}
+
+// preserveCase returns current, the value in the state, when read is the same name in another casing, so the casing
+// returned by the server does not replace the configured one.
+func preserveCase(current, read string) string {
+	if strings.EqualFold(current, read) {
+		return current
+	}
+	return read
+}
+
+// preserveName returns current, the value in the state, when read is the same domain name in another casing or with
+// its internationalized labels written as A-labels, which is how the server returns them. Other names are returned
+// with their A-labels in Unicode.
+func preserveName(current, read string) string {
+	if dnshelper.SameDomainName(current, read) {
+		return current
+	}
+	return dnshelper.ToUnicodeName(read)
+}
+
+// The server returns domain names fully qualified, with a trailing `.`.
+func suppressFQDNDiff(key, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(strings.TrimSuffix(old, "."), strings.TrimSuffix(new, "."))
+}
+
+// The server returns IP addresses in their canonical form, e.g. IPv6 addresses in lower case and with zeros compressed.
+func suppressIPAddressDiff(key, old, new string, d *schema.ResourceData) bool {
+	oldIP, newIP := net.ParseIP(old), net.ParseIP(new)
+	if oldIP == nil || newIP == nil {
+		return old == new
+	}
+	return oldIP.Equal(newIP)
+}
+
+// The server keeps no order among the addresses of a root hint, and returns them in their canonical form.
+func suppressIPAddressListDiff(key, old, new string, d *schema.ResourceData) bool {
+	// For a list, the key is path to the element, rather than the list.
+	if lastDotIndex := strings.LastIndex(key, "."); lastDotIndex != -1 {
+		key = key[:lastDotIndex]
+	}
+	oldData, newData := d.GetChange(key)
+	oldAddresses := listToStringSlice(oldData.([]interface{}))
+	newAddresses := listToStringSlice(newData.([]interface{}))
+	if len(oldAddresses) == 0 || len(oldAddresses) != len(newAddresses) {
+		return false
+	}
+	added, removed := diffIPAddresses(oldAddresses, newAddresses)
+	return len(added) == 0 && len(removed) == 0
+}
+
+// validateUniqueIPAddresses rejects the same IP address listed more than once, which the server would only keep once.
+func validateUniqueIPAddresses(key string, addresses []interface{}) error {
+	seen := make([]net.IP, 0, len(addresses))
+	for _, v := range addresses {
+		ip := net.ParseIP(v.(string))
+		if ip == nil {
+			continue
+		}
+		if slices.ContainsFunc(seen, ip.Equal) {
+			return fmt.Errorf("%s lists %s more than once", key, v)
+		}
+		seen = append(seen, ip)
+	}
+	return nil
+}
+
+// The server increments the serial number on every change to the zone, so a serial number behind the server's is not a change.
+func suppressSerialDiff(key, old, new string, d *schema.ResourceData) bool {
+	oldSerial, err := strconv.ParseInt(old, 10, 64)
+	if err != nil {
+		return false
+	}
+	newSerial, err := strconv.ParseInt(new, 10, 64)
+	if err != nil {
+		return false
+	}
+	return newSerial <= oldSerial
+}
+
 func suppressRecordDiff(key, old, new string, d *schema.ResourceData) bool {
 	// For a list, the key is path to the element, rather than the list.
 	// E.g. "windns_record.2.records.0"
@@ -31,25 +185,95 @@ func suppressRecordDiff(key, old, new string, d *schema.ResourceData) bool {
 	}
 
 	rrType := d.Get("type").(string)
-	oldRecords := setToStringSlice(oldData.(*schema.Set))
-	newRecords := setToStringSlice(newData.(*schema.Set))
+	oldRecords := listToStringSlice(oldData.([]interface{}))
+	newRecords := listToStringSlice(newData.([]interface{}))
 
 	// prevent (known after apply) to be ignored
 	if len(oldRecords) == 0 && len(newRecords) == 0 {
 		return false
 	}
 
-	return suppressRecordDiffForType(oldRecords, newRecords, rrType)
+	// Values are trimmed before they are added, so whitespace around them is not a change.
+	if d.Get("trim_whitespace").(bool) {
+		for i, v := range oldRecords {
+			oldRecords[i] = strings.TrimSpace(v)
+		}
+		for i, v := range newRecords {
+			newRecords[i] = strings.TrimSpace(v)
+		}
+	}
+
+	// Explicitly split TXT records may be written with any spacing and escaping of their strings.
+	if strings.EqualFold(rrType, dnshelper.RecordTypeTXT) && d.Get("explicit_txt_segments").(bool) {
+		for i, v := range oldRecords {
+			oldRecords[i] = dnshelper.NormalizeTXTStrings(v)
+		}
+		for i, v := range newRecords {
+			newRecords[i] = dnshelper.NormalizeTXTStrings(v)
+		}
+	}
+
+	return suppressRecordDiffForType(oldRecords, newRecords, rrType, d.Get("ordered_records").(bool))
 }
 
-func suppressRecordDiffForType(oldRecords, newRecords []string, rrType string) bool {
-	slices.Sort(oldRecords)
-	slices.Sort(newRecords)
+// configuredRecords returns the records of a windns_record resource, trimmed as they are added when trim_whitespace
+// is set.
+func configuredRecords(d *schema.ResourceData) []string {
+	records := listToStringSlice(d.Get("records").([]interface{}))
+	if d.Get("trim_whitespace").(bool) {
+		for i, v := range records {
+			records[i] = strings.TrimSpace(v)
+		}
+	}
+	return records
+}
 
-	if rrType == dnshelper.RecordTypePTR || rrType == dnshelper.RecordTypeCNAME {
-		return suppressDotDiff(oldRecords, newRecords)
+// txtSegments returns the records of a TXT record as split into character-strings on the server, each written as
+// its quoted character-strings. It is empty for other types.
+func txtSegments(record *dnshelper.Record) []string {
+	if !strings.EqualFold(record.RecordType, dnshelper.RecordTypeTXT) {
+		return nil
 	}
-	return suppressListCaseDiff(oldRecords, newRecords)
+	return dnshelper.RecordValues(record.RecordType, record.Records, true)
+}
+
+// Unless ordered, the records of a resource form an unordered set, and the server does not return them in the order
+// they were added, so the order of the values is ignored. The order of the fields within multi-field record data is
+// significant.
+//
+// The server returns domain names fully qualified, with a trailing `.` (e.g. the data of PTR and CNAME records, or the
+// host name in AFSDB records), which is the canonical form stored in the state. Both sides are normalized to that form
+// before comparing, so domain names may be configured with or without the trailing `.`.
+//
+// Whitespace around the data is insignificant, except for TXT data, which is compared exactly, as its case and
+// whitespace may be significant, and is never altered.
+//
+// Unless ordered, a value listed more than once is added once, as the server keeps a single copy, so duplicates are
+// not a change either.
+func suppressRecordDiffForType(oldRecords, newRecords []string, rrType string, ordered bool) bool {
+	caseSensitive := dnshelper.IsCaseSensitiveRecordType(rrType)
+	normalize := func(records []string) []string {
+		normalized := make([]string, 0, len(records))
+		for _, v := range records {
+			normalized = append(normalized, dnshelper.NormalizeRecordData(rrType, v))
+		}
+		if !ordered {
+			slices.SortFunc(normalized, func(a, b string) int {
+				if caseSensitive {
+					return strings.Compare(a, b)
+				}
+				return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+			})
+			normalized = slices.CompactFunc(normalized, func(a, b string) bool {
+				return a == b || (!caseSensitive && strings.EqualFold(a, b))
+			})
+		}
+		return normalized
+	}
+	if caseSensitive {
+		return slices.Equal(normalize(oldRecords), normalize(newRecords))
+	}
+	return suppressListCaseDiff(normalize(oldRecords), normalize(newRecords))
 }
 
 // Get-DNSResourceRecord always returns AAAA records in lower case.
@@ -60,24 +284,19 @@ func suppressListCaseDiff(oldRecords, newRecords []string) bool {
 	})
 }
 
-// Get-DNSResourceRecord always adds a `.` after the PTR and CNAME record types.
-// To avoid change if the user did not add it, we need to add it before we compare.
-func suppressDotDiff(oldRecords, newRecords []string) bool {
-	var newRecordsWithDot []string
-
-	for _, v := range newRecords {
-		if strings.HasSuffix(v, ".") {
-			newRecordsWithDot = append(newRecordsWithDot, v)
-		} else {
-			newRecordsWithDot = append(newRecordsWithDot, fmt.Sprintf("%s.", v))
-		}
+// recordFQDN joins a record name and its zone into a fully qualified domain name
+// without the trailing dot. The apex of the zone is denoted by "@" or an empty name.
+func recordFQDN(name, zoneName string) string {
+	zoneName = strings.TrimSuffix(zoneName, ".")
+	if dnshelper.IsApexName(name) {
+		return zoneName
 	}
-	return slices.Equal(oldRecords, newRecordsWithDot)
+	return fmt.Sprintf("%s.%s", name, zoneName)
 }
 
-func setToStringSlice(d *schema.Set) []string {
+func listToStringSlice(l []interface{}) []string {
 	var data []string
-	for _, v := range d.List() {
+	for _, v := range l {
 		data = append(data, fmt.Sprintf("%s", v))
 	}
 	return data
@@ -2,7 +2,10 @@
 
 package provider
 
-import "testing"
+import (
+	"context"
+	"testing"
+)
 
 func Test_suppressRecordDiffForType(t *testing.T) {
 	tests := []struct {
@@ -45,17 +48,541 @@ func Test_suppressRecordDiffForType(t *testing.T) {
 		{
 			"test-dot-cname", "CNAME", []string{"example-host.example.com."}, []string{"example-host.example.com"}, true,
 		},
+		{
+			"test-both-dot-cname", "CNAME", []string{"example-host.example.com."}, []string{"example-host.example.com."}, true,
+		},
+		{
+			"test-old-without-dot-cname", "CNAME", []string{"example-host.example.com"}, []string{"example-host.example.com."}, true,
+		},
+		{
+			"test-case-dot-cname", "CNAME", []string{"example-host.example.com."}, []string{"Example-Host.Example.com"}, true,
+		},
+		{
+			"test-other-cname", "CNAME", []string{"example-host.example.com."}, []string{"example-host.example.com.example.com"}, false,
+		},
 		// rrType PTR test cases
 		{
 			"test-dot-ptr", "PTR", []string{"example-host.example.com."}, []string{"example-host.example.com"}, true,
 		},
+		{
+			"test-both-dot-ptr", "PTR", []string{"example-host.example.com."}, []string{"example-host.example.com."}, true,
+		},
+		{
+			"test-old-without-dot-ptr", "PTR", []string{"example-host.example.com"}, []string{"example-host.example.com."}, true,
+		},
+		{
+			"test-case-ptr", "PTR", []string{"example-host.example.com."}, []string{"Example-Host.example.com"}, true,
+		},
+		{
+			"test-changed-ptr", "PTR", []string{"example-host.example.com."}, []string{"other-host.example.com"}, false,
+		},
+		{
+			"test-whitespace-ptr", "PTR", []string{"a.example.com."}, []string{" a.example.com "}, true,
+		},
+		{
+			"test-duplicate-a", "A", []string{"203.0.113.11", "203.0.113.12"}, []string{"203.0.113.12", "203.0.113.11", "203.0.113.12"}, true,
+		},
+		{
+			"test-duplicate-casemix-ptr", "PTR", []string{"a.example.com."}, []string{"a.example.com", "A.example.com."}, true,
+		},
+		{
+			"test-multiple-casemix-ptr", "PTR", []string{"a.example.com.", "B.example.com."}, []string{"b.example.com", "A.example.com"}, true,
+		},
+		// rrType TXT test cases
+		{
+			"test-multiple-unsorted-txt", "TXT", []string{"v=spf1 -all", "google-site-verification=abc", "key=value"}, []string{"key=value", "v=spf1 -all", "google-site-verification=abc"}, true,
+		},
+		{
+			"test-multiple-changed-txt", "TXT", []string{"v=spf1 -all", "google-site-verification=abc", "key=value"}, []string{"key=value", "v=spf1 -all", "google-site-verification=def"}, false,
+		},
+		{
+			"test-case-changed-txt", "TXT", []string{"Token=AbC"}, []string{"token=abc"}, false,
+		},
+		{
+			"test-mixed-case-special-txt", "txt", []string{`v=DKIM1; k=rsa; p=MIGf+/AbC=="quoted" \ $Var`, "B", "a"}, []string{"a", "B", `v=DKIM1; k=rsa; p=MIGf+/AbC=="quoted" \ $Var`}, true,
+		},
+		{
+			"test-whitespace-txt", "TXT", []string{"v=spf1 -all"}, []string{"v=spf1 -all "}, false,
+		},
+		// rrType AFSDB test cases
+		{
+			"test-dot-afsdb", "AFSDB", []string{"1 afsdb.example.com."}, []string{"1 afsdb.example.com"}, true,
+		},
+		{
+			"test-subtype-afsdb", "AFSDB", []string{"1 afsdb.example.com."}, []string{"2 afsdb.example.com."}, false,
+		},
+		{
+			"test-field-order-afsdb", "AFSDB", []string{"1 afsdb.example.com."}, []string{"afsdb.example.com. 1"}, false,
+		},
+		// rrType RP test cases
+		{
+			"test-dot-rp", "RP", []string{"admin.example.com. txt.example.com."}, []string{"admin.example.com txt.example.com"}, true,
+		},
+		{
+			"test-multiple-rp", "RP", []string{"b.example.com. txt.example.com.", "a.example.com. txt.example.com."}, []string{"a.example.com txt.example.com", "b.example.com. txt.example.com"}, true,
+		},
+		// rrType RT test cases
+		{
+			"test-dot-rt", "RT", []string{"10 relay.example.com."}, []string{"10 Relay.example.com"}, true,
+		},
+		{
+			"test-preference-rt", "RT", []string{"10 relay.example.com."}, []string{"20 relay.example.com."}, false,
+		},
+		// rrType NS test cases
+		{
+			"test-dot-ns", "NS", []string{"ns1.sub.example.com.", "ns2.sub.example.com."}, []string{"ns2.sub.example.com", "NS1.sub.example.com"}, true,
+		},
+		// rrType MB, MG, MR and MINFO test cases
+		{
+			"test-dot-mb", "MB", []string{"mail.example.com."}, []string{"mail.example.com"}, true,
+		},
+		{
+			"test-dot-mg", "MG", []string{"Group.example.com."}, []string{"group.example.com"}, true,
+		},
+		{
+			"test-changed-mr", "MR", []string{"old.example.com."}, []string{"new.example.com"}, false,
+		},
+		{
+			"test-dot-minfo", "MINFO", []string{"admin.example.com. errors.example.com."}, []string{"admin.example.com errors.example.com"}, true,
+		},
+		// rrType WKS test cases
+		{
+			"test-service-order-wks", "WKS", []string{"203.0.113.11 TCP smtp ftp"}, []string{"203.0.113.11 tcp ftp smtp"}, true,
+		},
+		{
+			"test-protocol-wks", "WKS", []string{"203.0.113.11 UDP domain"}, []string{"203.0.113.11 tcp domain"}, false,
+		},
+		{
+			"test-service-added-wks", "WKS", []string{"203.0.113.11 TCP smtp"}, []string{"203.0.113.11 tcp smtp ftp"}, false,
+		},
+		// rrType DS test cases
+		{
+			"test-digest-case-ds", "DS", []string{"12345 RsaSha256 Sha256 49FD46E6C4B45C55D4AC"}, []string{"12345 RsaSha256 Sha256 49fd46e6c4b45c55d4ac"}, true,
+		},
+		{
+			"test-numeric-ds", "DS", []string{"12345 RsaSha256 Sha256 49FD46E6C4B45C55D4AC"}, []string{"12345 8 2 49FD46E6C4B45C55D4AC"}, true,
+		},
+		{
+			"test-algorithm-ds", "DS", []string{"12345 RsaSha256 Sha256 49FD46E6C4B45C55D4AC"}, []string{"12345 13 2 49FD46E6C4B45C55D4AC"}, false,
+		},
+		// rrType ATMA test cases
+		{
+			"test-numeric-atma", "ATMA", []string{"1 358400123456"}, []string{"E164 358400123456"}, true,
+		},
+		{
+			"test-format-case-atma", "ATMA", []string{"E164 358400123456"}, []string{"e164 358400123456"}, true,
+		},
+		{
+			"test-format-atma", "ATMA", []string{"1 358400123456"}, []string{"NSAP 358400123456"}, false,
+		},
+		// rrType CERT test cases
+		{
+			"test-mnemonic-cert", "CERT", []string{"1 0 8 MIIDdzCCAl+gAwIBAgIU"}, []string{"PKIX 0 8 MIIDdzCCAl+gAwIBAgIU"}, true,
+		},
+		{
+			"test-split-cert", "CERT", []string{"1 0 8 MIIDdzCCAl+gAwIBAgIU"}, []string{"PKIX 0 8 MIIDdzCC Al+gAwIBAgIU"}, true,
+		},
+		{
+			"test-case-cert", "CERT", []string{"1 0 8 MIIDdzCCAl+gAwIBAgIU"}, []string{"1 0 8 miiddzccal+gawibagiu"}, false,
+		},
+		// rrType X25 test cases
+		{
+			"test-x25", "X25", []string{"311061700956"}, []string{"311061700956"}, true,
+		},
+		{
+			"test-changed-x25", "X25", []string{"311061700956"}, []string{"311061700957"}, false,
+		},
+		// rrType ISDN test cases
+		{
+			"test-isdn", "ISDN", []string{"150862028003217 004"}, []string{"150862028003217 004"}, true,
+		},
+		{
+			"test-isdn-without-subaddress", "ISDN", []string{"150862028003217"}, []string{"150862028003217"}, true,
+		},
+		{
+			"test-isdn-subaddress-added", "ISDN", []string{"150862028003217"}, []string{"150862028003217 004"}, false,
+		},
+		{
+			"test-multiple-isdn", "ISDN", []string{"150862028003217 004", "150862028003218"}, []string{"150862028003218", "150862028003217 004"}, true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := suppressRecordDiffForType(tt.oldRecords, tt.newRecords, tt.rrType); got != tt.want {
+			if got := suppressRecordDiffForType(tt.oldRecords, tt.newRecords, tt.rrType, false); got != tt.want {
 				t.Errorf("suppressRecordDiffForType() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_suppressRecordDiffForType_Ordered(t *testing.T) {
+	tests := []struct {
+		name       string
+		rrType     string
+		oldRecords []string
+		newRecords []string
+		want       bool
+	}{
+		{
+			"test-same-order", "A", []string{"203.0.113.11", "203.0.113.12"}, []string{"203.0.113.11", "203.0.113.12"}, true,
+		},
+		{
+			"test-changed-order", "A", []string{"203.0.113.12", "203.0.113.11"}, []string{"203.0.113.11", "203.0.113.12"}, false,
+		},
+		{
+			"test-casemix-ipv6", "AAAA", []string{"2001:db8::2", "2001:db8::1"}, []string{"2001:DB8::2", "2001:db8::1"}, true,
+		},
+		{
+			"test-changed-order-afsdb", "AFSDB", []string{"1 b.example.com.", "1 a.example.com."}, []string{"1 a.example.com", "1 b.example.com"}, false,
+		},
+		{
+			"test-dot-afsdb", "AFSDB", []string{"1 b.example.com.", "1 a.example.com."}, []string{"1 b.example.com", "1 a.example.com"}, true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suppressRecordDiffForType(tt.oldRecords, tt.newRecords, tt.rrType, true); got != tt.want {
+				t.Errorf("suppressRecordDiffForType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_suppressNameDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want bool
+	}{
+		{"test-apex-empty", "@", "", true},
+		{"test-apex-at", "@", "@", true},
+		{"test-case", "WWW", "www", true},
+		{"test-apex-vs-name", "@", "www", false},
+		{"test-empty-vs-name", "", "www", false},
+		{"test-idn", "xn--bcher-kva", "bücher", true},
+		{"test-idn-case", "Bücher.sub", "xn--bcher-kva.SUB", true},
+		{"test-idn-different", "xn--bcher-kva", "bucher", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suppressNameDiff("name", tt.old, tt.new, nil); got != tt.want {
+				t.Errorf("suppressNameDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_nameChanged(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want bool
+	}{
+		{"test-same", "www", "www", false},
+		{"test-case", "WWW", "www", false},
+		{"test-apex", "@", "", false},
+		{"test-rename", "www", "web", true},
+		{"test-apex-vs-name", "@", "www", true},
+		{"test-idn", "xn--bcher-kva", "bücher", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nameChanged(context.Background(), tt.old, tt.new, nil); got != tt.want {
+				t.Errorf("nameChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_changedIgnoringCase(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want bool
+	}{
+		{"test-same", "example.com", "example.com", false},
+		{"test-case", "Example.COM", "example.com", false},
+		{"test-change", "example.com", "example.org", true},
+		{"test-apex", "@", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := changedIgnoringCase(context.Background(), tt.old, tt.new, nil); got != tt.want {
+				t.Errorf("changedIgnoringCase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_preserveCase(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		read    string
+		want    string
+	}{
+		{"test-same", "www", "www", "www"},
+		{"test-mixed-case", "Www", "www", "Www"},
+		{"test-upper-case", "WWW", "www", "WWW"},
+		{"test-different", "www", "web", "web"},
+		{"test-import", "", "www", "www"},
+		{"test-apex", "", "@", "@"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := preserveCase(tt.current, tt.read); got != tt.want {
+				t.Errorf("preserveCase() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_preserveName(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		read    string
+		want    string
+	}{
+		{"test-same", "www", "www", "www"},
+		{"test-mixed-case", "Www", "www", "Www"},
+		{"test-different", "www", "web", "web"},
+		{"test-idn-unicode", "bücher", "xn--bcher-kva", "bücher"},
+		{"test-idn-a-label", "xn--bcher-kva", "xn--bcher-kva", "xn--bcher-kva"},
+		{"test-idn-import", "", "xn--bcher-kva.sub", "bücher.sub"},
+		{"test-apex", "", "@", "@"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := preserveName(tt.current, tt.read); got != tt.want {
+				t.Errorf("preserveName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validateDomainName(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"test-ascii", "www", false},
+		{"test-service", "_sip._tcp", false},
+		{"test-idn", "bücher.sub", false},
+		{"test-idn-upper-case", "BÜCHER", false},
+		{"test-idn-leading-combining-mark", "\u0301bcher", true},
+		{"test-idn-mixed-direction", "a\u05d0", true},
+		{"test-idn-trailing-hyphen", "bücher-", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := validateDomainName(tt.value, "name")
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("validateDomainName() errors = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateRecordType(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"test-a", "A", false},
+		{"test-ns", "NS", false},
+		{"test-soa", "SOA", true},
+		{"test-soa-lower-case", "soa", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := validateRecordType(tt.value, "type")
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("validateRecordType() errors = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"test-seconds", "3600", "3600", false},
+		{"test-hours", "1h", "3600", false},
+		{"test-minutes", "30m", "1800", false},
+		{"test-days", "1d", "86400", false},
+		{"test-combined", "1h30m", "5400", false},
+		{"test-upper-case", "1H", "3600", false},
+		{"test-zero", "0", "0", true},
+		{"test-zero-units", "0h", "0", true},
+		{"test-empty", "", "", true},
+		{"test-decimal", "1.5h", "1.5h", true},
+		{"test-unknown-unit", "1y", "1y", true},
+		{"test-too-long", "100000w", "100000w", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := validateTTL(tt.value, "ttl")
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("validateTTL() errors = %v, wantErr %v", errs, tt.wantErr)
+			}
+			if got := canonicalTTL(tt.value); got != tt.want {
+				t.Errorf("canonicalTTL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_upgradeRecordStateV0(t *testing.T) {
+	tests := []struct {
+		name  string
+		state map[string]any
+		want  any
+	}{
+		{"test-ttl", map[string]any{"ttl": float64(3600)}, "3600"},
+		{"test-mixed-ttls", map[string]any{"ttl": float64(0)}, "0"},
+		{"test-no-ttl", map[string]any{"ttl": nil}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := upgradeRecordStateV0(context.Background(), tt.state, nil)
+			if err != nil {
+				t.Fatalf("upgradeRecordStateV0() error = %s", err)
+			}
+			if got["ttl"] != tt.want {
+				t.Errorf("upgradeRecordStateV0() ttl = %#v, want %#v", got["ttl"], tt.want)
+			}
+		})
+	}
+}
+
+func Test_suppressFQDNDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want bool
+	}{
+		{"test-dot", "dc01.example.com.", "dc01.example.com", true},
+		{"test-case", "DC01.example.com.", "dc01.example.com.", true},
+		{"test-changed", "dc01.example.com.", "dc02.example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suppressFQDNDiff("primary_server", tt.old, tt.new, nil); got != tt.want {
+				t.Errorf("suppressFQDNDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_suppressIPAddressDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want bool
+	}{
+		{"test-ipv4", "203.0.113.11", "203.0.113.11", true},
+		{"test-ipv4-changed", "203.0.113.11", "203.0.113.12", false},
+		{"test-ipv6-case", "2001:db8::53", "2001:DB8::53", true},
+		{"test-ipv6-zeros", "2001:db8::53", "2001:db8:0:0:0:0:0:53", true},
+		{"test-count", "2", "3", false},
+		{"test-unknown", "", "203.0.113.11", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suppressIPAddressDiff("master_servers.0", tt.old, tt.new, nil); got != tt.want {
+				t.Errorf("suppressIPAddressDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validateUniqueIPAddresses(t *testing.T) {
+	tests := []struct {
+		name      string
+		addresses []interface{}
+		wantErr   bool
+	}{
+		{"test-unique", []interface{}{"203.0.113.11", "203.0.113.12", "2001:db8::53"}, false},
+		{"test-duplicate", []interface{}{"203.0.113.11", "203.0.113.12", "203.0.113.11"}, true},
+		{"test-duplicate-ipv6", []interface{}{"2001:db8::53", "2001:DB8:0::53"}, true},
+		{"test-empty", []interface{}{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateUniqueIPAddresses("master_servers", tt.addresses); (err != nil) != tt.wantErr {
+				t.Errorf("validateUniqueIPAddresses() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_suppressSerialDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want bool
+	}{
+		{"test-equal", "2024010101", "2024010101", true},
+		{"test-server-ahead", "2024010105", "2024010101", true},
+		{"test-config-ahead", "2024010101", "2024010200", false},
+		{"test-unknown", "", "2024010101", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suppressSerialDiff("serial_number", tt.old, tt.new, nil); got != tt.want {
+				t.Errorf("suppressSerialDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_recordFQDN(t *testing.T) {
+	tests := []struct {
+		name     string
+		rrName   string
+		zoneName string
+		want     string
+	}{
+		{"test-name", "www", "example.com", "www.example.com"},
+		{"test-zone-trailing-dot", "www", "example.com.", "www.example.com"},
+		{"test-apex-at", "@", "example.com", "example.com"},
+		{"test-apex-empty", "", "example.com", "example.com"},
+		{"test-multi-label", "a.b", "example.com", "a.b.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recordFQDN(tt.rrName, tt.zoneName); got != tt.want {
+				t.Errorf("recordFQDN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
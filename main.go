@@ -21,8 +21,8 @@ func main() {
 	opts := &plugin.ServeOpts{
 		Debug: debugMode,
 
-		ProviderAddr: "registry.terraform.io/nrkno/windns",
-		ProviderFunc: provider.Provider(version),
+		ProviderAddr:     "registry.terraform.io/nrkno/windns",
+		GRPCProviderFunc: provider.ProviderServer(version),
 	}
 
 	plugin.Serve(opts)